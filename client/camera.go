@@ -0,0 +1,37 @@
+package main
+
+// Camera 盤面のうち画面に表示する範囲
+type Camera struct {
+	// 表示範囲の左上の盤面上の座標
+	X int
+	Y int
+	// 表示範囲の大きさ
+	Width  int
+	Height int
+}
+
+// newFollowCamera targetが中央に来るように、盤面の外を表示しない範囲で表示範囲を決める
+// 画面が盤面より大きい場合は盤面全体を表示する
+func newFollowCamera(target Position, boardWidth, boardHeight, viewWidth, viewHeight int) Camera {
+	width := max(min(boardWidth, viewWidth), 0)
+	height := max(min(boardHeight, viewHeight), 0)
+	return Camera{
+		X:      clamp(target.X-width/2, 0, boardWidth-width),
+		Y:      clamp(target.Y-height/2, 0, boardHeight-height),
+		Width:  width,
+		Height: height,
+	}
+}
+
+// ToScreen 盤面上の座標を画面上の座標に変換する。表示範囲の外ならfalseを返す
+func (c Camera) ToScreen(position Position) (int, int, bool) {
+	x, y := position.X-c.X, position.Y-c.Y
+	if x < 0 || x >= c.Width || y < 0 || y >= c.Height {
+		return 0, 0, false
+	}
+	return x, y, true
+}
+
+func clamp(value, lower, upper int) int {
+	return max(lower, min(value, upper))
+}
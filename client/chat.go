@@ -0,0 +1,107 @@
+package main
+
+import (
+	"log"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/shibayu36/terminal-shooter/shared"
+	"google.golang.org/protobuf/proto"
+)
+
+// チャット欄に残す行数
+const chatLogSize = 5
+
+// ChatLog 受信したチャットを古い順に一定数だけ保持する
+type ChatLog struct {
+	lines []string
+}
+
+// 新しいChatLog構造体を作成
+func NewChatLog() *ChatLog {
+	return &ChatLog{
+		lines: nil,
+	}
+}
+
+// 行を追加する。保持できる行数を超えたら古いものから捨てる
+func (l *ChatLog) Add(line string) {
+	l.lines = append(l.lines, line)
+	if len(l.lines) > chatLogSize {
+		l.lines = l.lines[len(l.lines)-chatLogSize:]
+	}
+}
+
+// 古い順の行の一覧
+func (l *ChatLog) Lines() []string {
+	return l.lines
+}
+
+// チャットの入力を始める
+func (g *Game) startChat(scope shared.ChatScope) {
+	g.chatting = true
+	g.chatScope = scope
+	g.chatInput = nil
+}
+
+// チャット入力中のキー入力を処理する
+// Enterで送信し、Escで入力をやめる
+func (g *Game) handleChatKey(ev *tcell.EventKey) {
+	//nolint:exhaustive
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		g.chatting = false
+	case tcell.KeyEnter:
+		g.chatting = false
+		if len(g.chatInput) > 0 {
+			g.sendChat(string(g.chatInput), g.chatScope)
+		}
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(g.chatInput) > 0 {
+			g.chatInput = g.chatInput[:len(g.chatInput)-1]
+		}
+	case tcell.KeyRune:
+		g.chatInput = append(g.chatInput, ev.Rune())
+	}
+}
+
+// チャットをサーバーに送信する。送信者はサーバーが埋める
+func (g *Game) sendChat(text string, scope shared.ChatScope) {
+	chatMessage := &shared.ChatMessage{
+		Text:  text,
+		Scope: scope,
+	}
+	payload, err := proto.Marshal(chatMessage)
+	if err != nil {
+		log.Printf("Failed to marshal chat message: %v", err)
+		return
+	}
+
+	if token := g.mqtt.Publish("chat", 0, false, payload); token.Wait() && token.Error() != nil {
+		log.Printf("Failed to publish chat message: %v", token.Error())
+	}
+}
+
+// 受信したチャットをチャット欄に表示する文字列にする
+func (g *Game) chatText(chatMessage *shared.ChatMessage) string {
+	if chatMessage.GetSystem() {
+		return "* " + chatMessage.GetText()
+	}
+
+	name := chatMessage.GetSenderName()
+	if name == "" {
+		name = g.playerName(chatMessage.GetSenderId())
+	}
+	if chatMessage.GetScope() == shared.ChatScope_CHAT_SCOPE_TEAM {
+		return "[Team] " + name + ": " + chatMessage.GetText()
+	}
+	return name + ": " + chatMessage.GetText()
+}
+
+// チャットの入力欄に表示する文字列
+func (g *Game) chatInputText() string {
+	prefix := "Say: "
+	if g.chatScope == shared.ChatScope_CHAT_SCOPE_TEAM {
+		prefix = "Say (team): "
+	}
+	return prefix + string(g.chatInput) + "_"
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+// 設定ファイルを置くディレクトリの名前。OSごとのユーザー設定ディレクトリの下に置く
+const configDirName = "terminal-shooter"
+
+// fileConfig 設定ファイルの内容
+// 空の項目はフラグの初期値を使い、コマンドラインで指定したフラグは設定ファイルより優先する
+// サーバーにルームが無いのでルームの項目は持たない。別の部屋で遊ぶ場合は接続先のブローカーを変える
+type fileConfig struct {
+	Broker   string `json:"broker"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Name     string `json:"name"`
+	Theme    string `json:"theme"`
+	// 操作の名前からキーの名前への対応。指定しなかった操作は初期値のキーを使う
+	Keys map[string]string `json:"keys"`
+}
+
+// 設定ファイルの初期の場所。ユーザー設定ディレクトリが分からなければ設定ファイルを使わない
+func defaultConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, configDirName, "config.json")
+}
+
+// loadConfigFile 設定ファイルを読み込む
+// 初期の場所の設定ファイルは無くてもよいが、requiredの場合は無ければエラーにする
+func loadConfigFile(path string, required bool) (fileConfig, error) {
+	var config fileConfig
+	if path == "" {
+		return config, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return config, nil
+	}
+	if err != nil {
+		return config, errors.Wrapf(err, "failed to read config file: %s", path)
+	}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return config, errors.Wrapf(err, "failed to parse config file: %s", path)
+	}
+	return config, nil
+}
+
+// parseKeysFlag "up=w,down=s"の形式のフラグを操作の名前からキーの名前への対応にする
+func parseKeysFlag(value string) (map[string]string, error) {
+	keys := make(map[string]string)
+	if value == "" {
+		return keys, nil
+	}
+	for _, pair := range strings.Split(value, ",") {
+		action, key, ok := strings.Cut(pair, "=")
+		if !ok || action == "" || key == "" {
+			return nil, errors.Newf("invalid key binding: %q (expected action=key)", pair)
+		}
+		keys[strings.TrimSpace(action)] = key
+	}
+	return keys, nil
+}
+
+// parseOptions フラグと設定ファイルから起動時の設定を作る
+// 優先順位はコマンドラインのフラグ、設定ファイル、フラグの初期値の順
+func parseOptions(flagSet *flag.FlagSet, args []string) (*runOptions, error) {
+	opts := &runOptions{
+		Spectate: false,
+		Replay:   "",
+		Follow:   "",
+		Broker:   "",
+		Username: "",
+		Password: "",
+		Name:     "",
+		Theme:    themes[defaultThemeName],
+		Keys:     nil,
+	}
+	var configPath, themeName, keysFlag string
+	flagSet.StringVar(&configPath, "config", defaultConfigPath(), "path to the JSON config file")
+	flagSet.BoolVar(&opts.Spectate, "spectate", false, "connect as a spectator without joining the game")
+	flagSet.StringVar(&opts.Replay, "replay", "", "play a recorded match replay file instead of connecting to the server")
+	flagSet.StringVar(&opts.Follow, "follow", "", "player ID or display name to follow first in the replay")
+	flagSet.StringVar(&opts.Broker, "broker", "tcp://localhost:1883", "MQTT broker URL to connect to")
+	flagSet.StringVar(&opts.Username, "username", "", "username for the MQTT broker")
+	flagSet.StringVar(&opts.Password, "password", "", "password for the MQTT broker (prefer the config file to keep it out of shell history)")
+	flagSet.StringVar(&opts.Name, "name", "", "display name shown to other players (default: $USER)")
+	flagSet.StringVar(&themeName, "theme", defaultThemeName, "color theme (dark, light, mono)")
+	flagSet.StringVar(&keysFlag, "keys", "", "key bindings like up=w,down=s,left=a,right=d,shoot=j")
+	if err := flagSet.Parse(args); err != nil {
+		return nil, errors.Wrap(err, "failed to parse flags")
+	}
+
+	setFlags := make(map[string]bool)
+	flagSet.Visit(func(f *flag.Flag) {
+		setFlags[f.Name] = true
+	})
+
+	config, err := loadConfigFile(configPath, setFlags["config"])
+	if err != nil {
+		return nil, err
+	}
+
+	// コマンドラインで指定しなかった項目だけ設定ファイルの値で上書きする
+	fromFile := func(name string, dst *string, value string) {
+		if !setFlags[name] && value != "" {
+			*dst = value
+		}
+	}
+	fromFile("broker", &opts.Broker, config.Broker)
+	fromFile("username", &opts.Username, config.Username)
+	fromFile("password", &opts.Password, config.Password)
+	fromFile("name", &opts.Name, config.Name)
+	fromFile("theme", &themeName, config.Theme)
+
+	opts.Theme, err = findTheme(themeName)
+	if err != nil {
+		return nil, err
+	}
+
+	// キーは操作ごとに、設定ファイルの値をフラグの値で上書きする
+	keys, err := parseKeysFlag(keysFlag)
+	if err != nil {
+		return nil, err
+	}
+	for action, key := range config.Keys {
+		if _, ok := keys[action]; !ok {
+			keys[action] = key
+		}
+	}
+	opts.Keys, err = NewKeyBindings(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,33 @@
+package main
+
+// サーバーが関心領域の半径を決めている場合、範囲の外のプレイヤーやアイテムの変化は送られてこない
+// 範囲の外に出たものは古い状態のまま残ってしまうので、範囲に戻った時に古い位置で表示しないように忘れる
+// 範囲に入ったものはサーバーがその時点の状態を送り直してくれる
+
+// inView 自分の位置から関心領域の範囲内か。サーバーと同じくユークリッド距離で判定する
+func (g *Game) inView(position Position) bool {
+	center := g.getMyPlayer().Position
+	dx, dy := position.X-center.X, position.Y-center.Y
+	return dx*dx+dy*dy <= g.interestRadius*g.interestRadius
+}
+
+// forgetOutOfView 関心領域の外に出たプレイヤーとアイテムを忘れる
+// プレイヤーは表示名をキルフィードなどで使うので消さずに、次に状態が届くまで表示しない
+// 観戦中とリプレイの再生中は全て送られてくるので何もしない
+func (g *Game) forgetOutOfView() {
+	if g.interestRadius <= 0 || g.spectating || !g.viewSynced {
+		return
+	}
+
+	for id, item := range g.items {
+		if !g.inView(item.Position) {
+			delete(g.items, id)
+		}
+	}
+	for id, player := range g.players {
+		if id != g.myPlayerID && !player.OutOfView && !g.inView(player.Position) {
+			player.OutOfView = true
+			g.players[id] = player
+		}
+	}
+}
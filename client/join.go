@@ -0,0 +1,81 @@
+package main
+
+import (
+	"log"
+	"os"
+	"unicode/utf8"
+
+	"github.com/shibayu36/terminal-shooter/shared"
+	"google.golang.org/protobuf/proto"
+)
+
+// 表示名が指定されていない時に使う名前
+const fallbackDisplayName = "player"
+
+// サーバーが受け付ける表示名の最大文字数
+const maxDisplayNameLength = 16
+
+// 表示名の初期値。OSのユーザー名を使い、取れなければfallbackDisplayNameにする
+func defaultDisplayName() string {
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return fallbackDisplayName
+}
+
+// 表示名を指定してゲームへの参加をサーバーに要求する
+func (g *Game) join(displayName string) {
+	g.displayName = displayName
+
+	joinReq := &shared.JoinRequest{
+		DisplayName:   displayName,
+		PreferredTeam: shared.Team_NO_TEAM,
+		ClientVersion: shared.ProtocolVersion,
+	}
+	payload, err := proto.Marshal(joinReq)
+	if err != nil {
+		log.Printf("Failed to marshal join request: %v", err)
+		return
+	}
+
+	if token := g.mqtt.Publish("join", 0, false, payload); token.Wait() && token.Error() != nil {
+		log.Printf("Failed to publish join request: %v", token.Error())
+	}
+}
+
+// 参加リクエストの結果を反映する
+// 表示名が既に使われている場合は、IDの先頭を付けた名前で1度だけ再要求する
+func (g *Game) onJoinResponse(joinResponse *shared.JoinResponse) {
+	switch joinResponse.GetResult() {
+	case shared.JoinResult_JOIN_ACCEPTED:
+		g.displayName = joinResponse.GetDisplayName()
+		return
+	case shared.JoinResult_JOIN_NAME_TAKEN:
+		if !g.joinRetried {
+			g.joinRetried = true
+			g.join(retryDisplayName(g.displayName, g.myPlayerID))
+			return
+		}
+	case shared.JoinResult_JOIN_NAME_INVALID:
+		if g.displayName != fallbackDisplayName {
+			g.join(fallbackDisplayName)
+			return
+		}
+	case shared.JoinResult_JOIN_VERSION_MISMATCH:
+	}
+
+	// サーバーは参加を受け付けたクライアントにしかプレイヤーを作らないので、自分のプレイヤーも消す
+	delete(g.players, g.myPlayerID)
+	g.killFeed.Add("Join failed: " + joinResponse.GetMessage())
+}
+
+// retryDisplayName 表示名にIDの先頭を付けた名前を作る
+// 付けた後も最大文字数に収まるように元の名前を削る
+func retryDisplayName(name, playerID string) string {
+	suffix := "-" + playerID[:4]
+	base := []rune(name)
+	if maxLength := maxDisplayNameLength - utf8.RuneCountInString(suffix); len(base) > maxLength {
+		base = base[:maxLength]
+	}
+	return string(base) + suffix
+}
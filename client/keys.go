@@ -0,0 +1,121 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Action キーに割り当てられるプレイヤーの操作
+type Action string
+
+const (
+	ActionUp         Action = "up"
+	ActionDown       Action = "down"
+	ActionLeft       Action = "left"
+	ActionRight      Action = "right"
+	ActionShoot      Action = "shoot"
+	ActionBomb       Action = "bomb"
+	ActionSwitchTeam Action = "switch_team"
+	ActionReload     Action = "reload"
+	ActionChat       Action = "chat"
+	ActionTeamChat   Action = "team_chat"
+)
+
+// 操作ごとのキーの初期値
+// 武器の選択の1から4と、終了のEscとCtrl-Cは変えられない
+var defaultKeys = map[Action]string{ //nolint:gochecknoglobals
+	ActionUp:         "Up",
+	ActionDown:       "Down",
+	ActionLeft:       "Left",
+	ActionRight:      "Right",
+	ActionShoot:      "Space",
+	ActionBomb:       "b",
+	ActionSwitchTeam: "t",
+	ActionReload:     "r",
+	ActionChat:       "Enter",
+	ActionTeamChat:   "y",
+}
+
+// keyBinding 1つのキー。文字キーの場合はkeyがKeyRuneで、chに文字が入る
+type keyBinding struct {
+	key tcell.Key
+	ch  rune
+}
+
+// KeyBindings キーから操作を引く対応表
+type KeyBindings map[keyBinding]Action
+
+// NewKeyBindings 初期値のキーをoverridesで上書きした対応表を作る
+// 知らない操作や読めないキー、同じキーを複数の操作に割り当てた場合はエラーを返す
+func NewKeyBindings(overrides map[string]string) (KeyBindings, error) {
+	keys := make(map[Action]string, len(defaultKeys))
+	maps.Copy(keys, defaultKeys)
+	for name, key := range overrides {
+		action := Action(name)
+		if _, ok := defaultKeys[action]; !ok {
+			return nil, errors.Newf("unknown key binding action: %s", name)
+		}
+		keys[action] = key
+	}
+
+	bindings := make(KeyBindings, len(keys))
+	// エラーメッセージが毎回同じになるように、操作の名前順に割り当てる
+	for _, action := range slices.Sorted(maps.Keys(keys)) {
+		binding, err := parseKey(keys[action])
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid key for %s", action)
+		}
+		if isReservedKey(binding) {
+			return nil, errors.Newf("key %q for %s is reserved", keys[action], action)
+		}
+		if other, ok := bindings[binding]; ok {
+			return nil, errors.Newf("key %q is bound to both %s and %s", keys[action], other, action)
+		}
+		bindings[binding] = action
+	}
+	return bindings, nil
+}
+
+// Lookup キー入力に割り当てられた操作を返す
+func (b KeyBindings) Lookup(ev *tcell.EventKey) (Action, bool) {
+	action, ok := b[eventKeyBinding(ev)]
+	return action, ok
+}
+
+func eventKeyBinding(ev *tcell.EventKey) keyBinding {
+	if ev.Key() == tcell.KeyRune {
+		return keyBinding{key: tcell.KeyRune, ch: ev.Rune()}
+	}
+	return keyBinding{key: ev.Key(), ch: 0}
+}
+
+// parseKey キーの名前を読む
+// "Space"、tcellのキー名("Up"、"Enter"、"Ctrl-A"など)、1文字のいずれかを受け付ける
+func parseKey(name string) (keyBinding, error) {
+	if strings.EqualFold(name, "Space") {
+		return keyBinding{key: tcell.KeyRune, ch: ' '}, nil
+	}
+	if utf8.RuneCountInString(name) == 1 {
+		r, _ := utf8.DecodeRuneInString(name)
+		return keyBinding{key: tcell.KeyRune, ch: r}, nil
+	}
+	for key, keyName := range tcell.KeyNames {
+		if strings.EqualFold(name, keyName) {
+			return keyBinding{key: key, ch: 0}, nil
+		}
+	}
+	return keyBinding{key: tcell.KeyNUL, ch: 0}, errors.Newf("unknown key: %q", name)
+}
+
+// 変えられないキーか。武器の選択と終了のキー
+func isReservedKey(binding keyBinding) bool {
+	if binding.key == tcell.KeyEscape || binding.key == tcell.KeyCtrlC {
+		return true
+	}
+	return binding.key == tcell.KeyRune && binding.ch >= '1' && int(binding.ch-'1') < len(weaponLabels)
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/shibayu36/terminal-shooter/shared"
+)
+
+// キルフィードに残す行数
+const killFeedSize = 10
+
+// KillFeed サーバーから届いたゲームイベントを新しい順に一定数だけ保持する
+type KillFeed struct {
+	lines []string
+}
+
+// 新しいKillFeed構造体を作成
+func NewKillFeed() *KillFeed {
+	return &KillFeed{
+		lines: nil,
+	}
+}
+
+// 行を追加する。保持できる行数を超えたら古いものから捨てる
+func (f *KillFeed) Add(line string) {
+	f.lines = append([]string{line}, f.lines...)
+	if len(f.lines) > killFeedSize {
+		f.lines = f.lines[:killFeedSize]
+	}
+}
+
+// 新しい順の行の一覧
+func (f *KillFeed) Lines() []string {
+	return f.lines
+}
+
+// ゲームイベントをキルフィードに表示する文字列にする
+// 表示しないイベントの場合は空文字を返す
+func (g *Game) gameEventText(event *shared.GameEvent) string {
+	switch e := event.GetEvent().(type) {
+	case *shared.GameEvent_PlayerKilled:
+		killed := e.PlayerKilled
+		victim := g.playerName(killed.GetVictimId())
+		if killed.GetKillerId() == "" || killed.GetKillerId() == killed.GetVictimId() {
+			return fmt.Sprintf("%s died [%s]", victim, killCauseLabel(killed))
+		}
+		return fmt.Sprintf("%s > %s [%s]", g.playerName(killed.GetKillerId()), victim, killCauseLabel(killed))
+	case *shared.GameEvent_BombExploded:
+		position := e.BombExploded.GetPosition()
+		return fmt.Sprintf("Bomb (%d,%d) by %s", position.GetX(), position.GetY(), g.playerName(e.BombExploded.GetOwnerId()))
+	case *shared.GameEvent_PlayerJoined:
+		return g.playerName(e.PlayerJoined.GetPlayerId()) + " joined"
+	case *shared.GameEvent_PlayerLeft:
+		return g.playerName(e.PlayerLeft.GetPlayerId()) + " left"
+	case *shared.GameEvent_PowerUpCollected:
+		return fmt.Sprintf("%s got %s", g.playerName(e.PowerUpCollected.GetPlayerId()), powerUpLabels[e.PowerUpCollected.GetType()])
+	}
+	return ""
+}
+
+// キルフィードで表示するプレイヤー名。自分はYouにし、表示名が分からないプレイヤーはIDの先頭だけを表示する
+func (g *Game) playerName(playerID string) string {
+	if playerID == g.myPlayerID {
+		return "You"
+	}
+	if name := g.players[playerID].Name; name != "" {
+		return name
+	}
+	if len(playerID) > 8 {
+		return playerID[:8]
+	}
+	return playerID
+}
+
+// 倒した攻撃の表示名。弾の場合は武器名にする
+func killCauseLabel(killed *shared.PlayerKilled) string {
+	switch killed.GetCause() {
+	case shared.ItemType_BULLET:
+		for _, weaponLabel := range weaponLabels {
+			if weaponLabel.Type == killed.GetWeapon() {
+				return weaponLabel.Label
+			}
+		}
+	case shared.ItemType_BOMB_FIRE:
+		return "Bomb"
+	case shared.ItemType_BOMB,
+		shared.ItemType_BOMB_CAPACITY_UP,
+		shared.ItemType_BOMB_RANGE_UP,
+		shared.ItemType_SPEED_UP,
+		shared.ItemType_SHIELD,
+		shared.ItemType_RAPID_FIRE,
+		shared.ItemType_WALL,
+		shared.ItemType_BLOCK:
+	}
+	return "?"
+}
@@ -1,15 +1,19 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"fmt"
 	"log"
 	"math/rand"
+	"os"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/errors"
 	mqtt "github.com/eclipse/paho.mqtt.golang"
 	"github.com/gdamore/tcell/v2"
-	"github.com/google/uuid"
 	"github.com/shibayu36/terminal-shooter/shared"
 	"google.golang.org/protobuf/proto"
 )
@@ -24,12 +28,54 @@ type Player struct {
 	Position  Position
 	Direction shared.Direction
 	Status    shared.Status
+	Team      shared.Team
+	HP        int
+	MaxHP     int
+	Effects   []Effect
+	Weapon    Weapon
+	// 1マス移動するのに必要なサーバーのtick数。0なら制限なし
+	MoveTicks int
+	// 表示名。サーバーが参加リクエストを受け付けるまでは空
+	Name string
+	// 関心領域の外に出て、状態が送られてこなくなったか。次に状態が届くまで表示しない
+	OutOfView bool
+}
+
+// サーバーの1秒あたりのtick数の初期値。サーバーからマッチの状態を受け取ったらその値を使う
+const defaultTicksPerSecond = 60
+
+// Weapon プレイヤーの武器の状態
+type Weapon struct {
+	Type        shared.WeaponType
+	BulletReady bool
+	Ammo        int
+	MaxAmmo     int
+	Reloading   bool
+	ActiveBombs int
+	MaxBombs    int
+}
+
+// Effect プレイヤーにかかっているパワーアップの効果
+type Effect struct {
+	Type             shared.ItemType
+	RemainingSeconds int
 }
 
 type Item struct {
 	ID       string
 	Type     shared.ItemType
 	Position Position
+	// BULLETの場合に発射した武器
+	Weapon shared.WeaponType
+}
+
+type MatchStatus struct {
+	Mode             shared.GameMode
+	Phase            shared.MatchPhase
+	RemainingSeconds int
+	Scores           map[string]int
+	TeamScores       map[shared.Team]int
+	WinnerIDs        []string
 }
 
 type Game struct {
@@ -40,10 +86,44 @@ type Game struct {
 	myPlayerID string
 	players    map[string]Player
 	items      map[string]Item
+	match      MatchStatus
 	width      int
 	height     int
+	// サーバーの1秒あたりのtick数
+	ticksPerSecond int
+	// サーバーが状態を送ってくる範囲の半径。0なら盤面全体が送られてくる
+	interestRadius int
+	// サーバーから自分の位置を受け取ったか。受け取るまでは関心領域の外のものを忘れない
+	viewSynced bool
+	// 最後に自分が移動した時刻。サーバーの移動速度の制限を超えないようにするために使う
+	lastMovedAt time.Time
 
 	messageStats *MessageStats
+	killFeed     *KillFeed
+
+	// 参加リクエストで送った表示名
+	displayName string
+	// 表示名が使われていて別の名前で参加し直したか
+	joinRetried bool
+
+	chatLog *ChatLog
+	// チャットを入力中か。入力中はキー入力をチャットの入力として扱う
+	chatting  bool
+	chatScope shared.ChatScope
+	chatInput []rune
+
+	// 観戦中か。観戦中はプレイヤーとして参加せず、followIDのプレイヤーを追いかけて表示する
+	spectating bool
+	followID   string
+
+	// 再生中のリプレイ。リプレイの再生中はサーバーに接続せず、記録された盤面を表示する
+	replay *Replay
+
+	// サーバーとの接続が切れて再接続中か。再接続中は終了以外の操作を受け付けない
+	reconnecting bool
+
+	theme Theme
+	keys  KeyBindings
 }
 
 func (g *Game) publishMyState() {
@@ -72,7 +152,11 @@ func (g *Game) publishMyState() {
 }
 
 func (g *Game) movePlayer(direction shared.Direction) {
-	myPlayer := g.getMyPlayer()
+	// 参加できなかった場合は動かすプレイヤーがいない
+	myPlayer, ok := g.players[g.myPlayerID]
+	if !ok {
+		return
+	}
 	oldX, oldY := myPlayer.Position.X, myPlayer.Position.Y
 	oldDirection := myPlayer.Direction
 
@@ -89,6 +173,17 @@ func (g *Game) movePlayer(direction shared.Direction) {
 		dy = 1
 	}
 
+	// 障害物のある位置には移動せず、向きだけ変える
+	if g.isObstacleAt(Position{X: myPlayer.Position.X + dx, Y: myPlayer.Position.Y + dy}) {
+		dx, dy = 0, 0
+	}
+
+	// サーバーの移動速度の制限より速くは移動せず、向きだけ変える
+	moveInterval := time.Duration(myPlayer.MoveTicks) * time.Second / time.Duration(g.ticksPerSecond)
+	if time.Since(g.lastMovedAt) < moveInterval {
+		dx, dy = 0, 0
+	}
+
 	if newX := myPlayer.Position.X + dx; newX >= 0 && newX < g.width {
 		myPlayer.Position.X = newX
 	}
@@ -99,39 +194,86 @@ func (g *Game) movePlayer(direction shared.Direction) {
 
 	g.players[g.myPlayerID] = myPlayer
 
+	if oldX != myPlayer.Position.X || oldY != myPlayer.Position.Y {
+		g.lastMovedAt = time.Now()
+		g.forgetOutOfView()
+	}
+
 	// 位置か方向が変更されたら自分の状態をサーバーに送る
 	if oldX != myPlayer.Position.X || oldY != myPlayer.Position.Y || oldDirection != direction {
 		g.publishMyState()
 	}
 }
 
+// 指定位置に壁やブロックがあるかどうか
+func (g *Game) isObstacleAt(position Position) bool {
+	for _, item := range g.items {
+		if item.Position == position && (item.Type == shared.ItemType_WALL || item.Type == shared.ItemType_BLOCK) {
+			return true
+		}
+	}
+	return false
+}
+
 func (g *Game) handleEvent(event tcell.Event) bool {
 	//nolint:gocritic,varnamelen // ignore singleCaseSwitch
 	switch ev := event.(type) {
 	case *tcell.EventKey:
-		//nolint:exhaustive
-		switch ev.Key() {
-		case tcell.KeyEscape, tcell.KeyCtrlC:
+		if g.reconnecting {
+			return ev.Key() == tcell.KeyEscape || ev.Key() == tcell.KeyCtrlC
+		}
+		if g.replay != nil {
+			return g.handleReplayKey(ev)
+		}
+		if g.spectating {
+			return g.handleSpectateKey(ev)
+		}
+		if g.chatting && ev.Key() != tcell.KeyCtrlC {
+			g.handleChatKey(ev)
+			return false
+		}
+
+		if ev.Key() == tcell.KeyEscape || ev.Key() == tcell.KeyCtrlC {
 			return true
-		case tcell.KeyLeft:
-			g.movePlayer(shared.Direction_LEFT)
-		case tcell.KeyRight:
-			g.movePlayer(shared.Direction_RIGHT)
-		case tcell.KeyUp:
-			g.movePlayer(shared.Direction_UP)
-		case tcell.KeyDown:
-			g.movePlayer(shared.Direction_DOWN)
-		case tcell.KeyRune:
-			if ev.Rune() == ' ' {
-				g.shootBullet()
-			} else if ev.Rune() == 'b' {
-				g.placeBomb()
-			}
+		}
+		if action, ok := g.keys.Lookup(ev); ok {
+			g.doAction(action)
+			return false
+		}
+		// 武器は1から順に選択する。キーは変えられない
+		if ev.Key() == tcell.KeyRune && ev.Rune() >= '1' && int(ev.Rune()-'1') < len(weaponLabels) {
+			g.selectWeapon(weaponLabels[ev.Rune()-'1'].Type)
 		}
 	}
 	return false
 }
 
+// キーに割り当てられた操作を行う
+func (g *Game) doAction(action Action) {
+	switch action {
+	case ActionUp:
+		g.movePlayer(shared.Direction_UP)
+	case ActionDown:
+		g.movePlayer(shared.Direction_DOWN)
+	case ActionLeft:
+		g.movePlayer(shared.Direction_LEFT)
+	case ActionRight:
+		g.movePlayer(shared.Direction_RIGHT)
+	case ActionShoot:
+		g.shootBullet()
+	case ActionBomb:
+		g.placeBomb()
+	case ActionSwitchTeam:
+		g.switchTeam()
+	case ActionReload:
+		g.reload()
+	case ActionChat:
+		g.startChat(shared.ChatScope_CHAT_SCOPE_ALL)
+	case ActionTeamChat:
+		g.startChat(shared.ChatScope_CHAT_SCOPE_TEAM)
+	}
+}
+
 func (g *Game) shootBullet() {
 	req := &shared.PlayerActionRequest{
 		Type: shared.ActionType_SHOOT_BULLET,
@@ -165,6 +307,61 @@ func (g *Game) placeBomb() {
 	}
 }
 
+// 相手チームへの移動をサーバーに要求する
+func (g *Game) switchTeam() {
+	team := shared.Team_RED
+	if g.getMyPlayer().Team == shared.Team_RED {
+		team = shared.Team_BLUE
+	}
+
+	actionReq := &shared.PlayerActionRequest{
+		Type: shared.ActionType_SWITCH_TEAM,
+		Team: team,
+	}
+	payload, err := proto.Marshal(actionReq)
+	if err != nil {
+		log.Printf("Failed to marshal player action request: %v", err)
+		return
+	}
+
+	if token := g.mqtt.Publish("player_action", 0, false, payload); token.Wait() && token.Error() != nil {
+		log.Printf("Failed to publish player action: %v", token.Error())
+	}
+}
+
+// 武器の持ち替えをサーバーに要求する
+func (g *Game) selectWeapon(weapon shared.WeaponType) {
+	actionReq := &shared.PlayerActionRequest{
+		Type:   shared.ActionType_SELECT_WEAPON,
+		Weapon: weapon,
+	}
+	payload, err := proto.Marshal(actionReq)
+	if err != nil {
+		log.Printf("Failed to marshal player action request: %v", err)
+		return
+	}
+
+	if token := g.mqtt.Publish("player_action", 0, false, payload); token.Wait() && token.Error() != nil {
+		log.Printf("Failed to publish player action: %v", token.Error())
+	}
+}
+
+// 弾のリロードをサーバーに要求する
+func (g *Game) reload() {
+	actionReq := &shared.PlayerActionRequest{
+		Type: shared.ActionType_RELOAD,
+	}
+	payload, err := proto.Marshal(actionReq)
+	if err != nil {
+		log.Printf("Failed to marshal player action request: %v", err)
+		return
+	}
+
+	if token := g.mqtt.Publish("player_action", 0, false, payload); token.Wait() && token.Error() != nil {
+		log.Printf("Failed to publish player action: %v", token.Error())
+	}
+}
+
 func getPlayerRune(player Player) rune {
 	if player.Status == shared.Status_DEAD {
 		return 'x'
@@ -184,88 +381,309 @@ func getPlayerRune(player Player) rune {
 	}
 }
 
-const (
-	bgColor          = tcell.Color232
-	mapColor         = tcell.Color255
-	myPlayerColor    = tcell.Color46
-	otherPlayerColor = tcell.Color196
-	itemColor        = tcell.Color226
-	bombColor        = tcell.Color208
-	fireColor        = tcell.Color196
-)
+// 武器ごとの弾の表示
+var bulletRunes = map[shared.WeaponType]rune{ //nolint:gochecknoglobals
+	shared.WeaponType_PISTOL:   '*',
+	shared.WeaponType_RIFLE:    '+',
+	shared.WeaponType_SHOTGUN:  '.',
+	shared.WeaponType_PIERCING: '!',
+}
+
+// 武器の表示名。キーの1から順に選択できる
+var weaponLabels = []struct { //nolint:gochecknoglobals
+	Type  shared.WeaponType
+	Label string
+}{
+	{Type: shared.WeaponType_PISTOL, Label: "Pistol"},
+	{Type: shared.WeaponType_RIFLE, Label: "Rifle"},
+	{Type: shared.WeaponType_SHOTGUN, Label: "Shotgun"},
+	{Type: shared.WeaponType_PIERCING, Label: "Piercing"},
+}
+
+// パワーアップアイテムの表示
+var powerUpRunes = map[shared.ItemType]rune{ //nolint:gochecknoglobals
+	shared.ItemType_BOMB_CAPACITY_UP: 'B',
+	shared.ItemType_BOMB_RANGE_UP:    'F',
+	shared.ItemType_SPEED_UP:         'S',
+	shared.ItemType_SHIELD:           'O',
+	shared.ItemType_RAPID_FIRE:       'R',
+}
+
+// パワーアップの効果の表示名
+var powerUpLabels = map[shared.ItemType]string{ //nolint:gochecknoglobals
+	shared.ItemType_BOMB_CAPACITY_UP: "Bomb+",
+	shared.ItemType_BOMB_RANGE_UP:    "Fire+",
+	shared.ItemType_SPEED_UP:         "Speed",
+	shared.ItemType_SHIELD:           "Shield",
+	shared.ItemType_RAPID_FIRE:       "Rapid",
+}
+
+// マップの下に表示する情報の行数。統計、マッチ、HP、武器の4行とチャット欄、チャットの入力欄
+const hudHeight = 4 + chatLogSize + 1
 
 //nolint:funlen
 func (g *Game) draw() {
 	g.screen.Clear()
 
 	defaultStyle := tcell.StyleDefault.
-		Background(bgColor).
-		Foreground(mapColor)
+		Background(g.theme.Background).
+		Foreground(g.theme.Map)
+
+	// 画面に収まらない場合は、見ているプレイヤーを中心にした範囲だけを描画する
+	screenWidth, screenHeight := g.screen.Size()
+	camera := newFollowCamera(g.viewedPlayer().Position, g.width, g.height, screenWidth, screenHeight-hudHeight)
+	setMapContent := func(position Position, r rune, style tcell.Style) {
+		if x, y, ok := camera.ToScreen(position); ok {
+			g.screen.SetContent(x, y, r, nil, style)
+		}
+	}
 
 	// マップを描画
-	for y := range g.height {
-		for x := range g.width {
+	for y := range camera.Height {
+		for x := range camera.Width {
 			g.screen.SetContent(x, y, '.', nil, defaultStyle)
 		}
 	}
 
+	// プレイヤーの表示名をプレイヤーの上の行に描画する
+	// プレイヤーやアイテムで上書きされるように先に描画する
+	nameStyle := defaultStyle.Foreground(g.theme.Name)
+	for _, player := range g.players {
+		if player.Name == "" || player.OutOfView {
+			continue
+		}
+		for i, r := range []rune(player.Name) {
+			setMapContent(Position{X: player.Position.X + i, Y: player.Position.Y - 1}, r, nameStyle)
+		}
+	}
+
 	// プレイヤーを描画
-	myPlayerStyle := defaultStyle.Foreground(myPlayerColor)
-	otherPlayerStyle := defaultStyle.Foreground(otherPlayerColor)
+	myPlayerStyle := defaultStyle.Foreground(g.theme.MyPlayer)
+	otherPlayerStyle := defaultStyle.Foreground(g.theme.OtherPlayer)
 	for _, player := range g.players {
+		if player.OutOfView {
+			continue
+		}
 		style := otherPlayerStyle
 		if player.ID == g.myPlayerID {
 			style = myPlayerStyle
 		}
-		g.screen.SetContent(
-			player.Position.X,
-			player.Position.Y,
-			getPlayerRune(player),
-			nil,
-			style,
-		)
+		// チーム戦ではチームの色で描画し、自分は太字にする
+		switch player.Team {
+		case shared.Team_RED:
+			style = defaultStyle.Foreground(g.theme.RedTeam).Bold(player.ID == g.myPlayerID)
+		case shared.Team_BLUE:
+			style = defaultStyle.Foreground(g.theme.BlueTeam).Bold(player.ID == g.myPlayerID)
+		case shared.Team_NO_TEAM:
+		}
+		setMapContent(player.Position, getPlayerRune(player), style)
 	}
 
 	// アイテムを描画
-	itemStyle := defaultStyle.Foreground(itemColor)
+	itemStyle := defaultStyle.Foreground(g.theme.Item)
 	for _, item := range g.items {
 		var itemRune rune
 		style := itemStyle
 
 		switch item.Type {
 		case shared.ItemType_BULLET:
-			itemRune = '*'
+			itemRune = bulletRunes[item.Weapon]
 		case shared.ItemType_BOMB:
 			itemRune = '@'
-			style = defaultStyle.Foreground(bombColor)
+			style = defaultStyle.Foreground(g.theme.Bomb)
 		case shared.ItemType_BOMB_FIRE:
 			itemRune = '#'
-			style = defaultStyle.Foreground(fireColor)
+			style = defaultStyle.Foreground(g.theme.Fire)
+		case shared.ItemType_BOMB_CAPACITY_UP,
+			shared.ItemType_BOMB_RANGE_UP,
+			shared.ItemType_SPEED_UP,
+			shared.ItemType_SHIELD,
+			shared.ItemType_RAPID_FIRE:
+			itemRune = powerUpRunes[item.Type]
+			style = defaultStyle.Foreground(g.theme.PowerUp)
+		case shared.ItemType_WALL:
+			itemRune = '█'
+			style = defaultStyle.Foreground(g.theme.Wall)
+		case shared.ItemType_BLOCK:
+			itemRune = '▒'
+			style = defaultStyle.Foreground(g.theme.Block)
 		}
-		g.screen.SetContent(
-			item.Position.X,
-			item.Position.Y,
-			itemRune,
-			nil,
-			style,
-		)
+		setMapContent(item.Position, itemRune, style)
 	}
 
-	// メッセージレートとバイトレートを画面下部に表示
+	// メッセージレートとバイトレートを画面下部に表示。リプレイ中は再生位置を表示する
 	statsStr := fmt.Sprintf("Msgs: %.1f/s, KB: %.1f/s",
 		g.messageStats.Rate(),
 		g.messageStats.BytesRate()/1024,
 	)
+	if g.replay != nil {
+		statsStr = g.replayText()
+	}
 	style := tcell.StyleDefault.
-		Foreground(tcell.ColorWhite)
+		Foreground(g.theme.Text)
 
 	for i, r := range []rune(statsStr) {
-		g.screen.SetContent(i, g.height, r, nil, style)
+		g.screen.SetContent(i, camera.Height, r, nil, style)
+	}
+
+	// マッチの状態を統計情報の下に表示
+	for i, r := range []rune(g.matchStatusText()) {
+		g.screen.SetContent(i, camera.Height+1, r, nil, style)
+	}
+
+	// 自分のHPをさらにその下に表示。観戦中は追いかけているプレイヤーのHPを表示する
+	hpText := g.hpText()
+	if g.spectating {
+		hpText = g.spectateText() + " " + hpText
+	}
+	for i, r := range []rune(hpText) {
+		g.screen.SetContent(i, camera.Height+2, r, nil, style)
+	}
+
+	// 武器の状態をさらにその下に表示
+	for i, r := range []rune(g.weaponText()) {
+		g.screen.SetContent(i, camera.Height+3, r, nil, style)
+	}
+
+	// チャットを武器の状態の下に古い順で表示し、入力中は入力欄をその下に表示する
+	for y, line := range g.chatLog.Lines() {
+		for i, r := range []rune(line) {
+			g.screen.SetContent(i, camera.Height+4+y, r, nil, style)
+		}
+	}
+	if g.chatting {
+		for i, r := range []rune(g.chatInputText()) {
+			g.screen.SetContent(i, camera.Height+4+chatLogSize, r, nil, style)
+		}
+	}
+
+	// 再接続中はマップの上に表示する
+	if g.reconnecting {
+		bannerStyle := tcell.StyleDefault.Background(tcell.ColorDarkRed).Foreground(tcell.ColorWhite).Bold(true)
+		banner := []rune(" Connection lost. Reconnecting... ")
+		x := max((camera.Width-len(banner))/2, 0)
+		for i, r := range banner {
+			g.screen.SetContent(x+i, camera.Height/2, r, nil, bannerStyle)
+		}
+	}
+
+	// キルフィードをマップの右側に新しい順で表示
+	for y, line := range g.killFeed.Lines() {
+		for i, r := range []rune(line) {
+			g.screen.SetContent(camera.Width+2+i, y, r, nil, style)
+		}
 	}
 
 	g.screen.Show()
 }
 
+// 自分のHPをゲージ付きの文字列にする
+func (g *Game) hpText() string {
+	const gaugeWidth = 10
+
+	myPlayer := g.viewedPlayer()
+	if myPlayer.MaxHP <= 0 {
+		return ""
+	}
+	filled := myPlayer.HP * gaugeWidth / myPlayer.MaxHP
+	text := fmt.Sprintf("HP [%s%s] %d/%d",
+		strings.Repeat("#", filled),
+		strings.Repeat("-", gaugeWidth-filled),
+		myPlayer.HP,
+		myPlayer.MaxHP,
+	)
+
+	// かかっているパワーアップの効果を並べる
+	for _, effect := range myPlayer.Effects {
+		text += fmt.Sprintf(" %s(%ds)", powerUpLabels[effect.Type], effect.RemainingSeconds)
+	}
+	return text
+}
+
+// 自分の武器の状態を表示用の文字列にする
+func (g *Game) weaponText() string {
+	weapon := g.viewedPlayer().Weapon
+
+	shot := "READY"
+	if weapon.Reloading {
+		shot = "RELOADING"
+	} else if !weapon.BulletReady {
+		shot = "COOLDOWN"
+	}
+
+	ammo := "∞"
+	if weapon.MaxAmmo > 0 {
+		ammo = fmt.Sprintf("%d/%d", weapon.Ammo, weapon.MaxAmmo)
+	}
+
+	bombs := "∞"
+	if weapon.MaxBombs > 0 {
+		bombs = fmt.Sprintf("%d/%d", weapon.MaxBombs-weapon.ActiveBombs, weapon.MaxBombs)
+	}
+
+	label := ""
+	for i, weaponLabel := range weaponLabels {
+		if weaponLabel.Type == weapon.Type {
+			label = fmt.Sprintf("%d:%s", i+1, weaponLabel.Label)
+		}
+	}
+
+	return fmt.Sprintf("Weapon: %s  Shot: %s  Ammo: %s  Bombs: %s", label, shot, ammo, bombs)
+}
+
+// マッチの進行状況を表示用の文字列にする
+func (g *Game) matchStatusText() string {
+	return fmt.Sprintf("[%s] %s", gameModeLabel(g.match.Mode), g.matchPhaseText())
+}
+
+func gameModeLabel(mode shared.GameMode) string {
+	switch mode {
+	case shared.GameMode_DEATHMATCH:
+		return "Deathmatch"
+	case shared.GameMode_LAST_MAN_STANDING:
+		return "Last Man Standing"
+	case shared.GameMode_TEAM_DEATHMATCH:
+		return "Team Deathmatch"
+	default:
+		return "Unknown"
+	}
+}
+
+func (g *Game) matchPhaseText() string {
+	switch g.match.Phase {
+	case shared.MatchPhase_WAITING:
+		return "Waiting for players..."
+	case shared.MatchPhase_COUNTDOWN:
+		return fmt.Sprintf("Starting in %d...", g.match.RemainingSeconds)
+	case shared.MatchPhase_RUNNING:
+		text := fmt.Sprintf("Score: %d", g.match.Scores[g.viewedPlayer().ID])
+		if g.match.Mode == shared.GameMode_TEAM_DEATHMATCH {
+			teamScores := g.match.TeamScores
+			text += fmt.Sprintf(", Red: %d, Blue: %d", teamScores[shared.Team_RED], teamScores[shared.Team_BLUE])
+		}
+		if g.match.RemainingSeconds > 0 {
+			text += fmt.Sprintf(", Time: %d:%02d", g.match.RemainingSeconds/60, g.match.RemainingSeconds%60)
+		}
+		return text
+	case shared.MatchPhase_FINISHED:
+		result := "Draw"
+		if g.spectating && len(g.match.WinnerIDs) > 0 {
+			names := make([]string, 0, len(g.match.WinnerIDs))
+			for _, winnerID := range g.match.WinnerIDs {
+				names = append(names, g.playerName(winnerID))
+			}
+			result = "Winner: " + strings.Join(names, ", ")
+		} else if slices.Contains(g.match.WinnerIDs, g.myPlayerID) {
+			result = "You win!"
+		} else if len(g.match.WinnerIDs) > 0 {
+			result = "You lose"
+		}
+		return fmt.Sprintf("Finished! %s (next match in %d)", result, g.match.RemainingSeconds)
+	default:
+		return ""
+	}
+}
+
 func (g *Game) getMyPlayer() Player {
 	return g.players[g.myPlayerID]
 }
@@ -282,21 +700,7 @@ func (g *Game) handleMessage(message mqtt.Message) {
 			log.Printf("Failed to unmarshal player state: %v", err)
 			return
 		}
-
-		if playerState.GetStatus() == shared.Status_DISCONNECTED {
-			delete(g.players, playerState.GetPlayerId())
-			return
-		}
-
-		g.players[playerState.GetPlayerId()] = Player{
-			ID: playerState.GetPlayerId(),
-			Position: Position{
-				X: int(playerState.GetPosition().GetX()),
-				Y: int(playerState.GetPosition().GetY()),
-			},
-			Direction: playerState.GetDirection(),
-			Status:    playerState.GetStatus(),
-		}
+		g.applyPlayerState(playerState)
 	case "item_state":
 		itemState := &shared.ItemState{}
 		err := proto.Unmarshal(message.Payload(), itemState)
@@ -304,25 +708,163 @@ func (g *Game) handleMessage(message mqtt.Message) {
 			log.Printf("Failed to unmarshal item state: %v", err)
 			return
 		}
-
-		if itemState.GetStatus() == shared.ItemStatus_REMOVED {
-			delete(g.items, itemState.GetItemId())
+		g.applyItemState(itemState)
+	case "match_state":
+		matchState := &shared.MatchState{}
+		err := proto.Unmarshal(message.Payload(), matchState)
+		if err != nil {
+			log.Printf("Failed to unmarshal match state: %v", err)
 			return
 		}
-
-		g.items[itemState.GetItemId()] = Item{
-			ID:   itemState.GetItemId(),
-			Type: itemState.GetType(),
-			Position: Position{
-				X: int(itemState.GetPosition().GetX()),
-				Y: int(itemState.GetPosition().GetY()),
-			},
+		g.applyMatchState(matchState)
+	case "join_response":
+		joinResponse := &shared.JoinResponse{}
+		err := proto.Unmarshal(message.Payload(), joinResponse)
+		if err != nil {
+			log.Printf("Failed to unmarshal join response: %v", err)
+			return
 		}
+		g.onJoinResponse(joinResponse)
+	case "chat":
+		chatMessage := &shared.ChatMessage{}
+		err := proto.Unmarshal(message.Payload(), chatMessage)
+		if err != nil {
+			log.Printf("Failed to unmarshal chat message: %v", err)
+			return
+		}
+		g.chatLog.Add(g.chatText(chatMessage))
+	case "game_event":
+		gameEvent := &shared.GameEvent{}
+		err := proto.Unmarshal(message.Payload(), gameEvent)
+		if err != nil {
+			log.Printf("Failed to unmarshal game event: %v", err)
+			return
+		}
+		g.applyGameEvent(gameEvent)
 	}
+
+	g.forgetOutOfView()
+}
+
+// サーバーから受け取ったプレイヤーの状態を反映する
+func (g *Game) applyPlayerState(playerState *shared.PlayerState) {
+	if playerState.GetStatus() == shared.Status_DISCONNECTED {
+		delete(g.players, playerState.GetPlayerId())
+		return
+	}
+
+	effects := make([]Effect, 0, len(playerState.GetEffects()))
+	for _, effect := range playerState.GetEffects() {
+		effects = append(effects, Effect{
+			Type:             effect.GetType(),
+			RemainingSeconds: int(effect.GetRemainingSeconds()),
+		})
+	}
+
+	g.players[playerState.GetPlayerId()] = Player{
+		ID: playerState.GetPlayerId(),
+		Position: Position{
+			X: int(playerState.GetPosition().GetX()),
+			Y: int(playerState.GetPosition().GetY()),
+		},
+		Direction: playerState.GetDirection(),
+		Status:    playerState.GetStatus(),
+		Team:      playerState.GetTeam(),
+		HP:        int(playerState.GetHp()),
+		MaxHP:     int(playerState.GetMaxHp()),
+		Effects:   effects,
+		Weapon: Weapon{
+			Type:        playerState.GetWeapon().GetType(),
+			BulletReady: playerState.GetWeapon().GetBulletReady(),
+			Ammo:        int(playerState.GetWeapon().GetAmmo()),
+			MaxAmmo:     int(playerState.GetWeapon().GetMaxAmmo()),
+			Reloading:   playerState.GetWeapon().GetReloading(),
+			ActiveBombs: int(playerState.GetWeapon().GetActiveBombs()),
+			MaxBombs:    int(playerState.GetWeapon().GetMaxBombs()),
+		},
+		MoveTicks: int(playerState.GetMoveTicks()),
+		Name:      playerState.GetDisplayName(),
+		OutOfView: false,
+	}
+	if playerState.GetPlayerId() == g.myPlayerID {
+		g.viewSynced = true
+	}
+}
+
+// サーバーから受け取ったアイテムの状態を反映する
+func (g *Game) applyItemState(itemState *shared.ItemState) {
+	if itemState.GetStatus() == shared.ItemStatus_REMOVED {
+		delete(g.items, itemState.GetItemId())
+		return
+	}
+
+	g.items[itemState.GetItemId()] = Item{
+		ID:   itemState.GetItemId(),
+		Type: itemState.GetType(),
+		Position: Position{
+			X: int(itemState.GetPosition().GetX()),
+			Y: int(itemState.GetPosition().GetY()),
+		},
+		Weapon: itemState.GetWeapon(),
+	}
+}
+
+// サーバーから受け取ったマッチの状態を反映する
+func (g *Game) applyMatchState(matchState *shared.MatchState) {
+	scores := make(map[string]int)
+	for _, score := range matchState.GetScores() {
+		scores[score.GetPlayerId()] = int(score.GetScore())
+	}
+	teamScores := make(map[shared.Team]int)
+	for _, score := range matchState.GetTeamScores() {
+		teamScores[score.GetTeam()] = int(score.GetScore())
+	}
+	g.match = MatchStatus{
+		Mode:             matchState.GetMode(),
+		Phase:            matchState.GetPhase(),
+		RemainingSeconds: int(matchState.GetRemainingSeconds()),
+		Scores:           scores,
+		TeamScores:       teamScores,
+		WinnerIDs:        matchState.GetWinnerIds(),
+	}
+	// 盤面の大きさを送ってこない古いサーバーの場合は今の大きさのままにする
+	if matchState.GetBoardWidth() > 0 && matchState.GetBoardHeight() > 0 {
+		g.width = int(matchState.GetBoardWidth())
+		g.height = int(matchState.GetBoardHeight())
+	}
+	if matchState.GetTicksPerSecond() > 0 {
+		g.ticksPerSecond = int(matchState.GetTicksPerSecond())
+	}
+	g.interestRadius = int(matchState.GetInterestRadius())
+}
+
+// サーバーから受け取ったゲーム内の出来事をキルフィードに表示する
+func (g *Game) applyGameEvent(gameEvent *shared.GameEvent) {
+	if text := g.gameEventText(gameEvent); text != "" {
+		g.killFeed.Add(text)
+	}
+}
+
+type runOptions struct {
+	// 観戦者として接続する。プレイヤーとしては参加せず、他のプレイヤーを追いかけて表示する
+	Spectate bool
+	// 再生するリプレイファイル。指定した場合はサーバーに接続しない
+	Replay string
+	// リプレイで最初に追いかけるプレイヤーのIDか表示名。空なら最初のプレイヤー
+	Follow string
+
+	// 接続するMQTTブローカーのURLと認証情報
+	Broker   string
+	Username string
+	Password string
+	// 表示名。空ならOSのユーザー名を使う
+	Name  string
+	Theme Theme
+	Keys  KeyBindings
 }
 
 //nolint:funlen
-func Run() error {
+func Run(opts *runOptions) error {
 	screen, err := tcell.NewScreen()
 	if err != nil {
 		return errors.Wrap(err, "failed to create new screen")
@@ -333,60 +875,39 @@ func Run() error {
 		return errors.Wrap(err, "failed to initialize screen")
 	}
 
-	// MQTTクライアントの設定
-	clientID := uuid.New().String()
-	opts := mqtt.NewClientOptions().
-		AddBroker("tcp://localhost:1883").
-		SetClientID(clientID)
-
-	client := mqtt.NewClient(opts)
-	if token := client.Connect(); token.Wait() && token.Error() != nil {
-		return errors.Wrap(token.Error(), "failed to connect MQTT broker")
+	if opts.Replay != "" {
+		return runReplay(screen, opts)
 	}
-	defer client.Disconnect(250)
 
-	game := &Game{
-		mqtt:         client,
-		myPlayerID:   clientID,
-		screen:       screen,
-		width:        30,
-		height:       30,
-		players:      make(map[string]Player),
-		items:        make(map[string]Item),
-		messageStats: NewMessageStats(),
-	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
 
-	// プレイヤーをwidthとheightの範囲内でランダムに配置
-	game.players[clientID] = Player{
-		ID: clientID,
-		//nolint:gosec
-		Position:  Position{X: rand.Intn(game.width), Y: rand.Intn(game.height)},
-		Direction: shared.Direction_UP,
-		Status:    shared.Status_ALIVE,
+	// 接続が切れたら通知を受け取り、再接続できたら新しい接続を受け取る
+	lostChan := make(chan error, 1)
+	reconnectedChan := make(chan connection)
+
+	conn, err := connect(opts, lostChan)
+	if err != nil {
+		return err
 	}
 
-	// screenからのイベントを受け取る
-	eventChan := make(chan tcell.Event)
-	go func() {
-		for {
-			eventChan <- screen.PollEvent()
-		}
+	game := newGame(screen, conn, opts)
+	defer func() {
+		game.mqtt.Disconnect(250)
 	}()
 
 	// MQTTのメッセージを受け取る
 	messageChan := make(chan mqtt.Message)
-	handleMessage := func(client mqtt.Client, message mqtt.Message) {
-		messageChan <- message
-	}
-	token := game.mqtt.Subscribe("#", 0, handleMessage)
-	if token.Wait() && token.Error() != nil {
-		return errors.Wrap(token.Error(), "failed to subscribe to topics")
+	if err := game.subscribe(messageChan); err != nil {
+		return err
 	}
 
-	// 自分の初期位置を送信
-	game.publishMyState()
+	if !opts.Spectate {
+		game.joinAsPlayer()
+	}
 
 	// メインループ
+	eventChan := pollEvents(screen)
 	ticker := time.NewTicker(50 * time.Millisecond)
 	for {
 		select {
@@ -396,6 +917,22 @@ func Run() error {
 			}
 		case message := <-messageChan:
 			game.handleMessage(message)
+		case err := <-lostChan:
+			// 再接続中に繋がった接続がすぐに切れた場合は、受け取った接続の購読の失敗として扱う
+			if game.reconnecting {
+				continue
+			}
+			log.Printf("Connection lost: %v", err)
+			game.reconnecting = true
+			go reconnect(ctx, opts, lostChan, reconnectedChan)
+		case conn := <-reconnectedChan:
+			if err := game.resync(conn, messageChan); err != nil {
+				// 購読できなければ切断して、もう一度再接続する
+				log.Printf("Failed to resync: %v", err)
+				conn.client.Disconnect(250)
+				game.reconnecting = true
+				go reconnect(ctx, opts, lostChan, reconnectedChan)
+			}
 		case <-ticker.C:
 			game.messageStats.Calculate()
 			game.draw()
@@ -403,8 +940,138 @@ func Run() error {
 	}
 }
 
+// サーバーの全てのトピックを購読し、受け取ったメッセージをmessageChanに渡す
+// 購読するとサーバーから現在の盤面が全て送られてくる
+func (g *Game) subscribe(messageChan chan<- mqtt.Message) error {
+	handleMessage := func(client mqtt.Client, message mqtt.Message) {
+		messageChan <- message
+	}
+	token := g.mqtt.Subscribe("#", 0, handleMessage)
+	if token.Wait() && token.Error() != nil {
+		return errors.Wrap(token.Error(), "failed to subscribe to topics")
+	}
+	return nil
+}
+
+// リプレイファイルを読み込み、サーバーに接続せずに再生する
+func runReplay(screen tcell.Screen, opts *runOptions) error {
+	replay, err := LoadReplay(opts.Replay)
+	if err != nil {
+		return err
+	}
+
+	game := newGame(screen, connection{client: nil, clientID: ""}, opts)
+	game.replay = replay
+	game.resetReplay()
+	game.followPlayer(opts.Follow)
+
+	// メインループ。実時間の経過に合わせて再生位置を進める
+	eventChan := pollEvents(screen)
+	ticker := time.NewTicker(50 * time.Millisecond)
+	lastAt := time.Now()
+	for {
+		select {
+		case event := <-eventChan:
+			if game.handleEvent(event) {
+				return nil
+			}
+		case now := <-ticker.C:
+			game.advanceReplay(now.Sub(lastAt))
+			lastAt = now
+			game.draw()
+		}
+	}
+}
+
+// screenからのイベントを受け取るチャネルを返す
+func pollEvents(screen tcell.Screen) <-chan tcell.Event {
+	eventChan := make(chan tcell.Event)
+	go func() {
+		for {
+			eventChan <- screen.PollEvent()
+		}
+	}()
+	return eventChan
+}
+
+// リプレイの再生中はconnが空で、観戦者として扱う
+func newGame(screen tcell.Screen, conn connection, opts *runOptions) *Game {
+	return &Game{
+		mqtt:           conn.client,
+		myPlayerID:     conn.clientID,
+		screen:         screen,
+		width:          30,
+		height:         30,
+		ticksPerSecond: defaultTicksPerSecond,
+		interestRadius: 0,
+		viewSynced:     false,
+		players:        make(map[string]Player),
+		items:          make(map[string]Item),
+		match:          newMatchStatus(),
+		lastMovedAt:    time.Time{},
+		messageStats:   NewMessageStats(),
+		killFeed:       NewKillFeed(),
+		displayName:    opts.Name,
+		joinRetried:    false,
+		chatLog:        NewChatLog(),
+		chatting:       false,
+		chatScope:      shared.ChatScope_CHAT_SCOPE_ALL,
+		chatInput:      nil,
+		spectating:     opts.Spectate || opts.Replay != "",
+		followID:       "",
+		replay:         nil,
+		reconnecting:   false,
+		theme:          opts.Theme,
+		keys:           opts.Keys,
+	}
+}
+
+// 何も受け取っていない時のマッチの状態
+func newMatchStatus() MatchStatus {
+	return MatchStatus{Mode: shared.GameMode_DEATHMATCH, Phase: shared.MatchPhase_WAITING, RemainingSeconds: 0, Scores: map[string]int{}, TeamScores: map[shared.Team]int{}, WinnerIDs: nil}
+}
+
+// プレイヤーとしてゲームに参加する
+func (g *Game) joinAsPlayer() {
+	// プレイヤーをwidthとheightの範囲内でランダムに配置
+	g.players[g.myPlayerID] = Player{
+		ID: g.myPlayerID,
+		//nolint:gosec
+		Position:  Position{X: rand.Intn(g.width), Y: rand.Intn(g.height)},
+		Direction: shared.Direction_UP,
+		Status:    shared.Status_ALIVE,
+		Team:      shared.Team_NO_TEAM,
+		HP:        0,
+		MaxHP:     0,
+		Effects:   nil,
+		Weapon:    Weapon{Type: shared.WeaponType_PISTOL, BulletReady: true, Ammo: 0, MaxAmmo: 0, Reloading: false, ActiveBombs: 0, MaxBombs: 0},
+		MoveTicks: 0,
+		Name:      "",
+		OutOfView: false,
+	}
+	// サーバーから位置が届くまでは、ランダムな位置を基準に周りのものを忘れない
+	g.viewSynced = false
+
+	// 表示名を送ってゲームに参加する。再接続した場合は前に受け付けられた表示名で参加し直す
+	// 起動時に指定されていなければOSのユーザー名を使う
+	name := g.displayName
+	if name == "" {
+		name = defaultDisplayName()
+	}
+	g.join(name)
+
+	// 自分の初期位置を送信
+	// サーバーが移動速度を制限している場合は受け付けられず、サーバーが決めた位置が送られてくる
+	g.publishMyState()
+}
+
 func main() {
-	if err := Run(); err != nil {
+	opts, err := parseOptions(flag.CommandLine, os.Args[1:])
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := Run(opts); err != nil {
 		log.Fatal(err)
 	}
 }
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math/rand"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/google/uuid"
+	"github.com/shibayu36/terminal-shooter/shared"
+)
+
+const (
+	// 最初に再接続を試みるまでの間隔
+	reconnectInitialInterval = 500 * time.Millisecond
+	// 再接続を試みる間隔の上限
+	reconnectMaxInterval = 10 * time.Second
+)
+
+// connection サーバーとの1つの接続
+type connection struct {
+	client   mqtt.Client
+	clientID string
+}
+
+// connect optsのブローカーに接続する。接続が切れたらlostChanに通知する
+// 再接続の時にサーバーが前の接続の切断を検知する前に同じIDで接続すると、後から前のプレイヤーと一緒に削除されてしまうので、
+// 接続するたびに新しいクライアントIDを使う
+func connect(opts *runOptions, lostChan chan<- error) (connection, error) {
+	// 観戦者はクライアントIDの接頭辞でサーバーに伝える
+	clientID := uuid.New().String()
+	if opts.Spectate {
+		clientID = shared.SpectatorClientIDPrefix + clientID
+	}
+	mqttOpts := mqtt.NewClientOptions().
+		AddBroker(opts.Broker).
+		SetClientID(clientID).
+		SetUsername(opts.Username).
+		SetPassword(opts.Password).
+		// pahoの自動再接続は購読し直さず、盤面も古いままになるので使わない
+		SetAutoReconnect(false).
+		SetConnectionLostHandler(func(_ mqtt.Client, err error) {
+			select {
+			case lostChan <- err:
+			default:
+			}
+		})
+
+	client := mqtt.NewClient(mqttOpts)
+	if token := client.Connect(); token.Wait() && token.Error() != nil {
+		return connection{client: nil, clientID: ""}, errors.Wrap(token.Error(), "failed to connect MQTT broker")
+	}
+	return connection{client: client, clientID: clientID}, nil
+}
+
+// reconnect 接続できるまで間隔を空けながら再接続を試み、接続できたらreconnectedChanに渡す
+// ctxが終わったら諦める
+func reconnect(
+	ctx context.Context,
+	opts *runOptions,
+	lostChan chan<- error,
+	reconnectedChan chan<- connection,
+) {
+	backoff := newBackoff(reconnectInitialInterval, reconnectMaxInterval)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff.Next()):
+		}
+
+		conn, err := connect(opts, lostChan)
+		if err != nil {
+			log.Printf("Failed to reconnect: %v", err)
+			continue
+		}
+
+		select {
+		case reconnectedChan <- conn:
+		case <-ctx.Done():
+			conn.client.Disconnect(250)
+		}
+		return
+	}
+}
+
+// backoff 再接続を試みる間隔。失敗するたびに倍にし、上限で頭打ちにする
+type backoff struct {
+	current time.Duration
+	max     time.Duration
+}
+
+func newBackoff(initial, maxInterval time.Duration) *backoff {
+	return &backoff{current: initial, max: maxInterval}
+}
+
+// Next 次に試みるまでの間隔を返す
+// 多くのクライアントが同時に再接続しないように、最大で半分だけ短くする
+func (b *backoff) Next() time.Duration {
+	interval := b.current
+	b.current = min(b.current*2, b.max)
+	//nolint:gosec
+	return interval - time.Duration(rand.Int63n(int64(interval)/2+1))
+}
+
+// 接続し直したサーバーから全ての状態を受け取り直す
+// 古いプレイヤーやアイテムは消してから購読し直し、プレイヤーであれば参加し直す
+func (g *Game) resync(conn connection, messageChan chan<- mqtt.Message) error {
+	g.mqtt = conn.client
+	g.myPlayerID = conn.clientID
+	g.players = make(map[string]Player)
+	g.items = make(map[string]Item)
+	g.match = newMatchStatus()
+	g.followID = ""
+	g.joinRetried = false
+	g.reconnecting = false
+
+	if err := g.subscribe(messageChan); err != nil {
+		return err
+	}
+	if !g.spectating {
+		g.joinAsPlayer()
+	}
+	g.killFeed.Add("Reconnected")
+	return nil
+}
@@ -0,0 +1,268 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gdamore/tcell/v2"
+	"github.com/shibayu36/terminal-shooter/shared"
+)
+
+// 再生速度の選択肢。+と-で切り替える
+var replaySpeeds = []float64{0.25, 0.5, 1, 2, 4, 8} //nolint:gochecknoglobals
+
+// 再生速度の初期値のreplaySpeedsのインデックス
+const defaultReplaySpeedIndex = 2
+
+// 左右キーで移動する秒数
+const replaySeekSeconds = 5
+
+// Replay 記録されたマッチを読み込み、再生位置を管理する
+type Replay struct {
+	header *shared.ReplayHeader
+	// 記録されたtick。tickの順に並ぶ
+	ticks []*shared.ReplayTick
+
+	// 現在表示しているtick
+	current int64
+	// 次に盤面に反映するticksのインデックス
+	next int
+	// 前回進めてから、まだ反映していない端数のtick
+	fraction float64
+
+	paused     bool
+	speedIndex int
+}
+
+// LoadReplay リプレイファイルを全て読み込む
+// サーバーが途中で止まって末尾が欠けている場合は、読み込めたところまでを使う
+func LoadReplay(path string) (*Replay, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to open replay file: %s", path)
+	}
+	defer file.Close()
+
+	reader, err := shared.NewReplayReader(file)
+	if err != nil {
+		return nil, err
+	}
+	if reader.Header().GetProtocolVersion() != shared.ProtocolVersion {
+		return nil, errors.Newf(
+			"unsupported replay protocol version: %s (client: %s)",
+			reader.Header().GetProtocolVersion(),
+			shared.ProtocolVersion,
+		)
+	}
+
+	var ticks []*shared.ReplayTick
+	for {
+		tick, err := reader.Next()
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		ticks = append(ticks, tick)
+	}
+
+	return &Replay{
+		header:     reader.Header(),
+		ticks:      ticks,
+		current:    reader.Header().GetStartTick(),
+		next:       0,
+		fraction:   0,
+		paused:     false,
+		speedIndex: defaultReplaySpeedIndex,
+	}, nil
+}
+
+// StartTick 記録を開始したtick
+func (r *Replay) StartTick() int64 {
+	return r.header.GetStartTick()
+}
+
+// EndTick 最後に記録されたtick
+func (r *Replay) EndTick() int64 {
+	if len(r.ticks) == 0 {
+		return r.StartTick()
+	}
+	return r.ticks[len(r.ticks)-1].GetTick()
+}
+
+// Speed 再生速度の倍率
+func (r *Replay) Speed() float64 {
+	return replaySpeeds[r.speedIndex]
+}
+
+// changeSpeed 再生速度をdelta段階変える
+func (r *Replay) changeSpeed(delta int) {
+	r.speedIndex = clamp(r.speedIndex+delta, 0, len(replaySpeeds)-1)
+}
+
+// seekReplay 指定したtickの盤面を表示する
+// 前に戻る場合は記録を開始した時点の盤面から反映し直す
+func (g *Game) seekReplay(tick int64) {
+	r := g.replay
+	tick = max(r.StartTick(), min(tick, r.EndTick()))
+
+	if tick < r.current {
+		g.resetReplay()
+	}
+	for r.next < len(r.ticks) && r.ticks[r.next].GetTick() <= tick {
+		g.applyReplayTick(r.ticks[r.next])
+		r.next++
+	}
+	r.current = tick
+	r.fraction = 0
+}
+
+// resetReplay 盤面を記録を開始した時点に戻す
+func (g *Game) resetReplay() {
+	r := g.replay
+	header := r.header
+
+	g.width = int(header.GetMap().GetWidth())
+	g.height = int(header.GetMap().GetHeight())
+	g.players = make(map[string]Player)
+	g.items = make(map[string]Item)
+	g.killFeed = NewKillFeed()
+	for _, playerState := range header.GetPlayers() {
+		g.applyPlayerState(playerState)
+	}
+	for _, itemState := range header.GetItems() {
+		g.applyItemState(itemState)
+	}
+	g.applyMatchState(header.GetMatch())
+
+	r.current = r.StartTick()
+	r.next = 0
+	r.fraction = 0
+}
+
+// applyReplayTick 記録された1tickの状態の変化を盤面に反映する
+func (g *Game) applyReplayTick(tick *shared.ReplayTick) {
+	for _, playerState := range tick.GetPlayers() {
+		g.applyPlayerState(playerState)
+	}
+	for _, itemState := range tick.GetItems() {
+		g.applyItemState(itemState)
+	}
+	if tick.GetMatch() != nil {
+		g.applyMatchState(tick.GetMatch())
+	}
+	for _, gameEvent := range tick.GetEvents() {
+		g.applyGameEvent(gameEvent)
+		// 抜けたプレイヤーの状態は記録されないので、出来事から削除する
+		if playerLeft := gameEvent.GetPlayerLeft(); playerLeft != nil {
+			delete(g.players, playerLeft.GetPlayerId())
+		}
+	}
+}
+
+// advanceReplay 実時間でelapsedが経過した分だけ、再生速度に合わせて再生位置を進める
+// 最後まで再生したら一時停止する
+func (g *Game) advanceReplay(elapsed time.Duration) {
+	r := g.replay
+	if r.paused {
+		return
+	}
+
+	r.fraction += elapsed.Seconds() * float64(g.ticksPerSecond) * r.Speed()
+	ticks := int64(r.fraction)
+	fraction := r.fraction - float64(ticks)
+	g.seekReplay(r.current + ticks)
+	r.fraction = fraction
+
+	if r.current >= r.EndTick() {
+		r.paused = true
+	}
+}
+
+// 左右キーで移動するtick数
+func (g *Game) replaySeekTicks() int64 {
+	return int64(replaySeekSeconds * g.ticksPerSecond)
+}
+
+// リプレイ中のキー入力を処理する
+// 終了する場合はtrueを返す
+func (g *Game) handleReplayKey(ev *tcell.EventKey) bool {
+	r := g.replay
+
+	//nolint:exhaustive
+	switch ev.Key() {
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		return true
+	case tcell.KeyLeft:
+		g.seekReplay(r.current - g.replaySeekTicks())
+	case tcell.KeyRight:
+		g.seekReplay(r.current + g.replaySeekTicks())
+	case tcell.KeyHome:
+		g.seekReplay(r.StartTick())
+	case tcell.KeyEnd:
+		g.seekReplay(r.EndTick())
+	case tcell.KeyUp:
+		r.changeSpeed(1)
+	case tcell.KeyDown:
+		r.changeSpeed(-1)
+	case tcell.KeyTab:
+		g.followNext(1)
+	case tcell.KeyBacktab:
+		g.followNext(-1)
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case ' ':
+			// 最後まで再生した後は最初から再生し直す
+			if r.paused && r.current >= r.EndTick() {
+				g.seekReplay(r.StartTick())
+			}
+			r.paused = !r.paused
+		case ',':
+			r.paused = true
+			g.seekReplay(r.current - 1)
+		case '.':
+			r.paused = true
+			g.seekReplay(r.current + 1)
+		case '<':
+			g.seekReplay(r.current - g.replaySeekTicks())
+		case '>':
+			g.seekReplay(r.current + g.replaySeekTicks())
+		case '+':
+			r.changeSpeed(1)
+		case '-':
+			r.changeSpeed(-1)
+		default:
+			// 数字キーで全体の0%から90%の位置に移動する
+			if ev.Rune() >= '0' && ev.Rune() <= '9' {
+				length := r.EndTick() - r.StartTick()
+				g.seekReplay(r.StartTick() + length*int64(ev.Rune()-'0')/10)
+			}
+		}
+	}
+	return false
+}
+
+// 再生位置と再生速度を表示用の文字列にする
+func (g *Game) replayText() string {
+	r := g.replay
+	state := "Playing"
+	if r.paused {
+		state = "Paused"
+	}
+	return fmt.Sprintf("Replay %s / %s x%g %s (Space:pause ,/.:step ←/→ or </>:seek +/-:speed)",
+		formatTicks(r.current-r.StartTick(), g.ticksPerSecond),
+		formatTicks(r.EndTick()-r.StartTick(), g.ticksPerSecond),
+		r.Speed(),
+		state,
+	)
+}
+
+// tick数を分:秒.ミリ秒の文字列にする
+func formatTicks(ticks int64, ticksPerSecond int) string {
+	d := time.Duration(ticks) * time.Second / time.Duration(ticksPerSecond)
+	return fmt.Sprintf("%d:%02d.%03d", int(d.Minutes()), int(d.Seconds())%60, d.Milliseconds()%1000)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+// 観戦中のキー入力を処理する。左右キーかTabで追いかけるプレイヤーを切り替える
+// 終了する場合はtrueを返す
+func (g *Game) handleSpectateKey(ev *tcell.EventKey) bool {
+	//nolint:exhaustive
+	switch ev.Key() {
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		return true
+	case tcell.KeyLeft, tcell.KeyBacktab:
+		g.followNext(-1)
+	case tcell.KeyRight, tcell.KeyTab:
+		g.followNext(1)
+	}
+	return false
+}
+
+// 追いかけるプレイヤーをID順でdelta個先のプレイヤーに切り替える
+func (g *Game) followNext(delta int) {
+	playerIDs := slices.Sorted(maps.Keys(g.players))
+	if len(playerIDs) == 0 {
+		g.followID = ""
+		return
+	}
+
+	index := slices.Index(playerIDs, g.followID)
+	if index < 0 {
+		g.followID = playerIDs[0]
+		return
+	}
+	g.followID = playerIDs[((index+delta)%len(playerIDs)+len(playerIDs))%len(playerIDs)]
+}
+
+// IDか表示名が一致するプレイヤーを追いかける。見つからなければ最初のプレイヤーを追いかける
+func (g *Game) followPlayer(idOrName string) {
+	for _, playerID := range slices.Sorted(maps.Keys(g.players)) {
+		player := g.players[playerID]
+		if idOrName != "" && (player.ID == idOrName || strings.EqualFold(player.Name, idOrName)) {
+			g.followID = playerID
+			return
+		}
+	}
+	g.followID = ""
+	g.followNext(0)
+}
+
+// 画面の中心に表示するプレイヤー
+// 観戦中は追いかけているプレイヤーで、いなくなっていたら別のプレイヤーに切り替える
+func (g *Game) viewedPlayer() Player {
+	if !g.spectating {
+		return g.getMyPlayer()
+	}
+	if _, ok := g.players[g.followID]; !ok {
+		g.followNext(0)
+	}
+	return g.players[g.followID]
+}
+
+// 観戦中であることと、追いかけているプレイヤーを表示用の文字列にする
+func (g *Game) spectateText() string {
+	if len(g.players) == 0 {
+		return "Spectating: no players"
+	}
+	if g.replay != nil {
+		return fmt.Sprintf("Following: %s (Tab to switch)", g.playerName(g.viewedPlayer().ID))
+	}
+	return fmt.Sprintf("Spectating: %s (<-/-> to switch)", g.playerName(g.viewedPlayer().ID))
+}
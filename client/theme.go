@@ -0,0 +1,96 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/gdamore/tcell/v2"
+)
+
+// Theme 画面の色の組み合わせ
+type Theme struct {
+	Background  tcell.Color
+	Map         tcell.Color
+	MyPlayer    tcell.Color
+	OtherPlayer tcell.Color
+	RedTeam     tcell.Color
+	BlueTeam    tcell.Color
+	Item        tcell.Color
+	Bomb        tcell.Color
+	Fire        tcell.Color
+	PowerUp     tcell.Color
+	Wall        tcell.Color
+	Block       tcell.Color
+	Name        tcell.Color
+	// マップの下に表示する情報の文字色
+	Text tcell.Color
+}
+
+// 指定しなかった場合に使うテーマ
+const defaultThemeName = "dark"
+
+// 選べるテーマ
+var themes = map[string]Theme{ //nolint:gochecknoglobals
+	// 黒い背景
+	"dark": {
+		Background:  tcell.Color232,
+		Map:         tcell.Color255,
+		MyPlayer:    tcell.Color46,
+		OtherPlayer: tcell.Color196,
+		RedTeam:     tcell.Color196,
+		BlueTeam:    tcell.Color33,
+		Item:        tcell.Color226,
+		Bomb:        tcell.Color208,
+		Fire:        tcell.Color196,
+		PowerUp:     tcell.Color51,
+		Wall:        tcell.Color245,
+		Block:       tcell.Color130,
+		Name:        tcell.Color244,
+		Text:        tcell.ColorWhite,
+	},
+	// 白い背景
+	"light": {
+		Background:  tcell.Color255,
+		Map:         tcell.Color240,
+		MyPlayer:    tcell.Color28,
+		OtherPlayer: tcell.Color160,
+		RedTeam:     tcell.Color160,
+		BlueTeam:    tcell.Color25,
+		Item:        tcell.Color136,
+		Bomb:        tcell.Color166,
+		Fire:        tcell.Color160,
+		PowerUp:     tcell.Color30,
+		Wall:        tcell.Color243,
+		Block:       tcell.Color94,
+		Name:        tcell.Color245,
+		Text:        tcell.ColorBlack,
+	},
+	// 端末の色をそのまま使う。256色を表示できない端末向け
+	"mono": {
+		Background:  tcell.ColorDefault,
+		Map:         tcell.ColorDefault,
+		MyPlayer:    tcell.ColorDefault,
+		OtherPlayer: tcell.ColorDefault,
+		RedTeam:     tcell.ColorDefault,
+		BlueTeam:    tcell.ColorDefault,
+		Item:        tcell.ColorDefault,
+		Bomb:        tcell.ColorDefault,
+		Fire:        tcell.ColorDefault,
+		PowerUp:     tcell.ColorDefault,
+		Wall:        tcell.ColorDefault,
+		Block:       tcell.ColorDefault,
+		Name:        tcell.ColorDefault,
+		Text:        tcell.ColorDefault,
+	},
+}
+
+// findTheme 名前からテーマを取得する
+func findTheme(name string) (Theme, error) {
+	theme, ok := themes[strings.ToLower(name)]
+	if !ok {
+		return Theme{}, errors.Newf("unknown theme: %s (available: %s)", name, strings.Join(slices.Sorted(maps.Keys(themes)), ", "))
+	}
+	return theme, nil
+}
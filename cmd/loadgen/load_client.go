@@ -0,0 +1,208 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/shibayu36/terminal-shooter/shared"
+	"google.golang.org/protobuf/proto"
+)
+
+// サーバーが受け付ける表示名の最大文字数
+const maxDisplayNameLength = 16
+
+// pendingMove 送信したが、まだサーバーから配信されていない移動
+type pendingMove struct {
+	position *shared.Position
+	sentAt   time.Time
+}
+
+// loadClient 1つのMQTT接続を持ち、ランダムに移動し、弾を撃ち、ボムを置くクライアント
+type loadClient struct {
+	id     string
+	name   string
+	opts   *loadOptions
+	stats  *loadStats
+	rng    *rand.Rand
+	client mqtt.Client
+
+	// サーバーから配信された自分の位置
+	position *shared.Position
+	pending  *pendingMove
+
+	mu sync.Mutex `exhaustruct:"optional"`
+}
+
+func newLoadClient(index int, opts *loadOptions, stats *loadStats) *loadClient {
+	return &loadClient{
+		id:    fmt.Sprintf("loadgen-%d-%d", opts.Seed, index),
+		name:  loadClientName(opts.Seed, index),
+		opts:  opts,
+		stats: stats,
+		//nolint:gosec
+		rng:      rand.New(rand.NewSource(opts.Seed + int64(index))),
+		client:   nil,
+		position: &shared.Position{X: 0, Y: 0},
+		pending:  nil,
+	}
+}
+
+// loadClientName シードと番号から表示名を作る
+// 複数のloadgenを同時に動かしても重ならないようにシードを含め、長すぎる場合はシードの上位の桁を削る
+func loadClientName(seed int64, index int) string {
+	suffix := "-" + strconv.Itoa(index)
+	seedHex := strconv.FormatUint(uint64(seed), 16)
+	if maxLength := maxDisplayNameLength - len("l") - len(suffix); len(seedHex) > maxLength {
+		seedHex = seedHex[len(seedHex)-maxLength:]
+	}
+	return "l" + seedHex + suffix
+}
+
+// Connect ブローカーに接続して全てのトピックを購読し、ゲームに参加する
+func (c *loadClient) Connect() error {
+	mqttOpts := mqtt.NewClientOptions().
+		AddBroker(c.opts.Broker).
+		SetClientID(c.id).
+		SetAutoReconnect(false).
+		SetConnectionLostHandler(func(_ mqtt.Client, _ error) {
+			c.stats.disconnects.Add(1)
+			c.stats.connected.Add(-1)
+		})
+	c.client = mqtt.NewClient(mqttOpts)
+
+	start := time.Now()
+	if token := c.client.Connect(); token.Wait() && token.Error() != nil {
+		c.stats.connectErrors.Add(1)
+		return errors.Wrap(token.Error(), "failed to connect MQTT broker")
+	}
+	c.stats.connectLatency.Record(time.Since(start))
+	c.stats.connected.Add(1)
+
+	if token := c.client.Subscribe("#", 0, c.onMessage); token.Wait() && token.Error() != nil {
+		return errors.Wrap(token.Error(), "failed to subscribe to topics")
+	}
+
+	return c.publish("join", &shared.JoinRequest{
+		DisplayName:   c.name,
+		PreferredTeam: shared.Team_NO_TEAM,
+		ClientVersion: shared.ProtocolVersion,
+	})
+}
+
+// Disconnect 接続を閉じる。自分から切断した場合は切断数に数えない
+func (c *loadClient) Disconnect() {
+	if c.client == nil || !c.client.IsConnected() {
+		return
+	}
+	c.client.Disconnect(250)
+	c.stats.connected.Add(-1)
+}
+
+// Run ctxが終わるまで、設定された頻度で行動する
+func (c *loadClient) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.opts.ActionInterval)
+	defer ticker.Stop()
+
+	seconds := c.opts.ActionInterval.Seconds()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if c.rng.Float64() < c.opts.MoveRate*seconds {
+				c.move()
+			}
+			if c.rng.Float64() < c.opts.ShootRate*seconds {
+				c.action(shared.ActionType_SHOOT_BULLET)
+			}
+			if c.rng.Float64() < c.opts.BombRate*seconds {
+				c.action(shared.ActionType_PLACE_BOMB)
+			}
+		}
+	}
+}
+
+// move ランダムな方向に1マス移動する
+// 盤面の外や障害物への移動はサーバーに拒否され、サーバーの位置に戻される
+func (c *loadClient) move() {
+	directions := []shared.Direction{
+		shared.Direction_UP,
+		shared.Direction_DOWN,
+		shared.Direction_LEFT,
+		shared.Direction_RIGHT,
+	}
+
+	c.mu.Lock()
+	direction := directions[c.rng.Intn(len(directions))]
+	next := &shared.Position{X: c.position.GetX(), Y: c.position.GetY()}
+	switch direction {
+	case shared.Direction_UP:
+		next.Y--
+	case shared.Direction_DOWN:
+		next.Y++
+	case shared.Direction_LEFT:
+		next.X--
+	case shared.Direction_RIGHT:
+		next.X++
+	}
+	c.pending = &pendingMove{position: next, sentAt: time.Now()}
+	c.mu.Unlock()
+
+	_ = c.publish("player_state", &shared.PlayerState{
+		PlayerId:  c.id,
+		Position:  next,
+		Direction: direction,
+	})
+}
+
+func (c *loadClient) action(actionType shared.ActionType) {
+	_ = c.publish("player_action", &shared.PlayerActionRequest{
+		Type: actionType,
+	})
+}
+
+func (c *loadClient) publish(topic string, message proto.Message) error {
+	payload, err := proto.Marshal(message)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s", topic)
+	}
+	c.stats.published.Add(1)
+	if token := c.client.Publish(topic, 0, false, payload); token.Wait() && token.Error() != nil {
+		c.stats.publishErrors.Add(1)
+		return errors.Wrapf(token.Error(), "failed to publish %s", topic)
+	}
+	return nil
+}
+
+// onMessage 受信したメッセージを数え、自分の状態なら位置を更新して伝搬時間を記録する
+func (c *loadClient) onMessage(_ mqtt.Client, message mqtt.Message) {
+	c.stats.received.Add(1)
+	c.stats.receivedBytes.Add(int64(len(message.Payload())))
+
+	if message.Topic() != "player_state" {
+		return
+	}
+	playerState := &shared.PlayerState{}
+	if err := proto.Unmarshal(message.Payload(), playerState); err != nil {
+		return
+	}
+	if playerState.GetPlayerId() != c.id || playerState.GetPosition() == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.position = playerState.GetPosition()
+	if c.pending != nil &&
+		c.pending.position.GetX() == c.position.GetX() &&
+		c.pending.position.GetY() == c.position.GetY() {
+		c.stats.propagationLatency.Record(time.Since(c.pending.sentAt))
+		c.pending = nil
+	}
+}
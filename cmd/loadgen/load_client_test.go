@@ -0,0 +1,18 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_loadClientName(t *testing.T) {
+	assert.Equal(t, "lff-3", loadClientName(0xff, 3))
+
+	// シードが長くても表示名の上限に収まり、番号は残る
+	name := loadClientName(0x7123456789abcdef, 120)
+	assert.Equal(t, "l56789abcdef-120", name)
+	assert.LessOrEqual(t, len(name), maxDisplayNameLength)
+
+	assert.NotEqual(t, loadClientName(1, 0), loadClientName(2, 0), "シードが違えば表示名も違う")
+}
@@ -0,0 +1,133 @@
+// loadgenは、多数のMQTTクライアントでサーバーに接続して負荷をかけ、
+// 接続時間や状態の伝搬時間、メッセージのレート、切断数を報告する
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+func main() {
+	opts := &loadOptions{
+		Broker:         "tcp://localhost:1883",
+		Clients:        50,
+		ConnectRate:    20,
+		Duration:       time.Minute,
+		ActionInterval: 50 * time.Millisecond,
+		MoveRate:       5,
+		ShootRate:      1,
+		BombRate:       0.1,
+		ReportInterval: 5 * time.Second,
+		Seed:           time.Now().UnixNano(),
+	}
+	flag.StringVar(&opts.Broker, "broker", opts.Broker, "MQTT broker URL")
+	flag.IntVar(&opts.Clients, "clients", opts.Clients, "number of clients to connect")
+	flag.Float64Var(&opts.ConnectRate, "connect-rate", opts.ConnectRate, "new connections per second")
+	flag.DurationVar(&opts.Duration, "duration", opts.Duration, "how long to keep the load after all clients connected")
+	flag.DurationVar(&opts.ActionInterval, "action-interval", opts.ActionInterval, "how often each client decides its next action")
+	flag.Float64Var(&opts.MoveRate, "move-rate", opts.MoveRate, "moves per second per client")
+	flag.Float64Var(&opts.ShootRate, "shoot-rate", opts.ShootRate, "shots per second per client")
+	flag.Float64Var(&opts.BombRate, "bomb-rate", opts.BombRate, "bombs per second per client")
+	flag.DurationVar(&opts.ReportInterval, "report-interval", opts.ReportInterval, "how often to print the statistics")
+	flag.Int64Var(&opts.Seed, "seed", opts.Seed, "random seed for client behavior")
+	flag.Parse()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	run(ctx, opts)
+}
+
+type loadOptions struct {
+	// 接続するMQTTブローカー
+	Broker string
+	// 接続するクライアント数
+	Clients int
+	// 1秒あたりに接続するクライアント数
+	ConnectRate float64
+	// 全てのクライアントの接続を始めてから負荷をかけ続ける時間
+	Duration time.Duration
+	// 各クライアントが行動を決める間隔
+	ActionInterval time.Duration
+	// クライアントごとの1秒あたりの移動、弾、ボムの回数
+	MoveRate  float64
+	ShootRate float64
+	BombRate  float64
+	// 計測結果を出力する間隔
+	ReportInterval time.Duration
+	// クライアントの行動を決める乱数のシード
+	Seed int64
+}
+
+func run(ctx context.Context, opts *loadOptions) {
+	stats := newLoadStats()
+	start := time.Now()
+	reporter := newReporter(stats, start)
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// 計測結果を定期的に出力する
+	go func() {
+		ticker := time.NewTicker(opts.ReportInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				fmt.Printf("[%s]\n%s\n", now.Sub(start).Round(time.Second), reporter.Report(now))
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	clients := make([]*loadClient, 0, opts.Clients)
+	connectInterval := time.Duration(float64(time.Second) / opts.ConnectRate)
+
+connecting:
+	for i := range opts.Clients {
+		client := newLoadClient(i, opts, stats)
+		clients = append(clients, client)
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.Connect(); err != nil {
+				slog.Error(fmt.Sprintf("client %s failed to connect\n%+v", client.id, err))
+				return
+			}
+			client.Run(ctx)
+		}()
+
+		select {
+		case <-ctx.Done():
+			break connecting
+		case <-time.After(connectInterval):
+		}
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-time.After(opts.Duration):
+	}
+	cancel()
+	wg.Wait()
+
+	now := time.Now()
+	fmt.Printf("[final %s]\n%s\n", now.Sub(start).Round(time.Second), reporter.Report(now))
+
+	for _, client := range clients {
+		client.Disconnect()
+	}
+	if stats.connectErrors.Load() > 0 || stats.disconnects.Load() > 0 {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencyRecorder 計測した所要時間を溜めて、パーセンタイルを計算する
+type latencyRecorder struct {
+	durations []time.Duration
+
+	mu sync.Mutex `exhaustruct:"optional"`
+}
+
+func newLatencyRecorder() *latencyRecorder {
+	return &latencyRecorder{
+		durations: nil,
+	}
+}
+
+func (r *latencyRecorder) Record(d time.Duration) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.durations = append(r.durations, d)
+}
+
+// latencySummary 所要時間の分布
+type latencySummary struct {
+	Count int
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+func (s latencySummary) String() string {
+	if s.Count == 0 {
+		return "n=0"
+	}
+	return fmt.Sprintf("n=%d p50=%s p95=%s p99=%s max=%s",
+		s.Count,
+		s.P50.Round(time.Microsecond),
+		s.P95.Round(time.Microsecond),
+		s.P99.Round(time.Microsecond),
+		s.Max.Round(time.Microsecond),
+	)
+}
+
+// Summary これまでに記録した所要時間の分布を計算する
+func (r *latencyRecorder) Summary() latencySummary {
+	r.mu.Lock()
+	sorted := append([]time.Duration(nil), r.durations...)
+	r.mu.Unlock()
+
+	if len(sorted) == 0 {
+		return latencySummary{Count: 0, P50: 0, P95: 0, P99: 0, Max: 0}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	return latencySummary{
+		Count: len(sorted),
+		P50:   percentile(sorted, 50),
+		P95:   percentile(sorted, 95),
+		P99:   percentile(sorted, 99),
+		Max:   sorted[len(sorted)-1],
+	}
+}
+
+// percentile 昇順に並んだsortedのpパーセンタイルを最近傍法で返す
+func percentile(sorted []time.Duration, p int) time.Duration {
+	index := (len(sorted)*p+99)/100 - 1
+	return sorted[max(0, min(index, len(sorted)-1))]
+}
+
+// loadStats 負荷試験全体の計測結果
+type loadStats struct {
+	// 接続にかかった時間
+	connectLatency *latencyRecorder
+	// 移動を送信してから、サーバーから自分の新しい位置が配信されるまでの時間
+	propagationLatency *latencyRecorder
+
+	connected     atomic.Int64 `exhaustruct:"optional"`
+	connectErrors atomic.Int64 `exhaustruct:"optional"`
+	disconnects   atomic.Int64 `exhaustruct:"optional"`
+	publishErrors atomic.Int64 `exhaustruct:"optional"`
+	published     atomic.Int64 `exhaustruct:"optional"`
+	received      atomic.Int64 `exhaustruct:"optional"`
+	receivedBytes atomic.Int64 `exhaustruct:"optional"`
+}
+
+func newLoadStats() *loadStats {
+	return &loadStats{
+		connectLatency:     newLatencyRecorder(),
+		propagationLatency: newLatencyRecorder(),
+	}
+}
+
+// reporter 前回の報告からのメッセージ数の差分でレートを計算する
+type reporter struct {
+	stats *loadStats
+
+	lastAt       time.Time
+	lastReceived int64
+	lastBytes    int64
+	lastSent     int64
+}
+
+func newReporter(stats *loadStats, start time.Time) *reporter {
+	return &reporter{
+		stats:        stats,
+		lastAt:       start,
+		lastReceived: 0,
+		lastBytes:    0,
+		lastSent:     0,
+	}
+}
+
+// Report 前回の報告からnowまでのレートと、これまでの累計を文字列にする
+func (r *reporter) Report(now time.Time) string {
+	elapsed := now.Sub(r.lastAt).Seconds()
+	received := r.stats.received.Load()
+	bytes := r.stats.receivedBytes.Load()
+	sent := r.stats.published.Load()
+
+	var receiveRate, byteRate, sendRate float64
+	if elapsed > 0 {
+		receiveRate = float64(received-r.lastReceived) / elapsed
+		byteRate = float64(bytes-r.lastBytes) / elapsed
+		sendRate = float64(sent-r.lastSent) / elapsed
+	}
+	r.lastAt, r.lastReceived, r.lastBytes, r.lastSent = now, received, bytes, sent
+
+	return fmt.Sprintf(
+		"clients=%d connect_errors=%d disconnects=%d publish_errors=%d\n"+
+			"  recv=%.1f msg/s (%.1f KB/s) sent=%.1f msg/s\n"+
+			"  connect:     %s\n"+
+			"  propagation: %s",
+		r.stats.connected.Load(),
+		r.stats.connectErrors.Load(),
+		r.stats.disconnects.Load(),
+		r.stats.publishErrors.Load(),
+		receiveRate,
+		byteRate/1024,
+		sendRate,
+		r.stats.connectLatency.Summary(),
+		r.stats.propagationLatency.Summary(),
+	)
+}
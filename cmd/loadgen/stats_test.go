@@ -0,0 +1,48 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLatencyRecorder_Summary(t *testing.T) {
+	recorder := newLatencyRecorder()
+	assert.Equal(t, 0, recorder.Summary().Count)
+	assert.Equal(t, "n=0", recorder.Summary().String())
+
+	// 1msから100msまでを逆順に記録する
+	for i := 100; i >= 1; i-- {
+		recorder.Record(time.Duration(i) * time.Millisecond)
+	}
+
+	summary := recorder.Summary()
+	assert.Equal(t, 100, summary.Count)
+	assert.Equal(t, 50*time.Millisecond, summary.P50)
+	assert.Equal(t, 95*time.Millisecond, summary.P95)
+	assert.Equal(t, 99*time.Millisecond, summary.P99)
+	assert.Equal(t, 100*time.Millisecond, summary.Max)
+}
+
+func TestReporter_Report(t *testing.T) {
+	stats := newLoadStats()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	reporter := newReporter(stats, start)
+
+	stats.connected.Add(3)
+	stats.received.Add(200)
+	stats.receivedBytes.Add(2048)
+	stats.published.Add(20)
+	stats.disconnects.Add(1)
+
+	report := reporter.Report(start.Add(2 * time.Second))
+	assert.True(t, strings.HasPrefix(report, "clients=3 connect_errors=0 disconnects=1 publish_errors=0"), report)
+	assert.Contains(t, report, "recv=100.0 msg/s (1.0 KB/s) sent=10.0 msg/s")
+
+	// レートは前回の報告からの差分で計算する
+	stats.received.Add(10)
+	report = reporter.Report(start.Add(3 * time.Second))
+	assert.Contains(t, report, "recv=10.0 msg/s (0.0 KB/s) sent=0.0 msg/s")
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/shibayu36/terminal-shooter/server/game"
+)
+
+// ミュートの期間が指定されなかった時に使う期間
+const defaultMuteDuration = 5 * time.Minute
+
+// newAdminHandler チャットのミュートやBANを操作する管理用のHTTPハンドラを作る
+// いずれもPOSTで接続中のプレイヤーのplayer_idを指定し、その接続元のホストに対して操作する。muteはdurationで期間を指定できる
+// メトリクスと同じポートで提供するので、Authorization: Bearer <token>でtokenを送ったリクエストだけを受け付ける
+// tokenが空の場合は全て拒否する
+func newAdminHandler(moderator *ChatModerator, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /admin/chat/mute", func(w http.ResponseWriter, r *http.Request) {
+		playerID, ok := adminPlayerID(w, r)
+		if !ok {
+			return
+		}
+		duration := defaultMuteDuration
+		if value := r.FormValue("duration"); value != "" {
+			parsed, err := time.ParseDuration(value)
+			if err != nil || parsed <= 0 {
+				http.Error(w, fmt.Sprintf("invalid duration: %s", value), http.StatusBadRequest)
+				return
+			}
+			duration = parsed
+		}
+		respondModeration(w, moderator.Mute(playerID, time.Now().Add(duration)))
+	})
+	mux.HandleFunc("POST /admin/chat/unmute", func(w http.ResponseWriter, r *http.Request) {
+		if playerID, ok := adminPlayerID(w, r); ok {
+			respondModeration(w, moderator.Unmute(playerID))
+		}
+	})
+	mux.HandleFunc("POST /admin/chat/ban", func(w http.ResponseWriter, r *http.Request) {
+		if playerID, ok := adminPlayerID(w, r); ok {
+			respondModeration(w, moderator.Ban(playerID))
+		}
+	})
+	mux.HandleFunc("POST /admin/chat/unban", func(w http.ResponseWriter, r *http.Request) {
+		if playerID, ok := adminPlayerID(w, r); ok {
+			respondModeration(w, moderator.Unban(playerID))
+		}
+	})
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !authorizedAdmin(r, token) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// authorizedAdmin リクエストが管理用のtokenを持っているか
+func authorizedAdmin(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// respondModeration ミュートやBANの操作の結果を返す。接続していないプレイヤーを指定した場合は404を返す
+func respondModeration(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrChatPlayerNotConnected):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	case err != nil:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// adminPlayerID リクエストから操作対象のプレイヤーIDを取り出す。なければ400を返す
+func adminPlayerID(w http.ResponseWriter, r *http.Request) (game.PlayerID, bool) {
+	playerID := r.FormValue("player_id")
+	if playerID == "" {
+		http.Error(w, "player_id is required", http.StatusBadRequest)
+		return "", false
+	}
+	return game.PlayerID(playerID), true
+}
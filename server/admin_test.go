@@ -0,0 +1,91 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminHandler(t *testing.T) {
+	moderator := NewChatModerator(ChatConfig{})
+	moderator.Register("player1", "192.0.2.1")
+	handler := newAdminHandler(moderator, "secret")
+
+	postWithToken := func(token string, path string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(form.Encode()))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+	post := func(path string, form url.Values) *httptest.ResponseRecorder {
+		return postWithToken("secret", path, form)
+	}
+
+	t.Run("ミュートとその解除", func(t *testing.T) {
+		rec := post("/admin/chat/mute", url.Values{"player_id": {"player1"}, "duration": {"1m"}})
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		_, err := moderator.Check("player1", "a", time.Now())
+		assert.True(t, errors.Is(err, ErrChatMuted))
+
+		rec = post("/admin/chat/unmute", url.Values{"player_id": {"player1"}})
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		_, err = moderator.Check("player1", "a", time.Now())
+		assert.NoError(t, err)
+	})
+
+	t.Run("BANとその解除", func(t *testing.T) {
+		rec := post("/admin/chat/ban", url.Values{"player_id": {"player1"}})
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		_, err := moderator.Check("player1", "a", time.Now())
+		assert.True(t, errors.Is(err, ErrChatBanned))
+
+		rec = post("/admin/chat/unban", url.Values{"player_id": {"player1"}})
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		_, err = moderator.Check("player1", "a", time.Now())
+		assert.NoError(t, err)
+	})
+
+	t.Run("tokenがないリクエストは拒否する", func(t *testing.T) {
+		for _, token := range []string{"", "wrong"} {
+			rec := postWithToken(token, "/admin/chat/ban", url.Values{"player_id": {"player1"}})
+			assert.Equal(t, http.StatusUnauthorized, rec.Code)
+		}
+		_, err := moderator.Check("player1", "a", time.Now())
+		assert.NoError(t, err, "BANされていない")
+
+		// tokenを設定していなければ管理用のAPIは使えない
+		req := httptest.NewRequest(http.MethodPost, "/admin/chat/ban", strings.NewReader("player_id=player1"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Authorization", "Bearer ")
+		rec := httptest.NewRecorder()
+		newAdminHandler(moderator, "").ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	})
+
+	t.Run("接続していないプレイヤーは指定できない", func(t *testing.T) {
+		for _, path := range []string{"/admin/chat/mute", "/admin/chat/unmute", "/admin/chat/ban", "/admin/chat/unban"} {
+			assert.Equal(t, http.StatusNotFound, post(path, url.Values{"player_id": {"unknown"}}).Code, path)
+		}
+	})
+
+	t.Run("不正なリクエスト", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, post("/admin/chat/ban", url.Values{}).Code)
+		assert.Equal(t, http.StatusBadRequest, post("/admin/chat/mute", url.Values{"player_id": {"player1"}, "duration": {"forever"}}).Code)
+
+		req := httptest.NewRequest(http.MethodGet, "/admin/chat/ban?player_id=player1", nil)
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+}
@@ -0,0 +1,169 @@
+package bot
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/shibayu36/terminal-shooter/server/game"
+)
+
+// ボットのプレイヤーIDの接頭辞
+const IDPrefix = "bot-"
+
+// IsBot ボットのプレイヤーIDか
+func IsBot(playerID game.PlayerID) bool {
+	return strings.HasPrefix(string(playerID), IDPrefix)
+}
+
+// Difficulty ボットの強さ
+type Difficulty string
+
+const (
+	DifficultyEasy   Difficulty = "easy"
+	DifficultyNormal Difficulty = "normal"
+	DifficultyHard   Difficulty = "hard"
+)
+
+// difficultySpec 強さごとの行動の性能
+type difficultySpec struct {
+	// 何tickごとに行動を決めるか。小さいほど反応が速い
+	thinkTicks int
+	// 敵を狙える時に撃つ確率
+	shootChance float64
+	// 危険な位置にいる時に避ける確率
+	dodgeChance float64
+	// 近くに敵がいる時にボムを置く確率
+	bombChance float64
+}
+
+func (d Difficulty) spec() difficultySpec {
+	switch d {
+	case DifficultyEasy:
+		return difficultySpec{thinkTicks: 30, shootChance: 0.3, dodgeChance: 0.3, bombChance: 0.05}
+	case DifficultyHard:
+		return difficultySpec{thinkTicks: 6, shootChance: 0.9, dodgeChance: 1.0, bombChance: 0.3}
+	case DifficultyNormal:
+		return difficultySpec{thinkTicks: 15, shootChance: 0.6, dodgeChance: 0.7, bombChance: 0.15}
+	default:
+		return DifficultyNormal.spec()
+	}
+}
+
+// ParseDifficulty 名前からボットの強さを取得する
+func ParseDifficulty(name string) (Difficulty, error) {
+	switch d := Difficulty(name); d {
+	case DifficultyEasy, DifficultyNormal, DifficultyHard:
+		return d, nil
+	default:
+		return "", errors.Newf("unknown bot difficulty: %s", name)
+	}
+}
+
+// Config ボットの設定
+type Config struct {
+	// 常に参加させるボットの数
+	Count int
+	// 人間のプレイヤーとボットを合わせてこの人数に満たない場合、足りない分だけボットを追加する
+	// 人間のプレイヤーが増えたら追加したボットは抜ける。0なら追加しない
+	FillTo int
+	// ボットの強さ。空ならnormal
+	Difficulty Difficulty
+	// ボットの行動を決める乱数のシード
+	Seed int64
+}
+
+// Manager ゲームに参加するボットの数を保ち、tickごとにボットを動かす
+// ボットはGameのメソッドを直接呼んで操作する
+type Manager struct {
+	game   *game.Game
+	config Config
+	spec   difficultySpec
+	rng    *rand.Rand
+
+	// 参加中のボットのID
+	bots []game.PlayerID
+	// 次に追加するボットの番号
+	nextNumber int
+	// Startしてからのtick数
+	tick int
+}
+
+func NewManager(g *game.Game, config Config) *Manager {
+	return &Manager{
+		game:   g,
+		config: config,
+		spec:   config.Difficulty.spec(),
+		//nolint:gosec
+		rng:        rand.New(rand.NewSource(config.Seed)),
+		bots:       nil,
+		nextNumber: 1,
+		tick:       0,
+	}
+}
+
+// Start ゲームのStepのたびにボットを動かすように登録する
+func (m *Manager) Start() {
+	m.game.AddStepHook(m.Tick)
+}
+
+// Tick ボットの数を調整し、行動を決めるタイミングのボットを動かす
+func (m *Manager) Tick() {
+	m.adjustBots()
+
+	m.tick++
+	w := newWorld(m.game)
+	for i, botID := range m.bots {
+		// 全てのボットが同じtickに動かないように、ボットごとにずらす
+		if (m.tick+i)%m.spec.thinkTicks != 0 {
+			continue
+		}
+		m.act(w, botID)
+	}
+}
+
+// Bots 参加中のボットのID
+func (m *Manager) Bots() []game.PlayerID {
+	return append([]game.PlayerID(nil), m.bots...)
+}
+
+// adjustBots 人間のプレイヤー数に合わせてボットを追加・削除する
+func (m *Manager) adjustBots() {
+	humans := 0
+	for playerID := range m.game.GetPlayers() {
+		if !IsBot(playerID) {
+			humans++
+		}
+	}
+	want := max(m.config.Count, m.config.FillTo-humans)
+
+	for len(m.bots) < want {
+		botID := game.PlayerID(fmt.Sprintf("%s%d", IDPrefix, m.nextNumber))
+		m.nextNumber++
+		// 同じIDのプレイヤーがいる場合は他人のプレイヤーを操作しないように次の番号を使う
+		if !m.game.AddPlayer(botID) {
+			continue
+		}
+		// 人間のプレイヤーが同じ名前を使っている場合は表示名なしで参加する
+		_, _ = m.game.SetDisplayName(botID, fmt.Sprintf("Bot%d", m.nextNumber-1))
+		m.bots = append(m.bots, botID)
+	}
+	// 後から追加したボットから抜ける
+	for len(m.bots) > want {
+		last := m.bots[len(m.bots)-1]
+		m.game.RemovePlayer(last)
+		m.bots = m.bots[:len(m.bots)-1]
+	}
+}
+
+// sortedPlayers プレイヤーをIDの順に並べる
+func sortedPlayers(players map[game.PlayerID]*game.Player) []*game.Player {
+	sorted := make([]*game.Player, 0, len(players))
+	for _, player := range players {
+		sorted = append(sorted, player)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].PlayerID < sorted[j].PlayerID })
+	return sorted
+}
@@ -0,0 +1,86 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/shibayu36/terminal-shooter/server/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDifficulty(t *testing.T) {
+	difficulty, err := ParseDifficulty("hard")
+	require.NoError(t, err)
+	assert.Equal(t, DifficultyHard, difficulty)
+
+	_, err = ParseDifficulty("impossible")
+	assert.Error(t, err)
+}
+
+func TestManager_adjustBots(t *testing.T) {
+	t.Run("常に参加させる数だけボットが参加し、表示名が付く", func(t *testing.T) {
+		g := game.NewGame(30, 30)
+		manager := NewManager(g, Config{Count: 2, FillTo: 0, Difficulty: DifficultyNormal, Seed: 1})
+		manager.Start()
+		g.Step()
+
+		assert.Equal(t, []game.PlayerID{"bot-1", "bot-2"}, manager.Bots())
+		assert.Equal(t, "Bot1", g.GetPlayers()["bot-1"].DisplayName())
+		assert.True(t, IsBot("bot-1"))
+		assert.False(t, IsBot("player1"))
+	})
+
+	t.Run("人間のプレイヤーが足りない分だけボットが参加し、人間が増えたら抜ける", func(t *testing.T) {
+		g := game.NewGame(30, 30)
+		manager := NewManager(g, Config{Count: 0, FillTo: 3, Difficulty: DifficultyNormal, Seed: 1})
+		manager.Start()
+
+		g.AddPlayer("player1")
+		g.Step()
+		assert.Equal(t, []game.PlayerID{"bot-1", "bot-2"}, manager.Bots())
+
+		g.AddPlayer("player2")
+		g.Step()
+		assert.Equal(t, []game.PlayerID{"bot-1"}, manager.Bots())
+		assert.NotContains(t, g.GetPlayers(), game.PlayerID("bot-2"))
+
+		g.AddPlayer("player3")
+		g.AddPlayer("player4")
+		g.Step()
+		assert.Empty(t, manager.Bots())
+		assert.Len(t, g.GetPlayers(), 4)
+
+		// 人間が抜けたらまたボットが参加する
+		g.RemovePlayer("player4")
+		g.RemovePlayer("player3")
+		g.RemovePlayer("player2")
+		g.Step()
+		assert.Equal(t, []game.PlayerID{"bot-3", "bot-4"}, manager.Bots())
+	})
+}
+
+func TestManager_Tick(t *testing.T) {
+	// ボット同士で対戦させると、そのうち誰かが倒される
+	g := game.NewGameWithConfig(game.Config{
+		Width:    15,
+		Height:   15,
+		Movement: game.MovementConfig{MoveTicks: 8},
+		Arena:    game.ArenaConfig{PillarInterval: 2, BlockDensity: 0, PowerUpChance: 0},
+		Seed:     1,
+	})
+	manager := NewManager(g, Config{Count: 2, FillTo: 0, Difficulty: DifficultyHard, Seed: 1})
+	manager.Start()
+
+	killed := false
+	for range 60 * game.TicksPerSecond {
+		for _, event := range g.Step() {
+			if _, ok := event.(game.PlayerDied); ok {
+				killed = true
+			}
+		}
+		if killed {
+			break
+		}
+	}
+	assert.True(t, killed, "ボットが敵を倒せる")
+}
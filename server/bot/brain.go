@@ -0,0 +1,330 @@
+package bot
+
+import (
+	"sort"
+
+	"github.com/shibayu36/terminal-shooter/server/game"
+)
+
+const (
+	// 弾の進路を何マス先まで危険とみなすか
+	bulletLookahead = 6
+	// 敵を狙って撃つ最大の距離
+	shootRange = 10
+	// ボムを置く時の敵との最大の距離
+	bombDistance = 2
+)
+
+// 上下左右の向き。探索の順番を固定して、同じ状態からは同じ行動をするようにする
+var directions = []game.Direction{ //nolint:gochecknoglobals
+	game.DirectionUp,
+	game.DirectionDown,
+	game.DirectionLeft,
+	game.DirectionRight,
+}
+
+// world ボットが行動を決めるための、あるtickの盤面の見取り図
+type world struct {
+	width  int
+	height int
+	// 壁やブロックがある位置
+	obstacles map[game.Position]bool
+	// 爆発の火や、爆発しそうなボムの範囲
+	fire map[game.Position]bool
+	// 弾が飛んでくる位置と、その弾を撃ったプレイヤー
+	bullets map[game.Position][]game.PlayerID
+	// 生きているプレイヤー。IDの順に並べる
+	players []*game.Player
+	// ゲームのルール。味方かどうかの判定に使う
+	mode game.GameMode
+}
+
+func newWorld(g *game.Game) *world {
+	w := &world{
+		width:     g.Width,
+		height:    g.Height,
+		obstacles: make(map[game.Position]bool),
+		fire:      make(map[game.Position]bool),
+		bullets:   make(map[game.Position][]game.PlayerID),
+		players:   nil,
+		mode:      g.Mode(),
+	}
+
+	items := make([]game.Item, 0)
+	for _, item := range g.GetItems() {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID() < items[j].ID() })
+
+	// 火の範囲は障害物で止まるので、先に障害物を集める
+	for _, item := range items {
+		if item.Type() == game.ItemTypeWall || item.Type() == game.ItemTypeBlock {
+			w.obstacles[item.Position()] = true
+		}
+	}
+	for _, item := range items {
+		switch item := item.(type) {
+		case *game.BombFire:
+			w.fire[item.Position()] = true
+		case *game.Bomb:
+			w.addBombRange(item)
+		case *game.Bullet:
+			w.addBulletPath(item)
+		}
+	}
+
+	for _, player := range sortedPlayers(g.GetPlayers()) {
+		if player.Status() == game.PlayerStatusAlive {
+			w.players = append(w.players, player)
+		}
+	}
+	return w
+}
+
+// addBombRange ボムが爆発した時に火が広がる範囲を危険な位置にする
+func (w *world) addBombRange(bomb *game.Bomb) {
+	w.fire[bomb.Position()] = true
+	for _, direction := range directions {
+		dx, dy := direction.ToVector()
+		for i := 1; i <= bomb.FireRange(); i++ {
+			position := game.Position{X: bomb.Position().X + dx*i, Y: bomb.Position().Y + dy*i}
+			if !w.inBounds(position) || w.obstacles[position] {
+				break
+			}
+			w.fire[position] = true
+		}
+	}
+}
+
+// addBulletPath 弾がこれから進む位置を危険な位置にする
+func (w *world) addBulletPath(bullet *game.Bullet) {
+	dx, dy := bullet.Direction().ToVector()
+	for i := 0; i <= bulletLookahead; i++ {
+		position := game.Position{X: bullet.Position().X + dx*i, Y: bullet.Position().Y + dy*i}
+		if !w.inBounds(position) || w.obstacles[position] {
+			break
+		}
+		w.bullets[position] = append(w.bullets[position], bullet.OwnerID())
+	}
+}
+
+func (w *world) inBounds(position game.Position) bool {
+	return position.X >= 0 && position.X < w.width && position.Y >= 0 && position.Y < w.height
+}
+
+// passable 移動できる位置か
+func (w *world) passable(position game.Position) bool {
+	return w.inBounds(position) && !w.obstacles[position]
+}
+
+// dangerous playerIDのプレイヤーにとって危険な位置か。自分の撃った弾は自分に向かってこないので除く
+func (w *world) dangerous(position game.Position, playerID game.PlayerID) bool {
+	if w.fire[position] {
+		return true
+	}
+	for _, ownerID := range w.bullets[position] {
+		if ownerID != playerID {
+			return true
+		}
+	}
+	return false
+}
+
+// isEnemy otherがplayerの敵か
+func (w *world) isEnemy(player, other *game.Player) bool {
+	if player.PlayerID == other.PlayerID {
+		return false
+	}
+	return player.Team() == game.TeamNone || player.Team() != other.Team() || w.mode.FriendlyFire()
+}
+
+// playerAt 指定位置にいる生きているプレイヤー
+func (w *world) playerAt(position game.Position) *game.Player {
+	for _, player := range w.players {
+		if player.Position() == position {
+			return player
+		}
+	}
+	return nil
+}
+
+func (w *world) player(playerID game.PlayerID) *game.Player {
+	for _, player := range w.players {
+		if player.PlayerID == playerID {
+			return player
+		}
+	}
+	return nil
+}
+
+// alignedEnemy playerと同じ行か列にいて、間に障害物がない一番近い敵の方向を返す
+func (w *world) alignedEnemy(player *game.Player) (game.Direction, bool) {
+	for i := 1; i <= shootRange; i++ {
+		for _, direction := range directions {
+			dx, dy := direction.ToVector()
+			position := game.Position{X: player.Position().X + dx*i, Y: player.Position().Y + dy*i}
+			if !w.clearLine(player.Position(), direction, i) {
+				continue
+			}
+			if other := w.playerAt(position); other != nil && w.isEnemy(player, other) {
+				return direction, true
+			}
+		}
+	}
+	return "", false
+}
+
+// clearLine fromからdirectionにdistanceマス先までの間に障害物がないか
+func (w *world) clearLine(from game.Position, direction game.Direction, distance int) bool {
+	dx, dy := direction.ToVector()
+	for i := 1; i <= distance; i++ {
+		if !w.passable(game.Position{X: from.X + dx*i, Y: from.Y + dy*i}) {
+			return false
+		}
+	}
+	return true
+}
+
+// nearestEnemyDistance playerから一番近い敵までのマンハッタン距離。敵がいなければfalseを返す
+func (w *world) nearestEnemyDistance(player *game.Player) (int, bool) {
+	nearest, found := 0, false
+	for _, other := range w.players {
+		if !w.isEnemy(player, other) {
+			continue
+		}
+		distance := abs(other.Position().X-player.Position().X) + abs(other.Position().Y-player.Position().Y)
+		if !found || distance < nearest {
+			nearest, found = distance, true
+		}
+	}
+	return nearest, found
+}
+
+// firstStep fromから幅優先探索で、goalを満たす一番近い位置へ向かう最初の1歩を返す
+// avoidDangerがtrueなら危険な位置は通らない
+func (w *world) firstStep(
+	from game.Position,
+	playerID game.PlayerID,
+	avoidDanger bool,
+	goal func(game.Position) bool,
+) (game.Position, bool) {
+	type node struct {
+		position game.Position
+		// fromから最初に踏み出した位置
+		first game.Position
+	}
+
+	visited := map[game.Position]bool{from: true}
+	queue := []node{}
+	for _, direction := range directions {
+		dx, dy := direction.ToVector()
+		next := game.Position{X: from.X + dx, Y: from.Y + dy}
+		queue = append(queue, node{position: next, first: next})
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if visited[current.position] || !w.passable(current.position) {
+			continue
+		}
+		if avoidDanger && w.dangerous(current.position, playerID) {
+			continue
+		}
+		visited[current.position] = true
+
+		if goal(current.position) {
+			return current.first, true
+		}
+		for _, direction := range directions {
+			dx, dy := direction.ToVector()
+			next := game.Position{X: current.position.X + dx, Y: current.position.Y + dy}
+			queue = append(queue, node{position: next, first: current.first})
+		}
+	}
+	return game.Position{X: 0, Y: 0}, false
+}
+
+// act ボットの次の行動を決めて実行する
+// 危険なら避け、敵を狙えるなら撃ち、敵が近ければボムを置き、それ以外は一番近い敵に近づく
+// 敵にたどり着けなければうろつく
+func (m *Manager) act(w *world, botID game.PlayerID) {
+	player := w.player(botID)
+	if player == nil {
+		return
+	}
+	position := player.Position()
+
+	if w.dangerous(position, botID) && m.rng.Float64() < m.spec.dodgeChance {
+		safe := func(p game.Position) bool { return !w.dangerous(p, botID) }
+		if next, ok := w.firstStep(position, botID, false, safe); ok {
+			m.moveTo(player, next)
+			return
+		}
+	}
+
+	if direction, ok := w.alignedEnemy(player); ok && m.rng.Float64() < m.spec.shootChance {
+		if player.Direction() != direction {
+			m.game.RequestMove(botID, position, direction)
+		}
+		m.game.ShootBullet(botID)
+		return
+	}
+
+	if distance, ok := w.nearestEnemyDistance(player); ok && distance <= bombDistance && m.rng.Float64() < m.spec.bombChance {
+		// 置いたボムの範囲から逃げられる場合だけ置く
+		escape := func(p game.Position) bool { return p.X != position.X && p.Y != position.Y }
+		if _, ok := w.firstStep(position, botID, true, escape); ok {
+			m.game.PlaceBomb(botID)
+			return
+		}
+	}
+
+	enemy := func(p game.Position) bool {
+		other := w.playerAt(p)
+		return other != nil && w.isEnemy(player, other)
+	}
+	if next, ok := w.firstStep(position, botID, true, enemy); ok {
+		if w.playerAt(next) == nil {
+			m.moveTo(player, next)
+		}
+		return
+	}
+
+	// 敵にたどり着けない場合は、安全な方向にうろつく
+	var candidates []game.Position
+	for _, direction := range directions {
+		dx, dy := direction.ToVector()
+		next := game.Position{X: position.X + dx, Y: position.Y + dy}
+		if w.passable(next) && !w.dangerous(next, botID) {
+			candidates = append(candidates, next)
+		}
+	}
+	if len(candidates) > 0 {
+		m.moveTo(player, candidates[m.rng.Intn(len(candidates))])
+	}
+}
+
+// moveTo 隣の位置に、その方向を向いて移動する
+func (m *Manager) moveTo(player *game.Player, next game.Position) {
+	position := player.Position()
+	direction := player.Direction()
+	switch {
+	case next.X < position.X:
+		direction = game.DirectionLeft
+	case next.X > position.X:
+		direction = game.DirectionRight
+	case next.Y < position.Y:
+		direction = game.DirectionUp
+	case next.Y > position.Y:
+		direction = game.DirectionDown
+	}
+	m.game.RequestMove(player.PlayerID, next, direction)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
@@ -0,0 +1,86 @@
+package bot
+
+import (
+	"testing"
+
+	"github.com/shibayu36/terminal-shooter/server/game"
+	"github.com/stretchr/testify/assert"
+)
+
+// newTestManager 必ず行動する強さのボットを1体だけ参加させる
+func newTestManager(g *game.Game) *Manager {
+	manager := NewManager(g, Config{Count: 1, FillTo: 0, Difficulty: DifficultyHard, Seed: 1})
+	manager.spec = difficultySpec{thinkTicks: 1, shootChance: 1, dodgeChance: 1, bombChance: 0}
+	manager.adjustBots()
+	return manager
+}
+
+// countBullets ownerIDのプレイヤーが撃った弾の数
+func countBullets(g *game.Game, ownerID game.PlayerID) int {
+	bullets := 0
+	for _, item := range g.GetItems() {
+		if bullet, ok := item.(*game.Bullet); ok && bullet.OwnerID() == ownerID {
+			bullets++
+		}
+	}
+	return bullets
+}
+
+func TestWorld_dangerous(t *testing.T) {
+	g := game.NewGame(30, 30)
+	g.AddPlayer("player1")
+	g.MovePlayer("player1", game.Position{X: 5, Y: 5}, game.DirectionRight)
+	g.ShootBullet("player1")
+
+	w := newWorld(g)
+	// 弾の進路は危険だが、撃ったプレイヤー自身にとっては危険ではない
+	assert.True(t, w.dangerous(game.Position{X: 8, Y: 5}, "bot-1"))
+	assert.False(t, w.dangerous(game.Position{X: 8, Y: 5}, "player1"))
+	assert.False(t, w.dangerous(game.Position{X: 5, Y: 6}, "bot-1"))
+}
+
+func TestManager_act(t *testing.T) {
+	t.Run("弾の進路にいたら避ける", func(t *testing.T) {
+		g := game.NewGame(30, 30)
+		manager := newTestManager(g)
+		g.MovePlayer("bot-1", game.Position{X: 10, Y: 5}, game.DirectionUp)
+		g.AddPlayer("player1")
+		g.MovePlayer("player1", game.Position{X: 5, Y: 5}, game.DirectionRight)
+		g.ShootBullet("player1")
+
+		// 撃った相手とは同じ行にいるが、撃ち返すより避けるのを優先する
+		manager.act(newWorld(g), "bot-1")
+		assert.NotEqual(t, 5, g.GetPlayers()["bot-1"].Position().Y)
+	})
+
+	t.Run("同じ列にいる敵の方を向いて撃つ", func(t *testing.T) {
+		g := game.NewGame(30, 30)
+		manager := newTestManager(g)
+		g.MovePlayer("bot-1", game.Position{X: 5, Y: 10}, game.DirectionRight)
+		g.AddPlayer("player1")
+		g.MovePlayer("player1", game.Position{X: 5, Y: 4}, game.DirectionRight)
+
+		manager.act(newWorld(g), "bot-1")
+		assert.Equal(t, game.DirectionUp, g.GetPlayers()["bot-1"].Direction())
+
+		assert.Equal(t, 1, countBullets(g, "bot-1"))
+	})
+
+	t.Run("壁を回り込んで敵に近づく", func(t *testing.T) {
+		// 奇数の座標に柱が並ぶ
+		g := game.NewGameWithConfig(game.Config{
+			Width:  30,
+			Height: 30,
+			Arena:  game.ArenaConfig{PillarInterval: 2, BlockDensity: 0, PowerUpChance: 0},
+		})
+		manager := newTestManager(g)
+		g.MovePlayer("bot-1", game.Position{X: 3, Y: 2}, game.DirectionRight)
+		// 同じ列にいるが、間に柱があるので撃てない
+		g.AddPlayer("player1")
+		g.MovePlayer("player1", game.Position{X: 3, Y: 6}, game.DirectionLeft)
+
+		manager.act(newWorld(g), "bot-1")
+		assert.Contains(t, []game.Position{{X: 2, Y: 2}, {X: 4, Y: 2}}, g.GetPlayers()["bot-1"].Position())
+		assert.Equal(t, 0, countBullets(g, "bot-1"), "弾を撃っていない")
+	})
+}
@@ -19,10 +19,16 @@ func NewBroker() *Broker {
 	}
 }
 
-func (b *Broker) AddClient(client Client) {
+// AddClient クライアントを登録する
+// 同じIDのクライアントが既に接続している場合は上書きせずにfalseを返す
+func (b *Broker) AddClient(client Client) bool {
 	b.clientsMux.Lock()
 	defer b.clientsMux.Unlock()
+	if _, ok := b.clients[client.ID()]; ok {
+		return false
+	}
 	b.clients[client.ID()] = client
+	return true
 }
 
 func (b *Broker) RemoveClient(client Client) {
@@ -33,6 +39,11 @@ func (b *Broker) RemoveClient(client Client) {
 
 // Broadcast クライアント全員にメッセージを配信する
 func (b *Broker) Broadcast(topic string, payload []byte) error {
+	return b.Multicast(topic, payload, func(string) bool { return true })
+}
+
+// Multicast acceptがtrueを返すIDのクライアントにだけメッセージを配信する
+func (b *Broker) Multicast(topic string, payload []byte, accept func(clientID string) bool) error {
 	b.clientsMux.RLock()
 	defer b.clientsMux.RUnlock()
 
@@ -44,7 +55,10 @@ func (b *Broker) Broadcast(topic string, payload []byte) error {
 
 	errs := make([]error, 0, len(b.clients))
 
-	for _, client := range b.clients {
+	for clientID, client := range b.clients {
+		if !accept(clientID) {
+			continue
+		}
 		err := client.Publish(publishPacket)
 		if err != nil {
 			errs = append(errs, err)
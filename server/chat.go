@@ -0,0 +1,249 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/errors"
+	"github.com/shibayu36/terminal-shooter/server/game"
+)
+
+var (
+	ErrChatEmpty       = errors.New("chat message is empty")
+	ErrChatTooLong     = errors.New("chat message is too long")
+	ErrChatRateLimited = errors.New("too many chat messages")
+	ErrChatMuted       = errors.New("player is muted")
+	ErrChatBanned      = errors.New("player is banned from chat")
+	// ErrChatPlayerNotConnected ミュートやBANの対象のプレイヤーが接続していない
+	ErrChatPlayerNotConnected = errors.New("player is not connected")
+)
+
+// ChatConfig チャットの制限
+type ChatConfig struct {
+	// 1メッセージの最大文字数
+	MaxLength int
+	// RateWindowの間に送れるメッセージ数。0なら制限なし
+	RateLimit  int
+	RateWindow time.Duration
+	// 送信数の制限を超えた回数がこれに達すると自動でミュートする。0なら自動でミュートしない
+	AutoMuteViolations int
+	AutoMuteDuration   time.Duration
+}
+
+// DefaultChatConfig サーバーで使うチャットの制限
+//
+//nolint:gochecknoglobals
+var DefaultChatConfig = ChatConfig{
+	MaxLength:          200,
+	RateLimit:          5,
+	RateWindow:         10 * time.Second,
+	AutoMuteViolations: 3,
+	AutoMuteDuration:   time.Minute,
+}
+
+// ChatModerator チャットの文字数や送信頻度を制限し、ミュートやBANされたプレイヤーの発言を止める
+// クライアントIDは接続ごとに変えられるので、送信履歴やミュートとBANは接続元のホストごとにメモリ上で管理する
+type ChatModerator struct {
+	config ChatConfig
+
+	// 接続中のプレイヤーごとの接続元のホスト
+	hosts map[game.PlayerID]string
+	// ホストごとの、RateWindow内に送信した時刻
+	sentAt map[string][]time.Time
+	// ホストごとの、送信数の制限を超えた回数
+	violations map[string]int
+	// ホストごとの、ミュートが解除される時刻
+	mutedUntil map[string]time.Time
+	banned     map[string]bool
+
+	mu sync.Mutex `exhaustruct:"optional"`
+}
+
+func NewChatModerator(config ChatConfig) *ChatModerator {
+	return &ChatModerator{
+		config:     config,
+		hosts:      make(map[game.PlayerID]string),
+		sentAt:     make(map[string][]time.Time),
+		violations: make(map[string]int),
+		mutedUntil: make(map[string]time.Time),
+		banned:     make(map[string]bool),
+	}
+}
+
+// Register 接続したプレイヤーの接続元のホストを覚える
+func (m *ChatModerator) Register(playerID game.PlayerID, host string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if host != "" {
+		m.hosts[playerID] = host
+	}
+}
+
+// keyWithoutLock プレイヤーの制限を管理するキー。接続元のホストが分からなければプレイヤーIDを使う
+func (m *ChatModerator) keyWithoutLock(playerID game.PlayerID) string {
+	if host, ok := m.hosts[playerID]; ok {
+		return "host:" + host
+	}
+	return "player:" + string(playerID)
+}
+
+// Check プレイヤーがnowにtextを送信できるか検証し、配信する本文を返す
+// 改行などの制御文字は空白に置き換え、前後の空白を取り除く
+func (m *ChatModerator) Check(playerID game.PlayerID, text string, now time.Time) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := m.keyWithoutLock(playerID)
+	if m.banned[key] {
+		return "", ErrChatBanned
+	}
+	if until, ok := m.mutedUntil[key]; ok {
+		if now.Before(until) {
+			return "", errors.Wrapf(ErrChatMuted, "until %s", until.Format(time.RFC3339))
+		}
+		delete(m.mutedUntil, key)
+	}
+
+	text = strings.TrimSpace(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, text))
+	if text == "" {
+		return "", ErrChatEmpty
+	}
+	if m.config.MaxLength > 0 && utf8.RuneCountInString(text) > m.config.MaxLength {
+		return "", errors.Wrapf(ErrChatTooLong, "max length is %d", m.config.MaxLength)
+	}
+
+	if !m.allowWithoutLock(key, now) {
+		return "", ErrChatRateLimited
+	}
+
+	return text, nil
+}
+
+// allowWithoutLock 送信数の制限内なら送信を記録してtrueを返す
+// 制限を超えた回数が一定に達したら自動でミュートする
+func (m *ChatModerator) allowWithoutLock(key string, now time.Time) bool {
+	if m.config.RateLimit <= 0 {
+		return true
+	}
+
+	sentAt := m.sentAt[key][:0]
+	for _, t := range m.sentAt[key] {
+		if now.Sub(t) < m.config.RateWindow {
+			sentAt = append(sentAt, t)
+		}
+	}
+
+	if len(sentAt) >= m.config.RateLimit {
+		m.sentAt[key] = sentAt
+		m.violations[key]++
+		if m.config.AutoMuteViolations > 0 && m.violations[key] >= m.config.AutoMuteViolations {
+			m.mutedUntil[key] = now.Add(m.config.AutoMuteDuration)
+			m.violations[key] = 0
+		}
+		return false
+	}
+
+	m.sentAt[key] = append(sentAt, now)
+	return true
+}
+
+// Mute 接続中のプレイヤーをuntilまでミュートする。同じホストから接続し直しても解除されない
+func (m *ChatModerator) Mute(playerID game.PlayerID, until time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.connectedKeyWithoutLock(playerID)
+	if err != nil {
+		return err
+	}
+	m.mutedUntil[key] = until
+	return nil
+}
+
+// Unmute 接続中のプレイヤーのミュートを解除する
+func (m *ChatModerator) Unmute(playerID game.PlayerID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.connectedKeyWithoutLock(playerID)
+	if err != nil {
+		return err
+	}
+	delete(m.mutedUntil, key)
+	delete(m.violations, key)
+	return nil
+}
+
+// Ban 接続中のプレイヤーをチャットからBANする。Unbanするまで同じホストからは発言できない
+func (m *ChatModerator) Ban(playerID game.PlayerID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.connectedKeyWithoutLock(playerID)
+	if err != nil {
+		return err
+	}
+	m.banned[key] = true
+	return nil
+}
+
+// Unban 接続中のプレイヤーのBANを解除する
+func (m *ChatModerator) Unban(playerID game.PlayerID) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	key, err := m.connectedKeyWithoutLock(playerID)
+	if err != nil {
+		return err
+	}
+	delete(m.banned, key)
+	return nil
+}
+
+// connectedKeyWithoutLock 接続中のプレイヤーの制限を管理するキー
+// 接続元のホストが分からないプレイヤーに制限をかけても、接続し直すと別のキーになって効かないのでエラーにする
+func (m *ChatModerator) connectedKeyWithoutLock(playerID game.PlayerID) (string, error) {
+	if _, ok := m.hosts[playerID]; !ok {
+		return "", errors.Wrapf(ErrChatPlayerNotConnected, "player: %s", playerID)
+	}
+	return m.keyWithoutLock(playerID), nil
+}
+
+// Forget 切断したプレイヤーの接続元のホストを忘れる
+// 送信履歴やミュートとBANはホストごとに残すので、接続し直しても制限は続く
+// 同じホストから接続しているプレイヤーが他にいなければ、nowの時点で期限が切れた送信履歴とミュートは捨てる
+func (m *ChatModerator) Forget(playerID game.PlayerID, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := m.keyWithoutLock(playerID)
+	host, ok := m.hosts[playerID]
+	delete(m.hosts, playerID)
+	if ok {
+		for _, other := range m.hosts {
+			if other == host {
+				return
+			}
+		}
+	}
+
+	var sentAt []time.Time
+	for _, t := range m.sentAt[key] {
+		if now.Sub(t) < m.config.RateWindow {
+			sentAt = append(sentAt, t)
+		}
+	}
+	if len(sentAt) == 0 {
+		delete(m.sentAt, key)
+		delete(m.violations, key)
+	} else {
+		m.sentAt[key] = sentAt
+	}
+	if until, ok := m.mutedUntil[key]; ok && !now.Before(until) {
+		delete(m.mutedUntil, key)
+	}
+}
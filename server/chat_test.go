@@ -0,0 +1,163 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/shibayu36/terminal-shooter/server/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatModerator_Check(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("制御文字は空白にし、前後の空白を取り除く", func(t *testing.T) {
+		moderator := NewChatModerator(ChatConfig{MaxLength: 10})
+		text, err := moderator.Check("player1", "  hi\nall ", now)
+		require.NoError(t, err)
+		assert.Equal(t, "hi all", text)
+	})
+
+	t.Run("空や長すぎるメッセージは送れない", func(t *testing.T) {
+		moderator := NewChatModerator(ChatConfig{MaxLength: 5})
+		_, err := moderator.Check("player1", " \n ", now)
+		assert.True(t, errors.Is(err, ErrChatEmpty))
+
+		_, err = moderator.Check("player1", strings.Repeat("あ", 6), now)
+		assert.True(t, errors.Is(err, ErrChatTooLong))
+
+		_, err = moderator.Check("player1", strings.Repeat("あ", 5), now)
+		assert.NoError(t, err)
+	})
+
+	t.Run("一定時間内に送れる数を超えると送れず、時間が経てば送れる", func(t *testing.T) {
+		moderator := NewChatModerator(ChatConfig{RateLimit: 2, RateWindow: 10 * time.Second})
+		_, err := moderator.Check("player1", "a", now)
+		require.NoError(t, err)
+		_, err = moderator.Check("player1", "b", now.Add(time.Second))
+		require.NoError(t, err)
+
+		_, err = moderator.Check("player1", "c", now.Add(2*time.Second))
+		assert.True(t, errors.Is(err, ErrChatRateLimited))
+
+		// 他のプレイヤーは制限されない
+		_, err = moderator.Check("player2", "c", now.Add(2*time.Second))
+		assert.NoError(t, err)
+
+		_, err = moderator.Check("player1", "d", now.Add(10*time.Second))
+		assert.NoError(t, err)
+	})
+
+	t.Run("制限を何度も超えると自動でミュートされる", func(t *testing.T) {
+		moderator := NewChatModerator(ChatConfig{
+			RateLimit:          1,
+			RateWindow:         time.Second,
+			AutoMuteViolations: 2,
+			AutoMuteDuration:   time.Minute,
+		})
+		_, err := moderator.Check("player1", "a", now)
+		require.NoError(t, err)
+		for range 2 {
+			_, err = moderator.Check("player1", "a", now)
+			assert.True(t, errors.Is(err, ErrChatRateLimited))
+		}
+
+		_, err = moderator.Check("player1", "a", now.Add(30*time.Second))
+		assert.True(t, errors.Is(err, ErrChatMuted))
+
+		_, err = moderator.Check("player1", "a", now.Add(time.Minute))
+		assert.NoError(t, err)
+	})
+
+	t.Run("ミュートとBANは解除するまで送れない", func(t *testing.T) {
+		moderator := NewChatModerator(ChatConfig{})
+		moderator.Register("player1", "192.0.2.1")
+		moderator.Register("player2", "192.0.2.2")
+		require.NoError(t, moderator.Mute("player1", now.Add(time.Hour)))
+		require.NoError(t, moderator.Ban("player2"))
+
+		_, err := moderator.Check("player1", "a", now)
+		assert.True(t, errors.Is(err, ErrChatMuted))
+		_, err = moderator.Check("player2", "a", now)
+		assert.True(t, errors.Is(err, ErrChatBanned))
+
+		// 切断して接続し直してもミュートとBANは残る
+		moderator.Forget("player1", now)
+		moderator.Forget("player2", now)
+		moderator.Register("player1", "192.0.2.1")
+		moderator.Register("player2", "192.0.2.2")
+		_, err = moderator.Check("player1", "a", now)
+		assert.True(t, errors.Is(err, ErrChatMuted))
+		_, err = moderator.Check("player2", "a", now)
+		assert.True(t, errors.Is(err, ErrChatBanned))
+
+		require.NoError(t, moderator.Unmute("player1"))
+		require.NoError(t, moderator.Unban(game.PlayerID("player2")))
+		_, err = moderator.Check("player1", "a", now)
+		assert.NoError(t, err)
+		_, err = moderator.Check("player2", "a", now)
+		assert.NoError(t, err)
+	})
+
+	t.Run("接続していないプレイヤーはミュートやBANできない", func(t *testing.T) {
+		moderator := NewChatModerator(ChatConfig{})
+		assert.True(t, errors.Is(moderator.Mute("player1", now.Add(time.Hour)), ErrChatPlayerNotConnected))
+		assert.True(t, errors.Is(moderator.Ban("player1"), ErrChatPlayerNotConnected))
+		assert.True(t, errors.Is(moderator.Unmute("player1"), ErrChatPlayerNotConnected))
+		assert.True(t, errors.Is(moderator.Unban("player1"), ErrChatPlayerNotConnected))
+		assert.Empty(t, moderator.mutedUntil)
+		assert.Empty(t, moderator.banned)
+	})
+
+	t.Run("別のクライアントIDで接続し直しても同じホストなら制限が続く", func(t *testing.T) {
+		moderator := NewChatModerator(ChatConfig{RateLimit: 1, RateWindow: 10 * time.Second})
+		moderator.Register("player1", "192.0.2.1")
+		_, err := moderator.Check("player1", "a", now)
+		require.NoError(t, err)
+		require.NoError(t, moderator.Mute("player1", now.Add(time.Hour)))
+		moderator.Forget("player1", now)
+
+		moderator.Register("player2", "192.0.2.1")
+		_, err = moderator.Check("player2", "a", now.Add(time.Second))
+		assert.True(t, errors.Is(err, ErrChatMuted))
+		require.NoError(t, moderator.Unmute("player2"))
+		_, err = moderator.Check("player2", "a", now.Add(time.Second))
+		assert.True(t, errors.Is(err, ErrChatRateLimited), "送信履歴も残る")
+
+		// 他のホストからは送れる
+		moderator.Register("player3", "192.0.2.2")
+		_, err = moderator.Check("player3", "a", now.Add(time.Second))
+		assert.NoError(t, err)
+	})
+
+	t.Run("切断した時に、同じホストのプレイヤーがいなければ期限が切れた記録を捨てる", func(t *testing.T) {
+		moderator := NewChatModerator(ChatConfig{RateLimit: 1, RateWindow: 10 * time.Second})
+		moderator.Register("player1", "192.0.2.1")
+		moderator.Register("player2", "192.0.2.1")
+		_, err := moderator.Check("player1", "a", now)
+		require.NoError(t, err)
+		_, err = moderator.Check("player1", "b", now)
+		require.True(t, errors.Is(err, ErrChatRateLimited))
+		require.NoError(t, moderator.Mute("player1", now.Add(time.Minute)))
+
+		// 同じホストのプレイヤーが残っていれば何も捨てない
+		moderator.Forget("player1", now.Add(time.Hour))
+		assert.Contains(t, moderator.sentAt, "host:192.0.2.1")
+		assert.Contains(t, moderator.violations, "host:192.0.2.1")
+		assert.Contains(t, moderator.mutedUntil, "host:192.0.2.1")
+
+		// 期限内の記録は残す
+		moderator.Forget("player2", now.Add(time.Second))
+		assert.Contains(t, moderator.sentAt, "host:192.0.2.1")
+		assert.Contains(t, moderator.mutedUntil, "host:192.0.2.1")
+
+		moderator.Register("player3", "192.0.2.1")
+		moderator.Forget("player3", now.Add(time.Hour))
+		assert.Empty(t, moderator.sentAt)
+		assert.Empty(t, moderator.violations)
+		assert.Empty(t, moderator.mutedUntil)
+	})
+}
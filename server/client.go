@@ -12,6 +12,8 @@ import (
 // Client represents a connected MQTT client
 type Client interface {
 	ID() string
+	// RemoteHost 接続元のホスト。分からなければ空文字列
+	RemoteHost() string
 	Publish(publishPacket *packets.PublishPacket) error
 }
 
@@ -19,6 +21,8 @@ type client struct {
 	id      string `exhaustruct:"optional"` // idは後から設定される
 	conn    net.Conn
 	sendMux sync.Mutex `exhaustruct:"optional"`
+	// 接続を受け付けたか。拒否したクライアントは切断時にOnDisconnectedを呼ばない
+	connected bool `exhaustruct:"optional"`
 }
 
 var _ Client = (*client)(nil)
@@ -27,6 +31,19 @@ func (c *client) ID() string {
 	return c.id
 }
 
+// RemoteHost 接続元のホスト。ポートは接続ごとに変わるので含めない
+func (c *client) RemoteHost() string {
+	addr := c.conn.RemoteAddr()
+	if addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(addr.String())
+	if err != nil {
+		return addr.String()
+	}
+	return host
+}
+
 // Publish クライアントに対してPublishパケットを送信する
 func (c *client) Publish(publishPacket *packets.PublishPacket) error {
 	c.sendMux.Lock()
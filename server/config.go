@@ -0,0 +1,411 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/shibayu36/terminal-shooter/server/bot"
+	"github.com/shibayu36/terminal-shooter/server/game"
+)
+
+// 環境変数の接頭辞。フラグの名前を大文字にして-を_にしたものを続ける
+// 例: -mqtt-portはTERMINAL_SHOOTER_MQTT_PORT
+const envPrefix = "TERMINAL_SHOOTER_"
+
+// 盤面の大きさの範囲
+const (
+	minBoardSize = 5
+	maxBoardSize = 1000
+)
+
+// 1秒あたりのtick数の上限
+const maxTicksPerSecond = 1000
+
+// serverConfig サーバーの設定。設定ファイルのJSONと同じ形をしている
+// ゲーム内の時間は全てtick数で指定するので、ticks_per_secondを変えると実時間での長さも変わる
+type serverConfig struct {
+	MQTTPort    string `json:"mqtt_port"`
+	MetricsPort string `json:"metrics_port"`
+	// 管理用のAPIの認証に使うtoken。空なら管理用のAPIを使えない
+	AdminToken string `json:"admin_token"`
+	// 1秒あたりのtick数
+	TicksPerSecond int `json:"ticks_per_second"`
+	// ゲーム内の乱数とボットのシード。0なら起動した時刻を使う
+	Seed int64 `json:"seed"`
+	// プレイヤーに状態を配信する範囲の半径。0なら盤面全体を配信する
+	InterestRadius int `json:"interest_radius"`
+
+	Board    boardFileConfig    `json:"board"`
+	Match    matchFileConfig    `json:"match"`
+	Weapon   weaponFileConfig   `json:"weapon"`
+	Movement movementFileConfig `json:"movement"`
+	Arena    arenaFileConfig    `json:"arena"`
+	Loop     loopFileConfig     `json:"loop"`
+	Bots     botsFileConfig     `json:"bots"`
+	Replay   replayFileConfig   `json:"replay"`
+}
+
+type boardFileConfig struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// matchFileConfig マッチのルール
+// サーバーはルームを持たず1つのゲームだけを動かすので、モードはサーバー全体で1つ。ルームごとに変えたい場合はサーバーを分けて起動する
+type matchFileConfig struct {
+	Mode           string `json:"mode"`
+	FriendlyFire   bool   `json:"friendly_fire"`
+	MinPlayers     int    `json:"min_players"`
+	CountdownTicks int    `json:"countdown_ticks"`
+	TimeLimitTicks int    `json:"time_limit_ticks"`
+	ScoreLimit     int    `json:"score_limit"`
+	ResultTicks    int    `json:"result_ticks"`
+}
+
+type weaponFileConfig struct {
+	BulletCooldownTicks int `json:"bullet_cooldown_ticks"`
+	MaxAmmo             int `json:"max_ammo"`
+	ReloadTicks         int `json:"reload_ticks"`
+	MaxBombs            int `json:"max_bombs"`
+	BombExplosionTicks  int `json:"bomb_explosion_ticks"`
+	BombFireRange       int `json:"bomb_fire_range"`
+	// 武器の名前から弾が1マス進むのにかかるtick数への対応
+	BulletMoveTicks map[string]int `json:"bullet_move_ticks"`
+}
+
+type movementFileConfig struct {
+	MoveTicks int `json:"move_ticks"`
+}
+
+type arenaFileConfig struct {
+	PillarInterval int     `json:"pillar_interval"`
+	BlockDensity   float64 `json:"block_density"`
+	PowerUpChance  float64 `json:"power_up_chance"`
+}
+
+type loopFileConfig struct {
+	MaxCatchUpTicks int `json:"max_catch_up_ticks"`
+}
+
+// botsFileConfig サーバー内で動かすボットの設定
+// ルームは無いので、人数と強さはサーバーで動かす1つのゲームに対して指定する
+type botsFileConfig struct {
+	Count      int    `json:"count"`
+	FillTo     int    `json:"fill_to"`
+	Difficulty string `json:"difficulty"`
+}
+
+type replayFileConfig struct {
+	Dir          string `json:"dir"`
+	MaxFiles     int    `json:"max_files"`
+	MaxFileBytes int64  `json:"max_file_bytes"`
+}
+
+// defaultServerConfig 設定ファイルもフラグも指定しなかった時の設定
+func defaultServerConfig() serverConfig {
+	return serverConfig{
+		MQTTPort:       "1883",
+		MetricsPort:    "2112",
+		AdminToken:     "",
+		TicksPerSecond: game.TicksPerSecond,
+		Seed:           0,
+		InterestRadius: 0,
+		Board:          boardFileConfig{Width: 30, Height: 30},
+		Match: matchFileConfig{
+			Mode:           string(game.GameModeDeathmatch),
+			FriendlyFire:   false,
+			MinPlayers:     2,
+			CountdownTicks: 3 * game.TicksPerSecond,
+			TimeLimitTicks: 180 * game.TicksPerSecond,
+			ScoreLimit:     10,
+			ResultTicks:    10 * game.TicksPerSecond,
+		},
+		Weapon: weaponFileConfig{
+			BulletCooldownTicks: 10,
+			MaxAmmo:             10,
+			ReloadTicks:         2 * game.TicksPerSecond,
+			MaxBombs:            1,
+			BombExplosionTicks:  0, // 1秒あたりのtick数に関わらず3秒で爆発する
+			BombFireRange:       game.BombFireRange,
+			BulletMoveTicks:     map[string]int{},
+		},
+		Movement: movementFileConfig{MoveTicks: 8},
+		Arena:    arenaFileConfig{PillarInterval: 2, BlockDensity: 0.3, PowerUpChance: 0.3},
+		Loop:     loopFileConfig{MaxCatchUpTicks: 5},
+		// ボットとリプレイの記録は、運用する人が指定した時だけ有効にする
+		Bots:   botsFileConfig{Count: 0, FillTo: 0, Difficulty: string(bot.DifficultyNormal)},
+		Replay: replayFileConfig{Dir: "", MaxFiles: 100, MaxFileBytes: 64 << 20},
+	}
+}
+
+// parseServerConfig 初期値、設定ファイル、環境変数、フラグの順に上書きして設定を作り、検証する
+// 設定ファイルは-configかTERMINAL_SHOOTER_CONFIGで指定する
+func parseServerConfig(args []string, getenv func(string) string, output io.Writer) (*serverConfig, error) {
+	// 設定ファイルの場所を知るために、一度フラグを読み捨てる
+	// フラグの誤りは後でもう一度読む時に報告する
+	configPath := getenv(envName("config"))
+	scratch := defaultServerConfig()
+	discard := newServerFlagSet(&scratch, &configPath)
+	discard.SetOutput(io.Discard)
+	_ = discard.Parse(args)
+
+	config := defaultServerConfig()
+	if configPath != "" {
+		if err := loadServerConfigFile(configPath, &config); err != nil {
+			return nil, err
+		}
+	}
+
+	// フラグの初期値は設定ファイルを反映した値になるので、環境変数とフラグで指定したものだけが上書きされる
+	flagSet := newServerFlagSet(&config, &configPath)
+	flagSet.SetOutput(output)
+	var envErrs []error
+	flagSet.VisitAll(func(f *flag.Flag) {
+		if value := getenv(envName(f.Name)); value != "" && f.Name != "config" {
+			if err := flagSet.Set(f.Name, value); err != nil {
+				envErrs = append(envErrs, errors.Wrapf(err, "invalid %s", envName(f.Name)))
+			}
+		}
+	})
+	if err := errors.Join(envErrs...); err != nil {
+		return nil, err
+	}
+	if err := flagSet.Parse(args); err != nil {
+		return nil, errors.Wrap(err, "failed to parse flags")
+	}
+
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// loadServerConfigFile 設定ファイルを読み込み、書かれている項目だけconfigを上書きする
+func loadServerConfigFile(path string, config *serverConfig) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open config file: %s", path)
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	// 項目名の書き間違いに気づけるように、知らない項目はエラーにする
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return errors.Wrapf(err, "failed to parse config file: %s", path)
+	}
+	return nil
+}
+
+// envName フラグに対応する環境変数の名前
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// newServerFlagSet configの各項目を書き換えるフラグを作る。フラグの初期値はconfigの現在の値になる
+func newServerFlagSet(config *serverConfig, configPath *string) *flag.FlagSet {
+	flagSet := flag.NewFlagSet("server", flag.ContinueOnError)
+	flagSet.Usage = func() {
+		_, _ = io.WriteString(flagSet.Output(),
+			"Usage: server [flags]\n\nEach flag can also be set with the environment variable "+envPrefix+"<FLAG_NAME>.\n\n")
+		flagSet.PrintDefaults()
+	}
+
+	flagSet.StringVar(configPath, "config", *configPath, "path to the JSON config file")
+	flagSet.StringVar(&config.MQTTPort, "mqtt-port", config.MQTTPort, "port of the MQTT server")
+	flagSet.StringVar(&config.MetricsPort, "metrics-port", config.MetricsPort, "port of the metrics and admin server")
+	flagSet.StringVar(&config.AdminToken, "admin-token", config.AdminToken,
+		"bearer token required by the admin API; the admin API is disabled when empty (prefer the environment variable to keep it out of process listings)")
+	flagSet.IntVar(&config.TicksPerSecond, "ticks-per-second", config.TicksPerSecond, "game ticks per second. all durations are in ticks")
+	flagSet.Int64Var(&config.Seed, "seed", config.Seed, "random seed of the game and bots. 0 uses the current time")
+	flagSet.IntVar(&config.InterestRadius, "interest-radius", config.InterestRadius, "send players only the states within this distance. 0 sends the whole board")
+
+	flagSet.IntVar(&config.Board.Width, "board-width", config.Board.Width, "board width")
+	flagSet.IntVar(&config.Board.Height, "board-height", config.Board.Height, "board height")
+
+	flagSet.StringVar(&config.Match.Mode, "mode", config.Match.Mode, "game mode (deathmatch, last_man_standing, team_deathmatch)")
+	flagSet.BoolVar(&config.Match.FriendlyFire, "friendly-fire", config.Match.FriendlyFire, "allow damaging teammates in team modes")
+	flagSet.IntVar(&config.Match.MinPlayers, "min-players", config.Match.MinPlayers, "players needed to start a match")
+	flagSet.IntVar(&config.Match.CountdownTicks, "countdown-ticks", config.Match.CountdownTicks, "countdown before a match starts")
+	flagSet.IntVar(&config.Match.TimeLimitTicks, "time-limit-ticks", config.Match.TimeLimitTicks, "match time limit. 0 means no limit")
+	flagSet.IntVar(&config.Match.ScoreLimit, "score-limit", config.Match.ScoreLimit, "score to win a match. 0 means no limit")
+	flagSet.IntVar(&config.Match.ResultTicks, "result-ticks", config.Match.ResultTicks, "time to show the result before the next match")
+
+	flagSet.IntVar(&config.Weapon.BulletCooldownTicks, "bullet-cooldown-ticks", config.Weapon.BulletCooldownTicks, "ticks between shots")
+	flagSet.IntVar(&config.Weapon.MaxAmmo, "max-ammo", config.Weapon.MaxAmmo, "magazine size. 0 means unlimited")
+	flagSet.IntVar(&config.Weapon.ReloadTicks, "reload-ticks", config.Weapon.ReloadTicks, "ticks to reload")
+	flagSet.IntVar(&config.Weapon.MaxBombs, "max-bombs", config.Weapon.MaxBombs, "bombs a player can place at once. 0 means unlimited")
+	flagSet.IntVar(&config.Weapon.BombExplosionTicks, "bomb-explosion-ticks", config.Weapon.BombExplosionTicks, "ticks until a bomb explodes (0: 3 seconds at any tick rate)")
+	flagSet.IntVar(&config.Weapon.BombFireRange, "bomb-fire-range", config.Weapon.BombFireRange, "cells a bomb explosion reaches")
+	flagSet.Func("bullet-move-ticks", "ticks a bullet takes to move one cell per weapon, like pistol=30,rifle=10", func(value string) error {
+		return parseBulletMoveTicks(value, config.Weapon.BulletMoveTicks)
+	})
+
+	flagSet.IntVar(&config.Movement.MoveTicks, "move-ticks", config.Movement.MoveTicks, "ticks a player takes to move one cell. 0 means unlimited")
+
+	flagSet.IntVar(&config.Arena.PillarInterval, "pillar-interval", config.Arena.PillarInterval, "interval of pillar walls. 0 means no walls")
+	flagSet.Float64Var(&config.Arena.BlockDensity, "block-density", config.Arena.BlockDensity, "probability of a block on an empty cell")
+	flagSet.Float64Var(&config.Arena.PowerUpChance, "power-up-chance", config.Arena.PowerUpChance, "probability of a power-up from a destroyed block")
+
+	flagSet.IntVar(&config.Loop.MaxCatchUpTicks, "max-catch-up-ticks", config.Loop.MaxCatchUpTicks, "extra ticks to run at once when the loop falls behind")
+
+	flagSet.IntVar(&config.Bots.Count, "bots", config.Bots.Count, "bots that always play")
+	flagSet.IntVar(&config.Bots.FillTo, "bots-fill-to", config.Bots.FillTo, "add bots until this many players are in the game")
+	flagSet.StringVar(&config.Bots.Difficulty, "bot-difficulty", config.Bots.Difficulty, "bot difficulty (easy, normal, hard)")
+
+	flagSet.StringVar(&config.Replay.Dir, "replay-dir", config.Replay.Dir, "directory to record match replays. empty disables recording")
+	flagSet.IntVar(&config.Replay.MaxFiles, "replay-max-files", config.Replay.MaxFiles, "replay files to keep. 0 means unlimited")
+	flagSet.Int64Var(&config.Replay.MaxFileBytes, "replay-max-file-bytes", config.Replay.MaxFileBytes, "size limit of a replay file. 0 means unlimited")
+
+	return flagSet
+}
+
+// parseBulletMoveTicks "pistol=30,rifle=10"の形式の値を読み、武器ごとの値をdstに上書きする
+func parseBulletMoveTicks(value string, dst map[string]int) error {
+	for _, pair := range strings.Split(value, ",") {
+		name, ticks, ok := strings.Cut(pair, "=")
+		if !ok {
+			return errors.Newf("invalid bullet move ticks: %q (expected weapon=ticks)", pair)
+		}
+		n, err := strconv.Atoi(ticks)
+		if err != nil {
+			return errors.Wrapf(err, "invalid bullet move ticks: %q", pair)
+		}
+		dst[strings.TrimSpace(name)] = n
+	}
+	return nil
+}
+
+// validate 設定の値が使えるものかを確認する。問題があれば全てまとめて返す
+//
+//nolint:cyclop
+func (c serverConfig) validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, errors.Newf(format, args...))
+		}
+	}
+
+	for name, port := range map[string]string{"mqtt_port": c.MQTTPort, "metrics_port": c.MetricsPort} {
+		n, err := strconv.Atoi(port)
+		check(err == nil && n > 0 && n <= 65535, "%s must be a port number: %q", name, port)
+	}
+	check(c.TicksPerSecond > 0 && c.TicksPerSecond <= maxTicksPerSecond,
+		"ticks_per_second must be between 1 and %d: %d", maxTicksPerSecond, c.TicksPerSecond)
+
+	check(c.Board.Width >= minBoardSize && c.Board.Width <= maxBoardSize,
+		"board.width must be between %d and %d: %d", minBoardSize, maxBoardSize, c.Board.Width)
+	check(c.Board.Height >= minBoardSize && c.Board.Height <= maxBoardSize,
+		"board.height must be between %d and %d: %d", minBoardSize, maxBoardSize, c.Board.Height)
+
+	if _, err := game.NewGameMode(game.GameModeName(c.Match.Mode), game.GameModeOptions{FriendlyFire: c.Match.FriendlyFire}); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid match.mode"))
+	}
+	for name, value := range map[string]int{
+		"interest_radius":              c.InterestRadius,
+		"match.min_players":            c.Match.MinPlayers,
+		"match.countdown_ticks":        c.Match.CountdownTicks,
+		"match.time_limit_ticks":       c.Match.TimeLimitTicks,
+		"match.score_limit":            c.Match.ScoreLimit,
+		"match.result_ticks":           c.Match.ResultTicks,
+		"weapon.bullet_cooldown_ticks": c.Weapon.BulletCooldownTicks,
+		"weapon.max_ammo":              c.Weapon.MaxAmmo,
+		"weapon.reload_ticks":          c.Weapon.ReloadTicks,
+		"weapon.max_bombs":             c.Weapon.MaxBombs,
+		"weapon.bomb_explosion_ticks":  c.Weapon.BombExplosionTicks,
+		"movement.move_ticks":          c.Movement.MoveTicks,
+		"arena.pillar_interval":        c.Arena.PillarInterval,
+		"loop.max_catch_up_ticks":      c.Loop.MaxCatchUpTicks,
+		"bots.count":                   c.Bots.Count,
+		"bots.fill_to":                 c.Bots.FillTo,
+		"replay.max_files":             c.Replay.MaxFiles,
+	} {
+		check(value >= 0, "%s must not be negative: %d", name, value)
+	}
+	check(c.Replay.MaxFileBytes >= 0, "replay.max_file_bytes must not be negative: %d", c.Replay.MaxFileBytes)
+
+	check(c.Weapon.BombFireRange > 0, "weapon.bomb_fire_range must be positive: %d", c.Weapon.BombFireRange)
+	for name, ticks := range c.Weapon.BulletMoveTicks {
+		if _, err := game.ParseWeaponType(name); err != nil {
+			errs = append(errs, errors.Wrap(err, "invalid weapon.bullet_move_ticks"))
+			continue
+		}
+		check(ticks > 0, "weapon.bullet_move_ticks.%s must be positive: %d", name, ticks)
+	}
+
+	check(c.Arena.BlockDensity >= 0 && c.Arena.BlockDensity <= 1, "arena.block_density must be between 0 and 1: %g", c.Arena.BlockDensity)
+	check(c.Arena.PowerUpChance >= 0 && c.Arena.PowerUpChance <= 1, "arena.power_up_chance must be between 0 and 1: %g", c.Arena.PowerUpChance)
+
+	if _, err := bot.ParseDifficulty(c.Bots.Difficulty); err != nil {
+		errs = append(errs, errors.Wrap(err, "invalid bots.difficulty"))
+	}
+
+	return errors.Join(errs...)
+}
+
+// toRunOptions 検証済みの設定をサーバーの起動時の設定に変換する
+func (c serverConfig) toRunOptions() *runOptions {
+	seed := c.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+
+	bulletMoveTicks := make(map[game.WeaponType]int, len(c.Weapon.BulletMoveTicks))
+	for name, ticks := range c.Weapon.BulletMoveTicks {
+		bulletMoveTicks[game.WeaponType(name)] = ticks
+	}
+
+	return &runOptions{
+		MQTTPort:       c.MQTTPort,
+		MetricsPort:    c.MetricsPort,
+		AdminToken:     c.AdminToken,
+		Width:          c.Board.Width,
+		Height:         c.Board.Height,
+		TicksPerSecond: c.TicksPerSecond,
+		Match: game.MatchConfig{
+			MinPlayers:     c.Match.MinPlayers,
+			CountdownTicks: c.Match.CountdownTicks,
+			TimeLimitTicks: c.Match.TimeLimitTicks,
+			ScoreLimit:     c.Match.ScoreLimit,
+			ResultTicks:    c.Match.ResultTicks,
+		},
+		Mode:         game.GameModeName(c.Match.Mode),
+		FriendlyFire: c.Match.FriendlyFire,
+		Weapon: game.WeaponConfig{
+			BulletCooldownTicks: c.Weapon.BulletCooldownTicks,
+			MaxAmmo:             c.Weapon.MaxAmmo,
+			ReloadTicks:         c.Weapon.ReloadTicks,
+			MaxBombs:            c.Weapon.MaxBombs,
+			BombExplosionTicks:  c.Weapon.BombExplosionTicks,
+			BombFireRange:       c.Weapon.BombFireRange,
+			BulletMoveTicks:     bulletMoveTicks,
+		},
+		Movement: game.MovementConfig{MoveTicks: c.Movement.MoveTicks},
+		Arena: game.ArenaConfig{
+			PillarInterval: c.Arena.PillarInterval,
+			BlockDensity:   c.Arena.BlockDensity,
+			PowerUpChance:  c.Arena.PowerUpChance,
+		},
+		Seed:           seed,
+		InterestRadius: c.InterestRadius,
+		Loop:           game.LoopConfig{MaxCatchUpTicks: c.Loop.MaxCatchUpTicks},
+		Bots: bot.Config{
+			Count:      c.Bots.Count,
+			FillTo:     c.Bots.FillTo,
+			Difficulty: bot.Difficulty(c.Bots.Difficulty),
+			Seed:       seed,
+		},
+		Replay: ReplayConfig{
+			Dir:          c.Replay.Dir,
+			MaxFiles:     c.Replay.MaxFiles,
+			MaxFileBytes: c.Replay.MaxFileBytes,
+		},
+	}
+}
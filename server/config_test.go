@@ -0,0 +1,137 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/shibayu36/terminal-shooter/server/game"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.json")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func envOf(env map[string]string) func(string) string {
+	return func(name string) string {
+		return env[name]
+	}
+}
+
+func TestParseServerConfig(t *testing.T) {
+	t.Run("何も指定しなければ初期値を使う", func(t *testing.T) {
+		config, err := parseServerConfig(nil, envOf(nil), io.Discard)
+		require.NoError(t, err)
+		assert.Equal(t, defaultServerConfig(), *config)
+	})
+
+	t.Run("設定ファイル、環境変数、フラグの順に優先される", func(t *testing.T) {
+		path := writeConfigFile(t, `{
+			"mqtt_port": "1884",
+			"metrics_port": "2113",
+			"ticks_per_second": 30,
+			"board": {"width": 40},
+			"weapon": {"bomb_explosion_ticks": 90, "bullet_move_ticks": {"pistol": 15}}
+		}`)
+		env := map[string]string{
+			"TERMINAL_SHOOTER_CONFIG":       path,
+			"TERMINAL_SHOOTER_METRICS_PORT": "2114",
+			"TERMINAL_SHOOTER_BOARD_HEIGHT": "20",
+		}
+		config, err := parseServerConfig(
+			[]string{"-board-height", "25", "-bullet-move-ticks", "rifle=5"},
+			envOf(env),
+			io.Discard,
+		)
+		require.NoError(t, err)
+
+		assert.Equal(t, "1884", config.MQTTPort, "設定ファイルの値")
+		assert.Equal(t, "2114", config.MetricsPort, "環境変数が設定ファイルより優先される")
+		assert.Equal(t, 30, config.TicksPerSecond)
+		assert.Equal(t, 40, config.Board.Width)
+		assert.Equal(t, 25, config.Board.Height, "フラグが環境変数より優先される")
+		assert.Equal(t, 90, config.Weapon.BombExplosionTicks)
+		assert.Equal(t, game.BombFireRange, config.Weapon.BombFireRange, "設定ファイルに書かれていない項目は初期値のまま")
+		assert.Equal(t, map[string]int{"pistol": 15, "rifle": 5}, config.Weapon.BulletMoveTicks)
+	})
+
+	t.Run("-configで指定した設定ファイルを読む", func(t *testing.T) {
+		path := writeConfigFile(t, `{"match": {"mode": "team_deathmatch", "friendly_fire": true}}`)
+		config, err := parseServerConfig([]string{"-config", path}, envOf(nil), io.Discard)
+		require.NoError(t, err)
+		assert.Equal(t, "team_deathmatch", config.Match.Mode)
+		assert.True(t, config.Match.FriendlyFire)
+	})
+
+	t.Run("設定ファイルの知らない項目はエラーになる", func(t *testing.T) {
+		path := writeConfigFile(t, `{"board": {"widht": 40}}`)
+		_, err := parseServerConfig([]string{"-config", path}, envOf(nil), io.Discard)
+		assert.ErrorContains(t, err, "widht")
+	})
+
+	t.Run("読めない環境変数はエラーになる", func(t *testing.T) {
+		_, err := parseServerConfig(nil, envOf(map[string]string{"TERMINAL_SHOOTER_SEED": "abc"}), io.Discard)
+		assert.ErrorContains(t, err, "TERMINAL_SHOOTER_SEED")
+	})
+
+	t.Run("使えない値は全てまとめてエラーになる", func(t *testing.T) {
+		_, err := parseServerConfig(
+			[]string{
+				"-mqtt-port", "http",
+				"-ticks-per-second", "0",
+				"-board-width", "2",
+				"-mode", "capture_the_flag",
+				"-bomb-explosion-ticks", "-1",
+				"-bullet-move-ticks", "laser=10",
+				"-block-density", "1.5",
+				"-bot-difficulty", "insane",
+				"-max-ammo", "-1",
+				"-interest-radius", "-1",
+			},
+			envOf(nil),
+			io.Discard,
+		)
+		require.Error(t, err)
+		for _, field := range []string{
+			"mqtt_port",
+			"ticks_per_second",
+			"board.width",
+			"match.mode",
+			"weapon.bomb_explosion_ticks",
+			"weapon.bullet_move_ticks",
+			"arena.block_density",
+			"bots.difficulty",
+			"weapon.max_ammo",
+			"interest_radius",
+		} {
+			assert.ErrorContains(t, err, field)
+		}
+	})
+}
+
+func TestServerConfig_ToRunOptions(t *testing.T) {
+	config := defaultServerConfig()
+	config.Seed = 42
+	config.Weapon.BulletMoveTicks = map[string]int{"rifle": 5}
+
+	opts := config.toRunOptions()
+	assert.Equal(t, 30, opts.Width)
+	assert.Equal(t, game.TicksPerSecond, opts.TicksPerSecond)
+	assert.Equal(t, game.GameModeDeathmatch, opts.Mode)
+	assert.Equal(t, 0, opts.Weapon.BombExplosionTicks, "1秒あたりのtick数から決める")
+	assert.Equal(t, map[game.WeaponType]int{game.WeaponTypeRifle: 5}, opts.Weapon.BulletMoveTicks)
+	assert.Equal(t, int64(42), opts.Seed)
+	assert.Equal(t, int64(42), opts.Bots.Seed)
+	assert.Zero(t, opts.Bots.FillTo, "初期値ではボットは参加しない")
+	assert.Empty(t, opts.Replay.Dir, "初期値ではリプレイを記録しない")
+
+	// シードが0なら起動するたびに変わる
+	config.Seed = 0
+	assert.NotZero(t, config.toRunOptions().Seed)
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/shibayu36/terminal-shooter/server/bot"
 	"github.com/shibayu36/terminal-shooter/server/game"
 	"github.com/shibayu36/terminal-shooter/server/stats"
 	"github.com/shibayu36/terminal-shooter/shared"
@@ -16,24 +17,52 @@ import (
 
 // Controller クライアントからのパケットをゲームの状態に反映し、さらに他のクライアントに状態同期をする役割を持つ
 type Controller struct {
-	broker *Broker
-	game   *game.Game
+	broker   *Broker
+	game     *game.Game
+	chat     *ChatModerator
+	interest *interest
 }
 
 var _ Hooker = (*Controller)(nil)
 
 func NewController(broker *Broker, game *game.Game) *Controller {
-	return &Controller{broker: broker, game: game}
+	return NewControllerWithConfig(broker, game, ControllerConfig{InterestRadius: 0})
+}
+
+func NewControllerWithConfig(broker *Broker, game *game.Game, config ControllerConfig) *Controller {
+	return &Controller{
+		broker:   broker,
+		game:     game,
+		chat:     NewChatModerator(DefaultChatConfig),
+		interest: newInterest(config.InterestRadius),
+	}
+}
+
+// ChatModerator チャットのミュートやBANを操作するためのModeratorを返す
+func (c *Controller) ChatModerator() *ChatModerator {
+	return c.chat
 }
 
 func (c *Controller) OnConnected(client Client, _ *packets.ConnectPacket) error {
-	c.broker.AddClient(client)
-	c.game.AddPlayer(game.PlayerID(client.ID()))
+	// ボットのIDで接続されるとボットのプレイヤーを乗っ取れてしまうので受け付けない
+	if bot.IsBot(game.PlayerID(client.ID())) {
+		return errors.Wrapf(ErrClientIDRejected, "client id %q is reserved for bots", client.ID())
+	}
+	if !c.broker.AddClient(client) {
+		return errors.Wrapf(ErrClientIDRejected, "client id %q is already connected", client.ID())
+	}
 
-	stats.ActiveClients.Inc()
+	// 観戦者は状態を受け取るだけで、プレイヤーとしては参加しない
+	if shared.IsSpectatorClientID(client.ID()) {
+		stats.ActiveClients.Inc()
+		stats.ActiveSpectators.Inc()
+		slog.Info("spectator connected", "client_id", client.ID())
+		return nil
+	}
 
-	// Player状態を出力
-	slog.Info("all players", "players", c.game.String())
+	// プレイヤーはjoinを受け付けてから作る。それまでは状態を受け取るだけ
+	c.chat.Register(game.PlayerID(client.ID()), client.RemoteHost())
+	stats.ActiveClients.Inc()
 
 	return nil
 }
@@ -60,15 +89,47 @@ func (c *Controller) OnSubscribed(client Client, _ *packets.SubscribePacket) err
 		}
 	}
 
+	// 変化したアイテムしか配信されないので、途中から参加したクライアントには今あるアイテムを全て送信する
+	for _, item := range c.game.GetItems() {
+		payload, err := proto.Marshal(toSharedItemState(item))
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal item state")
+		}
+		err = c.broker.Send(client.ID(), "item_state", payload)
+		if err != nil {
+			return errors.Wrap(err, "failed to send item state")
+		}
+	}
+
+	// 現在のマッチの状態を送信する
+	payload, err := proto.Marshal(c.sharedMatchState())
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal match state")
+	}
+	err = c.broker.Send(client.ID(), "match_state", payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to send match state")
+	}
+
 	return nil
 }
 
 func (c *Controller) OnPublished(client Client, publishPacket *packets.PublishPacket) error {
+	// 観戦者は操作できないので、送られてきたパケットは無視する
+	if shared.IsSpectatorClientID(client.ID()) {
+		slog.Debug("ignore packet from spectator", "client_id", client.ID(), "topic", publishPacket.TopicName)
+		return nil
+	}
+
 	switch publishPacket.TopicName {
 	case "player_state":
 		return c.onReceivePlayerState(client, publishPacket)
 	case "player_action":
 		return c.onReceivePlayerAction(client, publishPacket)
+	case "join":
+		return c.onReceiveJoin(client, publishPacket)
+	case "chat":
+		return c.onReceiveChat(client, publishPacket)
 	default:
 		return errors.New(fmt.Sprintf("invalid topic name: %s", publishPacket.TopicName))
 	}
@@ -80,21 +141,15 @@ func (c *Controller) OnDisconnected(client Client) error {
 
 	stats.ActiveClients.Dec()
 
-	c.game.RemovePlayer(game.PlayerID(client.ID()))
-
-	playerState := &shared.PlayerState{
-		PlayerId: client.ID(),
-		Status:   shared.Status_DISCONNECTED,
-	}
-	payload, err := proto.Marshal(playerState)
-	if err != nil {
-		return errors.Wrap(err, "failed to marshal player state")
-	}
-	err = c.broker.Broadcast("player_state", payload)
-	if err != nil {
-		return errors.Wrap(err, "failed to broadcast player state")
+	if shared.IsSpectatorClientID(client.ID()) {
+		stats.ActiveSpectators.Dec()
+		return nil
 	}
 
+	// 切断したことはゲームループからPlayerLeftとして通知される
+	c.game.RemovePlayer(game.PlayerID(client.ID()))
+	c.chat.Forget(game.PlayerID(client.ID()), time.Now())
+
 	return nil
 }
 
@@ -114,7 +169,7 @@ func (c *Controller) onReceivePlayerState(client Client, publishPacket *packets.
 		return nil
 	}
 
-	updatedPlayer := c.game.MovePlayer(
+	updatedPlayer, result := c.game.RequestMove(
 		playerID,
 		game.Position{
 			X: int(playerState.GetPosition().GetX()),
@@ -122,21 +177,199 @@ func (c *Controller) onReceivePlayerState(client Client, publishPacket *packets.
 		},
 		direction,
 	)
+	if updatedPlayer == nil || result != game.MoveResultRejected {
+		// 移動した場合はゲームループからイベントとして通知される
+		return nil
+	}
 
+	// 移動できなかった場合は、クライアントの位置を戻すために現在の状態を送る
 	payload, err := proto.Marshal(updatedPlayer.ToSharedPlayerState())
 	if err != nil {
 		return errors.Wrap(err, "failed to marshal player state")
 	}
-	err = c.broker.Broadcast("player_state", payload)
+	err = c.broker.Send(client.ID(), "player_state", payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to send player state")
+	}
+
+	return nil
+}
+
+// joinパケットを受信した時の処理
+// 表示名と希望するチームを反映し、結果をリクエストしたクライアントにのみ返す
+func (c *Controller) onReceiveJoin(client Client, publishPacket *packets.PublishPacket) error {
+	playerID := game.PlayerID(client.ID())
+
+	joinRequest := &shared.JoinRequest{}
+	err := proto.Unmarshal(publishPacket.Payload, joinRequest)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal join request")
+	}
+
+	joinResponse := c.join(playerID, joinRequest)
+	slog.Info("join requested",
+		"client_id", client.ID(),
+		"display_name", joinRequest.GetDisplayName(),
+		"client_version", joinRequest.GetClientVersion(),
+		"result", joinResponse.GetResult().String(),
+	)
+
+	payload, err := proto.Marshal(joinResponse)
 	if err != nil {
-		return errors.Wrap(err, "failed to broadcast player state")
+		return errors.Wrap(err, "failed to marshal join response")
+	}
+	err = c.broker.Send(client.ID(), "join_response", payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to send join response")
+	}
+
+	return nil
+}
+
+// join 参加リクエストを検証してゲームに反映する
+// 受け付けた場合にだけプレイヤーを作るので、受け付けなかったクライアントはゲームに参加しない
+func (c *Controller) join(playerID game.PlayerID, joinRequest *shared.JoinRequest) *shared.JoinResponse {
+	if joinRequest.GetClientVersion() != shared.ProtocolVersion {
+		return &shared.JoinResponse{
+			Result:   shared.JoinResult_JOIN_VERSION_MISMATCH,
+			PlayerId: string(playerID),
+			Message:  fmt.Sprintf("client version %q is not supported (server: %q)", joinRequest.GetClientVersion(), shared.ProtocolVersion),
+		}
+	}
+
+	player, err := c.game.JoinPlayer(playerID, joinRequest.GetDisplayName())
+	switch {
+	case errors.Is(err, game.ErrDisplayNameInvalid):
+		return &shared.JoinResponse{
+			Result:   shared.JoinResult_JOIN_NAME_INVALID,
+			PlayerId: string(playerID),
+			Message:  fmt.Sprintf("display name must be 1-%d printable characters without offensive words", game.MaxDisplayNameLength),
+		}
+	case errors.Is(err, game.ErrDisplayNameTaken):
+		return &shared.JoinResponse{
+			Result:   shared.JoinResult_JOIN_NAME_TAKEN,
+			PlayerId: string(playerID),
+			Message:  "display name is already taken",
+		}
+	case err != nil:
+		return &shared.JoinResponse{
+			Result:   shared.JoinResult_JOIN_NAME_INVALID,
+			PlayerId: string(playerID),
+			Message:  err.Error(),
+		}
+	}
+
+	// 希望するチームはSwitchTeamのルールで変更できる場合のみ反映する
+	if joinRequest.GetPreferredTeam() != shared.Team_NO_TEAM {
+		_ = c.switchTeam(playerID, joinRequest.GetPreferredTeam())
 	}
 
+	// Player状態を出力
 	slog.Info("all players", "players", c.game.String())
 
+	return &shared.JoinResponse{
+		Result:      shared.JoinResult_JOIN_ACCEPTED,
+		PlayerId:    string(playerID),
+		DisplayName: player.DisplayName(),
+	}
+}
+
+// chatパケットを受信した時の処理
+// 制限を満たす場合は送信者を埋めて配信し、満たさない場合は理由を送信者にのみ返す
+func (c *Controller) onReceiveChat(client Client, publishPacket *packets.PublishPacket) error {
+	playerID := game.PlayerID(client.ID())
+
+	chatMessage := &shared.ChatMessage{}
+	err := proto.Unmarshal(publishPacket.Payload, chatMessage)
+	if err != nil {
+		return errors.Wrap(err, "failed to unmarshal chat message")
+	}
+
+	player, ok := c.game.GetPlayers()[playerID]
+	if !ok {
+		return nil
+	}
+
+	text, err := c.chat.Check(playerID, chatMessage.GetText(), time.Now())
+	if err != nil {
+		slog.Info("chat message rejected", "client_id", client.ID(), "reason", err.Error())
+		return c.sendChatNotice(client.ID(), c.chatRejectedText(err))
+	}
+
+	// チームに所属していない場合はチーム宛てにできないので全員に配信する
+	scope := chatMessage.GetScope()
+	if player.Team() == game.TeamNone {
+		scope = shared.ChatScope_CHAT_SCOPE_ALL
+	}
+
+	payload, err := proto.Marshal(&shared.ChatMessage{
+		SenderId:   string(playerID),
+		SenderName: player.DisplayName(),
+		Text:       text,
+		Scope:      scope,
+		System:     false,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal chat message")
+	}
+
+	if scope == shared.ChatScope_CHAT_SCOPE_ALL {
+		err = c.broker.Broadcast("chat", payload)
+		if err != nil {
+			return errors.Wrap(err, "failed to broadcast chat message")
+		}
+		return nil
+	}
+
+	var errs []error
+	for memberID, member := range c.game.GetPlayers() {
+		// ボットはクライアントがないので送らない
+		if member.Team() != player.Team() || bot.IsBot(memberID) {
+			continue
+		}
+		if err := c.broker.Send(string(memberID), "chat", payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return errors.Wrap(err, "failed to send team chat message")
+	}
+	return nil
+}
+
+// sendChatNotice serverからのお知らせをクライアントにチャットとして送る
+func (c *Controller) sendChatNotice(clientID string, text string) error {
+	payload, err := proto.Marshal(&shared.ChatMessage{
+		Text:   text,
+		Scope:  shared.ChatScope_CHAT_SCOPE_ALL,
+		System: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal chat message")
+	}
+	err = c.broker.Send(clientID, "chat", payload)
+	if err != nil {
+		return errors.Wrap(err, "failed to send chat message")
+	}
 	return nil
 }
 
+// chatRejectedText チャットが受け付けられなかった理由をプレイヤー向けの文にする
+func (c *Controller) chatRejectedText(err error) string {
+	switch {
+	case errors.Is(err, ErrChatBanned):
+		return "You are banned from chat"
+	case errors.Is(err, ErrChatMuted):
+		return "You are muted"
+	case errors.Is(err, ErrChatRateLimited):
+		return "You are sending messages too fast"
+	case errors.Is(err, ErrChatTooLong):
+		return fmt.Sprintf("Message is too long (max %d characters)", c.chat.config.MaxLength)
+	default:
+		return "Message was not sent"
+	}
+}
+
 func (c *Controller) onReceivePlayerAction(client Client, publishPacket *packets.PublishPacket) error {
 	playerID := game.PlayerID(client.ID())
 
@@ -151,21 +384,46 @@ func (c *Controller) onReceivePlayerAction(client Client, publishPacket *packets
 		c.game.ShootBullet(playerID)
 	case shared.ActionType_PLACE_BOMB:
 		c.game.PlaceBomb(playerID)
+	case shared.ActionType_SWITCH_TEAM:
+		return c.switchTeam(playerID, playerActionRequest.GetTeam())
+	case shared.ActionType_RELOAD:
+		c.game.Reload(playerID)
+	case shared.ActionType_SELECT_WEAPON:
+		weaponType, err := game.FromSharedWeaponType(playerActionRequest.GetWeapon())
+		if err != nil {
+			// 武器が不正な場合は無視する
+			//nolint:nilerr
+			return nil
+		}
+		c.game.SelectWeapon(playerID, weaponType)
 	}
 
 	return nil
 }
 
-// StartPublishLoop ゲームの状態を定期的にpublishするループを開始する
-func (c *Controller) StartPublishLoop(ctx context.Context, updatedCh <-chan game.UpdatedResult) {
+// チームを変更する。変更できた場合はゲームループからイベントとして全員に通知される
+func (c *Controller) switchTeam(playerID game.PlayerID, sharedTeam shared.Team) error {
+	team, err := game.FromSharedTeam(sharedTeam)
+	if err != nil {
+		// チームが不正な場合は無視する
+		//nolint:nilerr
+		return nil
+	}
+
+	c.game.SwitchTeam(playerID, team)
+	return nil
+}
+
+// StartPublishLoop ゲームのイベントを受け取り、変化したものだけをpublishするループを開始する
+func (c *Controller) StartPublishLoop(ctx context.Context, eventCh <-chan game.Event) {
 	go func() {
 		for {
 			select {
-			case updatedResult, ok := <-updatedCh:
+			case event, ok := <-eventCh:
 				if !ok {
 					return
 				}
-				c.publishStates(updatedResult)
+				c.publishEvent(event)
 			case <-ctx.Done():
 				return
 			}
@@ -173,77 +431,191 @@ func (c *Controller) StartPublishLoop(ctx context.Context, updatedCh <-chan game
 	}()
 }
 
-func (c *Controller) publishStates(updatedResult game.UpdatedResult) {
+func (c *Controller) publishEvent(event game.Event) {
 	start := time.Now()
 	defer func() {
 		stats.PublishStatesDuration.Observe(time.Since(start).Seconds())
 	}()
 
-	switch updatedResult.Type {
-	case game.UpdatedResultTypeItemsUpdated:
-		c.publishItemStates()
-	case game.UpdatedResultTypePlayersUpdated:
-		c.publishPlayerStates()
+	if gameEvent := toSharedGameEvent(event); gameEvent != nil {
+		c.publishGameEvent(gameEvent)
+	}
+
+	switch event := event.(type) {
+	case game.PlayerJoined:
+		c.publishPlayerState(event.Player)
+	case game.PlayerMoved:
+		c.publishPlayerState(event.Player)
+	case game.PlayerUpdated:
+		c.publishPlayerState(event.Player)
+	case game.PlayerDied:
+		c.publishPlayerState(event.Player)
+	case game.PlayerLeft:
+		c.publishLeftPlayer(event.Player)
+	case game.ItemSpawned:
+		c.publishItemState(event.Item)
+	case game.ItemMoved:
+		c.publishItemState(event.Item)
+	case game.ItemRemoved:
+		c.publishRemovedItem(event.Item)
+	case game.MatchUpdated:
+		c.publishMatchState()
 	}
+	c.forget(event)
 }
 
-func (c *Controller) publishItemStates() {
-	// Activeなアイテムを送信する
-	for _, item := range c.game.GetItems() {
-		itemState := &shared.ItemState{
-			ItemId: string(item.ID()),
-			Type:   item.Type().ToSharedItemType(),
-			Position: &shared.Position{
-				X: int32(item.Position().X),
-				Y: int32(item.Position().Y),
-			},
-			Status: shared.ItemStatus_ACTIVE,
-		}
+func (c *Controller) publishItemState(item game.Item) {
+	payload, err := proto.Marshal(toSharedItemState(item))
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to marshal item state\n%+v", err))
+		return
+	}
+	// 前回の位置を見ていたプレイヤーにも、範囲から出たことが分かるように配信する
+	positions := []game.Position{item.Position()}
+	if previous, moved := c.interest.trackItem(item); moved {
+		positions = append(positions, previous)
+	}
+	err = c.publishAround("item_state", payload, positions...)
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to broadcast item state\n%+v", err))
+	}
+}
 
-		payload, err := proto.Marshal(itemState)
-		if err != nil {
-			slog.Error(fmt.Sprintf("failed to marshal item state\n%+v", err))
-			continue
-		}
-		err = c.broker.Broadcast("item_state", payload)
-		if err != nil {
-			slog.Error(fmt.Sprintf("failed to broadcast item state\n%+v", err))
-		}
+// publishLeftPlayer 切断したりボットが減ったりして盤面から消えたプレイヤーを、全員に切断した状態として配信する
+// クライアントはこれを受け取るとプレイヤーを消す
+func (c *Controller) publishLeftPlayer(player *game.Player) {
+	payload, err := proto.Marshal(&shared.PlayerState{
+		PlayerId: string(player.PlayerID),
+		Status:   shared.Status_DISCONNECTED,
+	})
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to marshal player state\n%+v", err))
+		return
 	}
+	err = c.broker.Broadcast("player_state", payload)
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to broadcast player state\n%+v", err))
+	}
+}
 
-	// 削除されたアイテムを送信する
-	for _, removedItem := range c.game.GetRemovedItems() {
-		itemState := &shared.ItemState{
-			ItemId: string(removedItem.ID()),
-			Status: shared.ItemStatus_REMOVED,
-		}
+func (c *Controller) publishRemovedItem(item game.Item) {
+	payload, err := proto.Marshal(toRemovedItemState(item))
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to marshal item state\n%+v", err))
+		return
+	}
+	err = c.broker.Broadcast("item_state", payload)
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to broadcast item state\n%+v", err))
+	}
+}
 
-		payload, err := proto.Marshal(itemState)
-		if err != nil {
-			slog.Error(fmt.Sprintf("failed to marshal item state\n%+v", err))
-			continue
-		}
-		err = c.broker.Broadcast("item_state", payload)
-		if err != nil {
-			slog.Error(fmt.Sprintf("failed to broadcast item state\n%+v", err))
-			continue
-		}
+func (c *Controller) publishPlayerState(player *game.Player) {
+	payload, err := proto.Marshal(player.ToSharedPlayerState())
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to marshal player state\n%+v", err))
+		return
+	}
+	positions := []game.Position{player.Position()}
+	previous, moved := c.interest.trackPlayer(player)
+	if moved {
+		positions = append(positions, previous)
+	}
+	err = c.publishAround("player_state", payload, positions...)
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to broadcast player state\n%+v", err))
+	}
+	if moved {
+		c.sendEnteredView(player, previous)
+	}
+}
 
-		// Broadcastが成功したら削除アイテムは不要になる
-		c.game.ClearRemovedItem(removedItem.ID())
+func (c *Controller) publishGameEvent(gameEvent *shared.GameEvent) {
+	payload, err := proto.Marshal(gameEvent)
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to marshal game event\n%+v", err))
+		return
+	}
+	err = c.broker.Broadcast("game_event", payload)
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to broadcast game event\n%+v", err))
 	}
 }
 
-func (c *Controller) publishPlayerStates() {
-	for _, player := range c.game.GetPlayers() {
-		payload, err := proto.Marshal(player.ToSharedPlayerState())
-		if err != nil {
-			slog.Error(fmt.Sprintf("failed to marshal player state\n%+v", err))
-			continue
+func (c *Controller) publishMatchState() {
+	payload, err := proto.Marshal(c.sharedMatchState())
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to marshal match state\n%+v", err))
+		return
+	}
+	err = c.broker.Broadcast("match_state", payload)
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to broadcast match state\n%+v", err))
+	}
+}
+
+// toSharedItemState アクティブなアイテムをshared.ItemStateに変換する
+func toSharedItemState(item game.Item) *shared.ItemState {
+	itemState := &shared.ItemState{
+		ItemId: string(item.ID()),
+		Type:   item.Type().ToSharedItemType(),
+		Position: &shared.Position{
+			X: int32(item.Position().X),
+			Y: int32(item.Position().Y),
+		},
+		Status: shared.ItemStatus_ACTIVE,
+	}
+	if bullet, ok := item.(*game.Bullet); ok {
+		itemState.Weapon = bullet.WeaponType().ToSharedWeaponType()
+	}
+	return itemState
+}
+
+// toRemovedItemState 削除されたアイテムをshared.ItemStateに変換する
+func toRemovedItemState(item game.Item) *shared.ItemState {
+	return &shared.ItemState{
+		ItemId: string(item.ID()),
+		Status: shared.ItemStatus_REMOVED,
+	}
+}
+
+// toSharedGameEvent キルフィードなどで表示する出来事をshared.GameEventに変換する
+// 表示対象でないイベントの場合はnilを返す
+func toSharedGameEvent(event game.Event) *shared.GameEvent {
+	switch event := event.(type) {
+	case game.PlayerDied:
+		playerKilled := &shared.PlayerKilled{
+			VictimId: string(event.Player.PlayerID),
+			KillerId: string(event.KillerID),
 		}
-		err = c.broker.Broadcast("player_state", payload)
-		if err != nil {
-			slog.Error(fmt.Sprintf("failed to broadcast player state\n%+v", err))
+		if event.Cause != nil {
+			playerKilled.Cause = event.Cause.Type().ToSharedItemType()
+		}
+		if bullet, ok := event.Cause.(*game.Bullet); ok {
+			playerKilled.Weapon = bullet.WeaponType().ToSharedWeaponType()
 		}
+		return &shared.GameEvent{Event: &shared.GameEvent_PlayerKilled{PlayerKilled: playerKilled}}
+	case game.BombExploded:
+		return &shared.GameEvent{Event: &shared.GameEvent_BombExploded{BombExploded: &shared.BombExploded{
+			OwnerId: string(event.Bomb.OwnerID()),
+			Position: &shared.Position{
+				X: int32(event.Bomb.Position().X),
+				Y: int32(event.Bomb.Position().Y),
+			},
+		}}}
+	case game.PlayerJoined:
+		return &shared.GameEvent{Event: &shared.GameEvent_PlayerJoined{PlayerJoined: &shared.PlayerJoined{
+			PlayerId: string(event.Player.PlayerID),
+		}}}
+	case game.PlayerLeft:
+		return &shared.GameEvent{Event: &shared.GameEvent_PlayerLeft{PlayerLeft: &shared.PlayerLeft{
+			PlayerId: string(event.Player.PlayerID),
+		}}}
+	case game.PowerUpCollected:
+		return &shared.GameEvent{Event: &shared.GameEvent_PowerUpCollected{PowerUpCollected: &shared.PowerUpCollected{
+			PlayerId: string(event.Player.PlayerID),
+			Type:     event.PowerUp.Type().ToSharedItemType(),
+		}}}
 	}
+	return nil
 }
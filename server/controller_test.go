@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/eclipse/paho.mqtt.golang/packets"
+	"github.com/shibayu36/terminal-shooter/server/bot"
 	"github.com/shibayu36/terminal-shooter/server/game"
 	"github.com/shibayu36/terminal-shooter/shared"
 	"github.com/stretchr/testify/assert"
@@ -18,6 +19,7 @@ import (
 
 type mockClient struct {
 	id        string
+	host      string
 	published []*packets.PublishPacket
 	mu        sync.Mutex
 }
@@ -26,6 +28,10 @@ func (c *mockClient) ID() string {
 	return c.id
 }
 
+func (c *mockClient) RemoteHost() string {
+	return c.host
+}
+
 func (c *mockClient) Publish(publishPacket *packets.PublishPacket) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -39,23 +45,55 @@ func (c *mockClient) Published() []*packets.PublishPacket {
 	return c.published
 }
 
+// publishEvents ゲームを1tick進め、起きたイベントを配信する
+func publishEvents(controller *Controller, state *game.Game) {
+	for _, event := range state.Step() {
+		controller.publishEvent(event)
+	}
+}
+
+// connectPlayer クライアントを接続し、IDを表示名にしてゲームに参加させる
+func connectPlayer(t *testing.T, controller *Controller, client *mockClient) {
+	t.Helper()
+	require.NoError(t, controller.OnConnected(client, nil))
+	joinPlayer(t, controller, client)
+}
+
+// joinPlayer 接続済みのクライアントをIDを表示名にしてゲームに参加させ、届いたjoin_responseを消す
+func joinPlayer(t *testing.T, controller *Controller, client *mockClient) {
+	t.Helper()
+	payload, err := proto.Marshal(&shared.JoinRequest{
+		DisplayName:   client.id,
+		PreferredTeam: shared.Team_NO_TEAM,
+		ClientVersion: shared.ProtocolVersion,
+	})
+	require.NoError(t, err)
+	require.NoError(t, controller.OnPublished(client, &packets.PublishPacket{TopicName: "join", Payload: payload}))
+	require.Contains(t, controller.game.GetPlayers(), game.PlayerID(client.id))
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	client.published = nil
+}
+
 func TestController_OnConnected(t *testing.T) {
 	broker := NewBroker()
 	state := game.NewGame(30, 30)
 	controller := NewController(broker, state)
 
 	cl1 := &mockClient{id: "id1"}
-	err := controller.OnConnected(cl1, nil)
-	require.NoError(t, err)
+	require.NoError(t, controller.OnConnected(cl1, nil))
+	assert.Equal(t, broker.clients[cl1.id], cl1, "cl1がbrokerに追加された")
+	assert.Empty(t, state.GetPlayers(), "joinを受け付けるまではプレイヤーを作らない")
+
+	joinPlayer(t, controller, cl1)
 	p1 := state.GetPlayers()[game.PlayerID("id1")]
 	assert.Equal(t, game.Position{X: 0, Y: 0}, p1.Position())
 	assert.Equal(t, game.DirectionUp, p1.Direction())
 	assert.Equal(t, game.PlayerStatusAlive, p1.Status())
-	assert.Equal(t, broker.clients[cl1.id], cl1, "cl1がbrokerに追加された")
 
 	cl2 := &mockClient{id: "id2"}
-	err = controller.OnConnected(cl2, nil)
-	require.NoError(t, err)
+	connectPlayer(t, controller, cl2)
 	p2 := state.GetPlayers()[game.PlayerID("id2")]
 	assert.Equal(t, game.Position{X: 0, Y: 0}, p2.Position())
 	assert.Equal(t, game.DirectionUp, p2.Direction())
@@ -63,6 +101,90 @@ func TestController_OnConnected(t *testing.T) {
 	assert.Equal(t, broker.clients[cl2.id], cl2, "cl2がbrokerに追加された")
 }
 
+func TestController_OnConnected_RejectedClientID(t *testing.T) {
+	broker := NewBroker()
+	state := game.NewGame(30, 30)
+	controller := NewController(broker, state)
+
+	t.Run("ボットのIDでは接続できない", func(t *testing.T) {
+		state.AddPlayer("bot-1")
+		botPlayer := state.GetPlayers()["bot-1"]
+
+		err := controller.OnConnected(&mockClient{id: "bot-1"}, nil)
+		require.ErrorIs(t, err, ErrClientIDRejected)
+		assert.Same(t, botPlayer, state.GetPlayers()["bot-1"], "ボットのプレイヤーは上書きされない")
+		assert.NotContains(t, broker.clients, "bot-1")
+	})
+
+	t.Run("接続中のクライアントと同じIDでは接続できない", func(t *testing.T) {
+		cl1 := &mockClient{id: "id1"}
+		connectPlayer(t, controller, cl1)
+		player := state.GetPlayers()["id1"]
+
+		err := controller.OnConnected(&mockClient{id: "id1"}, nil)
+		require.ErrorIs(t, err, ErrClientIDRejected)
+		assert.Same(t, player, state.GetPlayers()["id1"])
+		assert.Equal(t, broker.clients["id1"], cl1, "先に接続したクライアントのまま")
+
+		spectatorID := shared.SpectatorClientIDPrefix + "1"
+		spectator := &mockClient{id: spectatorID}
+		require.NoError(t, controller.OnConnected(spectator, nil))
+		err = controller.OnConnected(&mockClient{id: spectatorID}, nil)
+		require.ErrorIs(t, err, ErrClientIDRejected)
+		assert.Equal(t, broker.clients[spectatorID], spectator)
+	})
+}
+
+func TestController_Spectator(t *testing.T) {
+	broker := NewBroker()
+	state := game.NewGame(30, 30)
+	controller := NewController(broker, state)
+
+	cl1 := &mockClient{id: "id1"}
+	connectPlayer(t, controller, cl1)
+	state.MovePlayer("id1", game.Position{X: 5, Y: 10}, game.DirectionRight)
+
+	spectatorID := shared.SpectatorClientIDPrefix + "1"
+	spectator := &mockClient{id: spectatorID}
+	require.NoError(t, controller.OnConnected(spectator, nil))
+
+	// 観戦者はプレイヤーとして参加しないが、brokerには追加される
+	assert.Len(t, state.GetPlayers(), 1)
+	assert.NotContains(t, state.GetPlayers(), game.PlayerID(spectatorID))
+	assert.Equal(t, broker.clients[spectatorID], spectator)
+
+	// Subscribeすると既存のプレイヤーの状態を受け取る
+	require.NoError(t, controller.OnSubscribed(spectator, nil))
+	require.Len(t, spectator.Published(), 2)
+	assert.Equal(t, "player_state", spectator.Published()[0].TopicName)
+	assert.Equal(t, "match_state", spectator.Published()[1].TopicName)
+
+	// 観戦者からの操作は無視される
+	payload, err := proto.Marshal(&shared.PlayerState{
+		PlayerId: spectatorID,
+		Position: &shared.Position{X: 1, Y: 1},
+	})
+	require.NoError(t, err)
+	require.NoError(t, controller.OnPublished(spectator, &packets.PublishPacket{
+		TopicName: "player_state",
+		Payload:   payload,
+	}))
+	assert.Len(t, state.GetPlayers(), 1)
+
+	// 状態の変化は観戦者にも配信される
+	state.Step()
+	state.MovePlayer("id1", game.Position{X: 6, Y: 10}, game.DirectionRight)
+	publishEvents(controller, state)
+	require.Len(t, spectator.Published(), 3)
+	assert.Equal(t, "player_state", spectator.Published()[2].TopicName)
+
+	// 観戦者が切断しても他のクライアントにDISCONNECTEDは送られない
+	published := len(cl1.Published())
+	require.NoError(t, controller.OnDisconnected(spectator))
+	assert.Len(t, cl1.Published(), published)
+	assert.NotContains(t, broker.clients, spectatorID)
+}
+
 func TestController_OnSubscribed(t *testing.T) {
 	// 自分以外の既存プレイヤー全員に自分の位置を送信する
 
@@ -71,30 +193,37 @@ func TestController_OnSubscribed(t *testing.T) {
 	controller := NewController(broker, state)
 
 	cl1 := &mockClient{id: "id1"}
-	err := controller.OnConnected(cl1, nil)
-	require.NoError(t, err)
+	connectPlayer(t, controller, cl1)
 	state.MovePlayer(game.PlayerID("id1"), game.Position{X: 5, Y: 10}, game.DirectionRight)
 
 	cl2 := &mockClient{id: "id2"}
-	err = controller.OnConnected(cl2, nil)
-	require.NoError(t, err)
+	connectPlayer(t, controller, cl2)
 	state.MovePlayer(game.PlayerID("id2"), game.Position{X: 10, Y: 20}, game.DirectionLeft)
 
 	cl3 := &mockClient{id: "id3"}
-	err = controller.OnConnected(cl3, nil)
-	require.NoError(t, err)
+	connectPlayer(t, controller, cl3)
 
-	err = controller.OnSubscribed(cl3, nil)
+	bulletID := state.AddBullet(game.Position{X: 3, Y: 4}, game.DirectionUp)
+
+	err := controller.OnSubscribed(cl3, nil)
 	require.NoError(t, err)
 
-	require.Len(t, cl3.Published(), 2)
+	require.Len(t, cl3.Published(), 4)
 
-	// Topic名はplayer_state
+	// プレイヤーの状態、アイテムの状態、最後にmatch_stateが送られる
 	assert.Equal(t, "player_state", cl3.Published()[0].TopicName)
 	assert.Equal(t, "player_state", cl3.Published()[1].TopicName)
+	assert.Equal(t, "item_state", cl3.Published()[2].TopicName)
+	assert.Equal(t, "match_state", cl3.Published()[3].TopicName)
+
+	itemState := &shared.ItemState{}
+	err = proto.Unmarshal(cl3.Published()[2].Payload, itemState)
+	require.NoError(t, err)
+	assert.Equal(t, string(bulletID), itemState.GetItemId())
+	assert.Equal(t, shared.ItemStatus_ACTIVE, itemState.GetStatus())
 
 	idToState := map[string]*shared.PlayerState{}
-	for _, published := range cl3.Published() {
+	for _, published := range cl3.Published()[:2] {
 		publishedState := &shared.PlayerState{}
 		err := proto.Unmarshal(published.Payload, publishedState)
 		require.NoError(t, err)
@@ -122,18 +251,19 @@ func TestController_OnPublished_PlayerState(t *testing.T) {
 	controller := NewController(broker, state)
 
 	cl1 := &mockClient{id: "id1"}
-	err := controller.OnConnected(cl1, nil)
-	require.NoError(t, err)
+	connectPlayer(t, controller, cl1)
 
 	cl2 := &mockClient{id: "id2"}
-	err = controller.OnConnected(cl2, nil)
-	require.NoError(t, err)
+	connectPlayer(t, controller, cl2)
 
 	cl3 := &mockClient{id: "id3"}
-	err = controller.OnConnected(cl3, nil)
-	require.NoError(t, err)
+	connectPlayer(t, controller, cl3)
+	state.MovePlayer("id3", game.Position{X: 14, Y: 25}, game.DirectionRight)
+
+	// 接続時のイベントは読み捨てる
+	state.Step()
 
-	// cl3からのplayer_stateを受信する
+	// cl3からの隣のマスへのplayer_stateを受信する
 	{
 		payload, err := proto.Marshal(&shared.PlayerState{
 			PlayerId:  "id3",
@@ -150,6 +280,7 @@ func TestController_OnPublished_PlayerState(t *testing.T) {
 		err = controller.OnPublished(cl3, packet)
 		require.NoError(t, err)
 	}
+	publishEvents(controller, state)
 
 	// cl3の位置が更新されている
 	assert.EqualValues(t, 15, state.GetPlayers()[game.PlayerID("id3")].Position().X)
@@ -169,14 +300,57 @@ func TestController_OnPublished_PlayerState(t *testing.T) {
 	}
 }
 
+func TestController_OnPublished_PlayerState_MoveTicks(t *testing.T) {
+	broker := NewBroker()
+	state := game.NewGameWithConfig(game.Config{
+		Width:    30,
+		Height:   30,
+		Movement: game.MovementConfig{MoveTicks: 10},
+	})
+	controller := NewController(broker, state)
+
+	cl1 := &mockClient{id: "id1"}
+	connectPlayer(t, controller, cl1)
+	state.MovePlayer("id1", game.Position{X: 5, Y: 5}, game.DirectionRight)
+	state.Step()
+
+	publish := func(x, y int32) {
+		payload, err := proto.Marshal(&shared.PlayerState{
+			PlayerId:  "id1",
+			Position:  &shared.Position{X: x, Y: y},
+			Direction: shared.Direction_RIGHT,
+		})
+		require.NoError(t, err)
+		err = controller.OnPublished(cl1, &packets.PublishPacket{TopicName: "player_state", Payload: payload})
+		require.NoError(t, err)
+	}
+
+	// 移動できたらゲームループから送信される
+	publish(6, 5)
+	require.Empty(t, cl1.Published())
+	publishEvents(controller, state)
+	require.Len(t, cl1.Published(), 1)
+
+	// 移動間隔が空くまで保留された場合は送信されない
+	publish(7, 5)
+	require.Len(t, cl1.Published(), 1)
+
+	// 隣でないマスへの移動は受け付けられず、現在の位置が送信される
+	publish(20, 20)
+	require.Len(t, cl1.Published(), 2)
+	publishedState := &shared.PlayerState{}
+	err := proto.Unmarshal(cl1.Published()[1].Payload, publishedState)
+	require.NoError(t, err)
+	assert.EqualValues(t, 6, publishedState.GetPosition().GetX())
+}
+
 func TestController_OnPublished_PlayerAction_ShootBullet(t *testing.T) {
 	broker := NewBroker()
 	state := game.NewGame(30, 30)
 	controller := NewController(broker, state)
 
 	cl1 := &mockClient{id: "id1"}
-	err := controller.OnConnected(cl1, nil)
-	require.NoError(t, err)
+	connectPlayer(t, controller, cl1)
 
 	// cl1の位置を更新する
 	state.MovePlayer(game.PlayerID("id1"), game.Position{X: 5, Y: 10}, game.DirectionRight)
@@ -205,14 +379,41 @@ func TestController_OnPublished_PlayerAction_ShootBullet(t *testing.T) {
 	assert.Equal(t, game.Position{X: 6, Y: 10}, bullet.Position())
 }
 
+func TestController_OnPublished_PlayerAction_SelectWeapon(t *testing.T) {
+	broker := NewBroker()
+	state := game.NewGame(30, 30)
+	controller := NewController(broker, state)
+
+	cl1 := &mockClient{id: "id1"}
+	connectPlayer(t, controller, cl1)
+
+	// cl1からのplayer_action SelectWeaponを受信する
+	{
+		payload, err := proto.Marshal(&shared.PlayerActionRequest{
+			Type:   shared.ActionType_SELECT_WEAPON,
+			Weapon: shared.WeaponType_SHOTGUN,
+		})
+		require.NoError(t, err)
+
+		err = controller.OnPublished(cl1, &packets.PublishPacket{
+			TopicName: "player_action",
+			Payload:   payload,
+		})
+		require.NoError(t, err)
+	}
+
+	player := state.GetPlayers()[game.PlayerID("id1")]
+	assert.Equal(t, game.WeaponTypeShotgun, player.WeaponType())
+	assert.Equal(t, shared.WeaponType_SHOTGUN, player.ToSharedPlayerState().GetWeapon().GetType())
+}
+
 func TestController_OnPublished_PlayerAction_PlaceBomb(t *testing.T) {
 	broker := NewBroker()
 	state := game.NewGame(30, 30)
 	controller := NewController(broker, state)
 
 	cl1 := &mockClient{id: "id1"}
-	err := controller.OnConnected(cl1, nil)
-	require.NoError(t, err)
+	connectPlayer(t, controller, cl1)
 
 	// cl1の位置を更新する
 	state.MovePlayer(game.PlayerID("id1"), game.Position{X: 5, Y: 10}, game.DirectionRight)
@@ -241,6 +442,277 @@ func TestController_OnPublished_PlayerAction_PlaceBomb(t *testing.T) {
 	assert.Equal(t, game.Position{X: 5, Y: 10}, bomb.Position())
 }
 
+func TestController_OnPublished_PlayerAction_SwitchTeam(t *testing.T) {
+	broker := NewBroker()
+	state := game.NewGameWithConfig(game.Config{
+		Width:  30,
+		Height: 30,
+		Match:  game.MatchConfig{MinPlayers: 10},
+		Mode:   &game.TeamDeathmatch{},
+	})
+	controller := NewController(broker, state)
+
+	for _, id := range []string{"id1", "id2"} {
+		connectPlayer(t, controller, &mockClient{id: id})
+	}
+	cl3 := &mockClient{id: "id3"}
+	connectPlayer(t, controller, cl3)
+	assert.Equal(t, game.TeamRed, state.GetPlayers()[game.PlayerID("id3")].Team())
+	state.Step()
+
+	// cl3からのplayer_action SwitchTeamを受信する
+	{
+		payload, err := proto.Marshal(&shared.PlayerActionRequest{
+			Type: shared.ActionType_SWITCH_TEAM,
+			Team: shared.Team_BLUE,
+		})
+		require.NoError(t, err)
+
+		err = controller.OnPublished(cl3, &packets.PublishPacket{
+			TopicName: "player_action",
+			Payload:   payload,
+		})
+		require.NoError(t, err)
+	}
+
+	publishEvents(controller, state)
+
+	// cl3のチームが変わり、全員に通知されている
+	assert.Equal(t, game.TeamBlue, state.GetPlayers()[game.PlayerID("id3")].Team())
+	require.Len(t, cl3.Published(), 1)
+	publishedState := &shared.PlayerState{}
+	err := proto.Unmarshal(cl3.Published()[0].Payload, publishedState)
+	require.NoError(t, err)
+	assert.Equal(t, shared.Team_BLUE, publishedState.GetTeam())
+}
+
+func TestController_OnPublished_Join(t *testing.T) {
+	broker := NewBroker()
+	state := game.NewGameWithConfig(game.Config{
+		Width:  30,
+		Height: 30,
+		Match:  game.MatchConfig{MinPlayers: 10},
+		Mode:   &game.TeamDeathmatch{},
+	})
+	controller := NewController(broker, state)
+
+	cl1 := &mockClient{id: "id1"}
+	connectPlayer(t, controller, cl1)
+	connectPlayer(t, controller, &mockClient{id: "id2"})
+	// id3はまだ参加していない
+	cl3 := &mockClient{id: "id3"}
+	require.NoError(t, controller.OnConnected(cl3, nil))
+	state.Step()
+
+	// joinを送信し、返ってきたjoin_responseを取り出す
+	join := func(t *testing.T, client *mockClient, joinRequest *shared.JoinRequest) *shared.JoinResponse {
+		t.Helper()
+		payload, err := proto.Marshal(joinRequest)
+		require.NoError(t, err)
+		err = controller.OnPublished(client, &packets.PublishPacket{
+			TopicName: "join",
+			Payload:   payload,
+		})
+		require.NoError(t, err)
+
+		published := client.Published()
+		require.NotEmpty(t, published)
+		last := published[len(published)-1]
+		require.Equal(t, "join_response", last.TopicName)
+		joinResponse := &shared.JoinResponse{}
+		require.NoError(t, proto.Unmarshal(last.Payload, joinResponse))
+		return joinResponse
+	}
+
+	t.Run("表示名と希望するチームが反映され、全員に通知される", func(t *testing.T) {
+		joinResponse := join(t, cl3, &shared.JoinRequest{
+			DisplayName:   " Alice ",
+			PreferredTeam: shared.Team_BLUE,
+			ClientVersion: shared.ProtocolVersion,
+		})
+		assert.Equal(t, shared.JoinResult_JOIN_ACCEPTED, joinResponse.GetResult())
+		assert.Equal(t, "id3", joinResponse.GetPlayerId())
+		assert.Equal(t, "Alice", joinResponse.GetDisplayName())
+
+		player := state.GetPlayers()["id3"]
+		assert.Equal(t, "Alice", player.DisplayName())
+		assert.Equal(t, game.TeamBlue, player.Team())
+
+		publishEvents(controller, state)
+		published := cl1.Published()
+		require.NotEmpty(t, published)
+		playerState := &shared.PlayerState{}
+		require.NoError(t, proto.Unmarshal(published[len(published)-1].Payload, playerState))
+		assert.Equal(t, "id3", playerState.GetPlayerId())
+		assert.Equal(t, "Alice", playerState.GetDisplayName())
+		assert.Equal(t, shared.Team_BLUE, playerState.GetTeam())
+	})
+
+	t.Run("使われている表示名では参加できない", func(t *testing.T) {
+		joinResponse := join(t, cl1, &shared.JoinRequest{
+			DisplayName:   "alice",
+			ClientVersion: shared.ProtocolVersion,
+		})
+		assert.Equal(t, shared.JoinResult_JOIN_NAME_TAKEN, joinResponse.GetResult())
+		assert.Equal(t, "id1", state.GetPlayers()["id1"].DisplayName())
+	})
+
+	t.Run("長すぎる表示名では参加できない", func(t *testing.T) {
+		joinResponse := join(t, cl1, &shared.JoinRequest{
+			DisplayName:   "abcdefghijklmnopqrstuvwxyz",
+			ClientVersion: shared.ProtocolVersion,
+		})
+		assert.Equal(t, shared.JoinResult_JOIN_NAME_INVALID, joinResponse.GetResult())
+	})
+
+	t.Run("バージョンが違うクライアントは参加できない", func(t *testing.T) {
+		joinResponse := join(t, cl1, &shared.JoinRequest{
+			DisplayName:   "bob",
+			ClientVersion: "0",
+		})
+		assert.Equal(t, shared.JoinResult_JOIN_VERSION_MISMATCH, joinResponse.GetResult())
+		assert.Equal(t, "id1", state.GetPlayers()["id1"].DisplayName())
+	})
+
+	t.Run("参加を受け付けなかったクライアントはプレイヤーにならない", func(t *testing.T) {
+		cl4 := &mockClient{id: "id4"}
+		require.NoError(t, controller.OnConnected(cl4, nil))
+
+		for _, joinRequest := range []*shared.JoinRequest{
+			{DisplayName: "carol", ClientVersion: "0"},
+			{DisplayName: "", ClientVersion: shared.ProtocolVersion},
+			{DisplayName: "ALICE", ClientVersion: shared.ProtocolVersion},
+		} {
+			joinResponse := join(t, cl4, joinRequest)
+			assert.NotEqual(t, shared.JoinResult_JOIN_ACCEPTED, joinResponse.GetResult())
+			assert.NotContains(t, state.GetPlayers(), game.PlayerID("id4"))
+		}
+
+		joinResponse := join(t, cl4, &shared.JoinRequest{DisplayName: "carol", ClientVersion: shared.ProtocolVersion})
+		assert.Equal(t, shared.JoinResult_JOIN_ACCEPTED, joinResponse.GetResult())
+		assert.Equal(t, "carol", state.GetPlayers()["id4"].DisplayName())
+	})
+}
+
+func TestController_OnPublished_Chat(t *testing.T) {
+	broker := NewBroker()
+	state := game.NewGameWithConfig(game.Config{
+		Width:  30,
+		Height: 30,
+		Mode:   &game.TeamDeathmatch{},
+	})
+	controller := NewController(broker, state)
+
+	// id1とid3が赤チーム、id2が青チーム
+	clients := []*mockClient{{id: "id1", host: "192.0.2.1"}, {id: "id2", host: "192.0.2.2"}, {id: "id3", host: "192.0.2.3"}}
+	for _, cl := range clients {
+		connectPlayer(t, controller, cl)
+	}
+	_, err := state.SetDisplayName("id1", "alice")
+	require.NoError(t, err)
+	state.Step()
+
+	sendChat := func(t *testing.T, client *mockClient, chatMessage *shared.ChatMessage) {
+		t.Helper()
+		payload, err := proto.Marshal(chatMessage)
+		require.NoError(t, err)
+		err = controller.OnPublished(client, &packets.PublishPacket{
+			TopicName: "chat",
+			Payload:   payload,
+		})
+		require.NoError(t, err)
+	}
+	// 各クライアントに届いたチャットを取り出し、届いた記録を消す
+	receivedChats := func(t *testing.T, client *mockClient) []*shared.ChatMessage {
+		t.Helper()
+		client.mu.Lock()
+		defer client.mu.Unlock()
+		var chats []*shared.ChatMessage
+		for _, packet := range client.published {
+			if packet.TopicName != "chat" {
+				continue
+			}
+			chatMessage := &shared.ChatMessage{}
+			require.NoError(t, proto.Unmarshal(packet.Payload, chatMessage))
+			chats = append(chats, chatMessage)
+		}
+		client.published = nil
+		return chats
+	}
+
+	t.Run("全員宛てのチャットは送信者を埋めて全員に届く", func(t *testing.T) {
+		sendChat(t, clients[0], &shared.ChatMessage{
+			SenderId: "id2",
+			Text:     " hello ",
+			Scope:    shared.ChatScope_CHAT_SCOPE_ALL,
+		})
+
+		for _, cl := range clients {
+			chats := receivedChats(t, cl)
+			require.Len(t, chats, 1)
+			assert.Equal(t, "id1", chats[0].GetSenderId())
+			assert.Equal(t, "alice", chats[0].GetSenderName())
+			assert.Equal(t, "hello", chats[0].GetText())
+			assert.False(t, chats[0].GetSystem())
+		}
+	})
+
+	t.Run("チーム宛てのチャットは同じチームにのみ届く", func(t *testing.T) {
+		sendChat(t, clients[0], &shared.ChatMessage{
+			Text:  "go",
+			Scope: shared.ChatScope_CHAT_SCOPE_TEAM,
+		})
+
+		assert.Len(t, receivedChats(t, clients[0]), 1)
+		assert.Empty(t, receivedChats(t, clients[1]))
+		chats := receivedChats(t, clients[2])
+		require.Len(t, chats, 1)
+		assert.Equal(t, shared.ChatScope_CHAT_SCOPE_TEAM, chats[0].GetScope())
+	})
+
+	t.Run("ミュートされたプレイヤーのチャットは届かず、本人に理由が届く", func(t *testing.T) {
+		require.NoError(t, controller.ChatModerator().Mute("id2", time.Now().Add(time.Hour)))
+		sendChat(t, clients[1], &shared.ChatMessage{Text: "spam"})
+
+		assert.Empty(t, receivedChats(t, clients[0]))
+		chats := receivedChats(t, clients[1])
+		require.Len(t, chats, 1)
+		assert.True(t, chats[0].GetSystem())
+		assert.Equal(t, "You are muted", chats[0].GetText())
+	})
+
+	t.Run("別のクライアントIDで接続し直してもミュートは続く", func(t *testing.T) {
+		require.NoError(t, controller.OnDisconnected(clients[1]))
+		reconnected := &mockClient{id: "id4", host: "192.0.2.2"}
+		connectPlayer(t, controller, reconnected)
+		receivedChats(t, clients[0])
+
+		sendChat(t, reconnected, &shared.ChatMessage{Text: "spam"})
+
+		assert.Empty(t, receivedChats(t, clients[0]))
+		chats := receivedChats(t, reconnected)
+		require.Len(t, chats, 1)
+		assert.Equal(t, "You are muted", chats[0].GetText())
+	})
+}
+
+// disconnectedIDs クライアントに切断したとして届いたプレイヤーのID
+func disconnectedIDs(t *testing.T, cl *mockClient) []string {
+	t.Helper()
+	var ids []string
+	for _, published := range cl.Published() {
+		if published.TopicName != "player_state" {
+			continue
+		}
+		state := &shared.PlayerState{}
+		require.NoError(t, proto.Unmarshal(published.Payload, state))
+		if state.GetStatus() == shared.Status_DISCONNECTED {
+			ids = append(ids, state.GetPlayerId())
+		}
+	}
+	return ids
+}
+
 func TestController_OnDisconnected(t *testing.T) {
 	// 切断したら、そのプレイヤーを削除し、そのプレイヤーが切断したことを全員に送信する
 
@@ -249,39 +721,51 @@ func TestController_OnDisconnected(t *testing.T) {
 	controller := NewController(broker, state)
 
 	cl1 := &mockClient{id: "id1"}
-	err := controller.OnConnected(cl1, nil)
-	require.NoError(t, err)
+	connectPlayer(t, controller, cl1)
 
 	cl2 := &mockClient{id: "id2"}
-	err = controller.OnConnected(cl2, nil)
-	require.NoError(t, err)
+	connectPlayer(t, controller, cl2)
 
 	cl3 := &mockClient{id: "id3"}
-	broker.AddClient(cl3)
-	err = controller.OnConnected(cl3, nil)
-	require.NoError(t, err)
+	connectPlayer(t, controller, cl3)
 
-	err = controller.OnDisconnected(cl1)
+	err := controller.OnDisconnected(cl1)
 	require.NoError(t, err)
 
 	assert.NotContains(t, state.GetPlayers(), game.PlayerID("id1"))
 	assert.Contains(t, state.GetPlayers(), game.PlayerID("id2"))
 	assert.Contains(t, state.GetPlayers(), game.PlayerID("id3"))
 
-	// cl1の切断がcl2, cl3に送信されている
+	// cl1の切断がゲームループからcl2, cl3に送信される
+	publishEvents(controller, state)
 	for _, cl := range []*mockClient{cl2, cl3} {
-		require.Len(t, cl.Published(), 1)
-		assert.Equal(t, "player_state", cl.Published()[0].TopicName)
-		publishedState := &shared.PlayerState{}
-		err := proto.Unmarshal(cl.Published()[0].Payload, publishedState)
-		require.NoError(t, err)
-		assert.Equal(t, shared.Status_DISCONNECTED, publishedState.GetStatus())
+		assert.Equal(t, []string{"id1"}, disconnectedIDs(t, cl))
 	}
 
 	// cl1がbrokerから削除されている
 	assert.NotContains(t, broker.clients, cl1.id)
 }
 
+func TestController_RemovedBot(t *testing.T) {
+	// ボットが抜けた時も、切断したことを全員に送信する
+	broker := NewBroker()
+	state := game.NewGame(30, 30)
+	controller := NewController(broker, state)
+	manager := bot.NewManager(state, bot.Config{Count: 0, FillTo: 2, Difficulty: bot.DifficultyNormal, Seed: 1})
+	manager.Start()
+
+	cl1 := &mockClient{id: "id1"}
+	connectPlayer(t, controller, cl1)
+	publishEvents(controller, state)
+	require.Equal(t, []game.PlayerID{"bot-1"}, manager.Bots())
+	assert.Empty(t, disconnectedIDs(t, cl1))
+
+	connectPlayer(t, controller, &mockClient{id: "id2"})
+	publishEvents(controller, state)
+	require.Empty(t, manager.Bots())
+	assert.Equal(t, []string{"bot-1"}, disconnectedIDs(t, cl1))
+}
+
 func TestController_StartPublishLoop(t *testing.T) {
 	t.Run("アクティブなアイテムの情報を送れる", func(t *testing.T) {
 		broker := NewBroker()
@@ -289,20 +773,21 @@ func TestController_StartPublishLoop(t *testing.T) {
 		controller := NewController(broker, state)
 
 		cl1 := &mockClient{id: "id1"}
-		err := controller.OnConnected(cl1, nil)
-		require.NoError(t, err)
+		connectPlayer(t, controller, cl1)
 
 		cl2 := &mockClient{id: "id2"}
-		err = controller.OnConnected(cl2, nil)
-		require.NoError(t, err)
+		connectPlayer(t, controller, cl2)
 
-		updatedCh := make(chan game.UpdatedResult)
-		controller.StartPublishLoop(context.Background(), updatedCh)
+		eventCh := make(chan game.Event)
+		controller.StartPublishLoop(context.Background(), eventCh)
+		state.Step()
 
 		bulletID1 := state.AddBullet(game.Position{X: 1, Y: 2}, game.DirectionRight)
 		bulletID2 := state.AddBullet(game.Position{X: 2, Y: 3}, game.DirectionUp)
 
-		updatedCh <- game.UpdatedResult{Type: game.UpdatedResultTypeItemsUpdated}
+		for _, event := range state.Step() {
+			eventCh <- event
+		}
 
 		// TODO: 待つための良い手法があれば変更
 		time.Sleep(10 * time.Millisecond)
@@ -337,17 +822,19 @@ func TestController_StartPublishLoop(t *testing.T) {
 		controller := NewController(broker, state)
 
 		client := &mockClient{id: "id1"}
-		err := controller.OnConnected(client, nil)
-		require.NoError(t, err)
+		connectPlayer(t, controller, client)
+		state.Step()
 
 		bulletID1 := state.AddBullet(game.Position{X: 1, Y: 2}, game.DirectionRight)
 		bulletID2 := state.AddBullet(game.Position{X: 2, Y: 3}, game.DirectionUp)
 		state.RemoveItem(bulletID1)
 
-		updatedCh := make(chan game.UpdatedResult)
-		controller.StartPublishLoop(context.Background(), updatedCh)
+		eventCh := make(chan game.Event)
+		controller.StartPublishLoop(context.Background(), eventCh)
 
-		updatedCh <- game.UpdatedResult{Type: game.UpdatedResultTypeItemsUpdated}
+		for _, event := range state.Step() {
+			eventCh <- event
+		}
 
 		// TODO: 待つための良い手法があれば変更
 		time.Sleep(10 * time.Millisecond)
@@ -367,9 +854,6 @@ func TestController_StartPublishLoop(t *testing.T) {
 		assert.EqualValues(t, 2, idToState[bulletID2].GetPosition().GetX())
 		assert.EqualValues(t, 3, idToState[bulletID2].GetPosition().GetY())
 		assert.Equal(t, shared.ItemType_BULLET, idToState[bulletID2].GetType())
-
-		// 削除送信が成功したので、stateから削除済みアイテムがクリアされている
-		assert.NotContains(t, state.GetRemovedItems(), bulletID1)
 	})
 
 	t.Run("プレイヤーの更新を送信できる", func(t *testing.T) {
@@ -378,19 +862,21 @@ func TestController_StartPublishLoop(t *testing.T) {
 		controller := NewController(broker, state)
 
 		cl1 := &mockClient{id: "id1"}
-		err := controller.OnConnected(cl1, nil)
-		require.NoError(t, err)
-		state.MovePlayer(game.PlayerID("id1"), game.Position{X: 5, Y: 10}, game.DirectionRight)
+		connectPlayer(t, controller, cl1)
 
 		cl2 := &mockClient{id: "id2"}
-		err = controller.OnConnected(cl2, nil)
-		require.NoError(t, err)
+		connectPlayer(t, controller, cl2)
+		state.Step()
+
+		state.MovePlayer(game.PlayerID("id1"), game.Position{X: 5, Y: 10}, game.DirectionRight)
 		state.MovePlayer(game.PlayerID("id2"), game.Position{X: 10, Y: 20}, game.DirectionLeft)
 
-		updatedCh := make(chan game.UpdatedResult)
-		controller.StartPublishLoop(context.Background(), updatedCh)
+		eventCh := make(chan game.Event)
+		controller.StartPublishLoop(context.Background(), eventCh)
 
-		updatedCh <- game.UpdatedResult{Type: game.UpdatedResultTypePlayersUpdated}
+		for _, event := range state.Step() {
+			eventCh <- event
+		}
 
 		// TODO: 待つための良い手法があれば変更
 		time.Sleep(10 * time.Millisecond)
@@ -417,3 +903,84 @@ func TestController_StartPublishLoop(t *testing.T) {
 		}
 	})
 }
+
+func TestController_StartPublishLoop_MatchState(t *testing.T) {
+	broker := NewBroker()
+	state := game.NewGame(30, 30)
+	controller := NewController(broker, state)
+
+	cl1 := &mockClient{id: "id1"}
+	connectPlayer(t, controller, cl1)
+
+	eventCh := make(chan game.Event)
+	controller.StartPublishLoop(context.Background(), eventCh)
+
+	eventCh <- game.MatchUpdated{Match: state.Match()}
+
+	// TODO: 待つための良い手法があれば変更
+	time.Sleep(10 * time.Millisecond)
+
+	require.Len(t, cl1.Published(), 1)
+	assert.Equal(t, "match_state", cl1.Published()[0].TopicName)
+	matchState := &shared.MatchState{}
+	err := proto.Unmarshal(cl1.Published()[0].Payload, matchState)
+	require.NoError(t, err)
+	assert.Equal(t, shared.MatchPhase_RUNNING, matchState.GetPhase())
+}
+
+// gameEvents publishされたgame_eventトピックのメッセージを取り出す
+func gameEvents(t *testing.T, client *mockClient) []*shared.GameEvent {
+	t.Helper()
+	var events []*shared.GameEvent
+	for _, packet := range client.Published() {
+		if packet.TopicName != "game_event" {
+			continue
+		}
+		event := &shared.GameEvent{}
+		err := proto.Unmarshal(packet.Payload, event)
+		require.NoError(t, err)
+		events = append(events, event)
+	}
+	return events
+}
+
+func TestController_publishEvent_GameEvent(t *testing.T) {
+	broker := NewBroker()
+	state := game.NewGame(30, 30)
+	controller := NewController(broker, state)
+
+	cl1 := &mockClient{id: "id1"}
+	connectPlayer(t, controller, cl1)
+	cl2 := &mockClient{id: "id2"}
+	connectPlayer(t, controller, cl2)
+
+	publishEvents(controller, state)
+
+	t.Run("プレイヤーの参加が通知される", func(t *testing.T) {
+		events := gameEvents(t, cl1)
+		require.Len(t, events, 2)
+		assert.Equal(t, "id1", events[0].GetPlayerJoined().GetPlayerId())
+		assert.Equal(t, "id2", events[1].GetPlayerJoined().GetPlayerId())
+	})
+
+	t.Run("誰が誰をどの武器で倒したかが通知される", func(t *testing.T) {
+		state.MovePlayer("id1", game.Position{X: 2, Y: 3}, game.DirectionRight)
+		state.MovePlayer("id2", game.Position{X: 3, Y: 3}, game.DirectionLeft)
+		for range game.MaxHP / game.BulletDamage {
+			state.ShootBullet("id1")
+			publishEvents(controller, state)
+		}
+
+		var killed []*shared.PlayerKilled
+		for _, event := range gameEvents(t, cl2) {
+			if event.GetPlayerKilled() != nil {
+				killed = append(killed, event.GetPlayerKilled())
+			}
+		}
+		require.Len(t, killed, 1)
+		assert.Equal(t, "id2", killed[0].GetVictimId())
+		assert.Equal(t, "id1", killed[0].GetKillerId())
+		assert.Equal(t, shared.ItemType_BULLET, killed[0].GetCause())
+		assert.Equal(t, shared.WeaponType_PISTOL, killed[0].GetWeapon())
+	})
+}
@@ -111,6 +111,21 @@ func (c *TestClient) PublishPlayerState(position *shared.Position, direction sha
 	return token.Error()
 }
 
+// MustJoin 表示名を指定してゲームに参加する
+func (c *TestClient) MustJoin(t *testing.T, displayName string) {
+	t.Helper()
+	payload, err := proto.Marshal(&shared.JoinRequest{
+		DisplayName:   displayName,
+		PreferredTeam: shared.Team_NO_TEAM,
+		ClientVersion: shared.ProtocolVersion,
+	})
+	require.NoError(t, err)
+
+	token := c.client.Publish("join", 0, false, payload)
+	token.Wait()
+	require.NoError(t, token.Error())
+}
+
 func (c *TestClient) PublishPlayerAction(actionType shared.ActionType) error {
 	req := &shared.PlayerActionRequest{
 		Type: actionType,
@@ -142,6 +157,8 @@ func TestE2E(t *testing.T) {
 	opts := &runOptions{
 		MQTTPort:    "11883",
 		MetricsPort: "12113",
+		Width:       30,
+		Height:      30,
 	}
 
 	t.Run("クライアントが接続でき、サーバーを終了できる", func(t *testing.T) {
@@ -189,11 +206,13 @@ func TestE2E(t *testing.T) {
 
 		client1 := NewTestClient(t, "localhost:"+opts.MQTTPort, "player1")
 		client2 := NewTestClient(t, "localhost:"+opts.MQTTPort, "player2")
+		client1.MustJoin(t, "player1")
+		client2.MustJoin(t, "player2")
 
-		// client1がプレイヤーの位置を更新すると、client2が受信できる
+		// client1がプレイヤーの位置を隣のマスに更新すると、client2が受信できる
 		{
 			err := client1.PublishPlayerState(
-				&shared.Position{X: 10, Y: 20},
+				&shared.Position{X: 1, Y: 0},
 				shared.Direction_RIGHT,
 			)
 			require.NoError(t, err)
@@ -201,25 +220,25 @@ func TestE2E(t *testing.T) {
 			// client2が受信したメッセージを確認
 			time.Sleep(100 * time.Millisecond)
 			receivedState := client2.MustFindLastPlayerStateMessage(t, "player1")
-			assert.Equal(t, int32(10), receivedState.GetPosition().GetX())
-			assert.Equal(t, int32(20), receivedState.GetPosition().GetY())
+			assert.Equal(t, int32(1), receivedState.GetPosition().GetX())
+			assert.Equal(t, int32(0), receivedState.GetPosition().GetY())
 			assert.Equal(t, shared.Direction_RIGHT, receivedState.GetDirection())
 		}
 
 		// client2がプレイヤーの位置を更新すると、client1が受信できる
 		{
 			err := client2.PublishPlayerState(
-				&shared.Position{X: 2, Y: 1},
-				shared.Direction_UP,
+				&shared.Position{X: 0, Y: 1},
+				shared.Direction_DOWN,
 			)
 			require.NoError(t, err)
 
 			// client1が受信したメッセージを確認
 			time.Sleep(100 * time.Millisecond)
 			receivedState := client1.MustFindLastPlayerStateMessage(t, "player2")
-			assert.Equal(t, int32(2), receivedState.GetPosition().GetX())
+			assert.Equal(t, int32(0), receivedState.GetPosition().GetX())
 			assert.Equal(t, int32(1), receivedState.GetPosition().GetY())
-			assert.Equal(t, shared.Direction_UP, receivedState.GetDirection())
+			assert.Equal(t, shared.Direction_DOWN, receivedState.GetDirection())
 		}
 	})
 
@@ -243,10 +262,12 @@ func TestE2E(t *testing.T) {
 
 		client1 := NewTestClient(t, "localhost:"+opts.MQTTPort, "shoot-player1")
 		client2 := NewTestClient(t, "localhost:"+opts.MQTTPort, "shoot-player2")
+		client1.MustJoin(t, "shooter1")
+		client2.MustJoin(t, "shooter2")
 
-		// client1が右向きで位置を設定
+		// client1が右隣のマスに移動する
 		err := client1.PublishPlayerState(
-			&shared.Position{X: 10, Y: 20},
+			&shared.Position{X: 1, Y: 0},
 			shared.Direction_RIGHT,
 		)
 		require.NoError(t, err)
@@ -263,8 +284,8 @@ func TestE2E(t *testing.T) {
 			require.Len(t, itemMessages, 1)
 			assert.Equal(t, shared.ItemType_BULLET, itemMessages[0].GetType())
 			assert.Equal(t, shared.ItemStatus_ACTIVE, itemMessages[0].GetStatus())
-			assert.Equal(t, int32(11), itemMessages[0].GetPosition().GetX(), "右向きに発射された")
-			assert.Equal(t, int32(20), itemMessages[0].GetPosition().GetY())
+			assert.Equal(t, int32(2), itemMessages[0].GetPosition().GetX(), "右向きに発射された")
+			assert.Equal(t, int32(0), itemMessages[0].GetPosition().GetY())
 		}
 
 		// さらに1マス進むのを待ち、受け取れることを確認
@@ -272,8 +293,54 @@ func TestE2E(t *testing.T) {
 		for _, client := range []*TestClient{client1, client2} {
 			itemMessages := client.MustFindItemStateMessages(t)
 			require.Len(t, itemMessages, 2)
-			assert.Equal(t, int32(12), itemMessages[1].GetPosition().GetX(), "さらに1マス進んた値")
-			assert.Equal(t, int32(20), itemMessages[1].GetPosition().GetY())
+			assert.Equal(t, int32(3), itemMessages[1].GetPosition().GetX(), "さらに1マス進んた値")
+			assert.Equal(t, int32(0), itemMessages[1].GetPosition().GetY())
+		}
+	})
+
+	t.Run("接続中のクライアントと同じIDやボットのIDでは接続できない", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		// サーバー起動
+		errCh := make(chan error)
+		go func() {
+			errCh <- run(ctx, opts)
+		}()
+		t.Cleanup(func() {
+			cancel()
+			err := <-errCh
+			require.NoError(t, err)
+		})
+
+		// サーバーの起動を待つ
+		time.Sleep(100 * time.Millisecond)
+
+		client1 := NewTestClient(t, "localhost:"+opts.MQTTPort, "duplicate-player1")
+		client2 := NewTestClient(t, "localhost:"+opts.MQTTPort, "duplicate-player2")
+		client1.MustJoin(t, "duplicate1")
+		client2.MustJoin(t, "duplicate2")
+
+		for _, clientID := range []string{"duplicate-player1", "bot-1"} {
+			duplicate := mqtt.NewClient(mqtt.NewClientOptions().
+				AddBroker("tcp://localhost:" + opts.MQTTPort).
+				SetClientID(clientID))
+			token := duplicate.Connect()
+			token.Wait()
+			require.Error(t, token.Error(), clientID)
 		}
+
+		// 拒否された接続が切れても、先に接続したプレイヤーは残っている
+		time.Sleep(100 * time.Millisecond)
+		err := client1.PublishPlayerState(
+			&shared.Position{X: 1, Y: 0},
+			shared.Direction_RIGHT,
+		)
+		require.NoError(t, err)
+
+		time.Sleep(100 * time.Millisecond)
+		receivedState := client2.MustFindLastPlayerStateMessage(t, "duplicate-player1")
+		assert.Equal(t, shared.Status_ALIVE, receivedState.GetStatus())
+		assert.Equal(t, int32(1), receivedState.GetPosition().GetX())
 	})
 }
@@ -0,0 +1,85 @@
+package game
+
+import "fmt"
+
+// ArenaConfig 盤面に配置する障害物の設定
+// ゼロ値の場合は障害物を置かない
+type ArenaConfig struct {
+	// 壁を置く間隔。2なら縦横1マスおきに柱状の壁を置く。0なら壁を置かない
+	PillarInterval int
+	// 空いているマスにブロックを置く確率
+	BlockDensity float64
+	// ブロックを壊した時にパワーアップが出現する確率
+	PowerUpChance float64
+}
+
+// 壁を配置する。壁はゲームの間ずっと残る
+func (g *Game) placeWallsWithoutLock() {
+	interval := g.arena.PillarInterval
+	if interval <= 0 {
+		return
+	}
+	for x := interval - 1; x < g.Width; x += interval {
+		for y := interval - 1; y < g.Height; y += interval {
+			g.addItemWithoutLock(NewWall(ItemID(fmt.Sprintf("wall-%d-%d", x, y)), Position{X: x, Y: y}))
+		}
+	}
+}
+
+// 空いているマスにランダムにブロックを配置する
+// プレイヤーが閉じ込められないように、プレイヤーの位置とその上下左右には置かない
+func (g *Game) placeBlocksWithoutLock() {
+	if g.arena.BlockDensity <= 0 {
+		return
+	}
+
+	reserved := make(map[Position]bool)
+	for _, player := range g.Players {
+		pos := player.Position()
+		reserved[pos] = true
+		for _, direction := range []Direction{DirectionUp, DirectionDown, DirectionLeft, DirectionRight} {
+			dx, dy := direction.ToVector()
+			reserved[Position{X: pos.X + dx, Y: pos.Y + dy}] = true
+		}
+	}
+	for x := range g.Width {
+		for y := range g.Height {
+			pos := Position{X: x, Y: y}
+			if reserved[pos] || g.itemIndex.has(pos) || g.random.float64() >= g.arena.BlockDensity {
+				continue
+			}
+			g.addItemWithoutLock(NewBlock(g.random.newItemID(), pos))
+		}
+	}
+}
+
+// 指定位置にある障害物を取得する。なければnilを返す
+func (g *Game) obstacleAt(position Position) obstacle {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.obstacleAtWithoutLock(position)
+}
+
+func (g *Game) obstacleAtWithoutLock(position Position) obstacle {
+	for _, item := range g.itemIndex.at(position) {
+		if ob, ok := item.(obstacle); ok {
+			return ob
+		}
+	}
+	return nil
+}
+
+// 障害物を壊す。壊せない障害物の場合は何もしない
+// ブロックを壊した場合は一定確率でその位置にパワーアップが出現する
+func (g *Game) destroyObstacle(ob obstacle) {
+	if !ob.Destructible() {
+		return
+	}
+	g.RemoveItem(ob.ID())
+
+	if g.random.float64() >= g.arena.PowerUpChance {
+		return
+	}
+	itemType := PowerUpTypes[g.random.intn(len(PowerUpTypes))]
+	g.addItem(g.newPowerUp(itemType, ob.Position()))
+}
@@ -2,20 +2,30 @@ package game
 
 import (
 	"sync"
-
-	"github.com/google/uuid"
+	"time"
 )
 
 const (
-	BombExplosionTick = 180 // 3秒後に爆発
-	BombFireDuration  = 60  // 1秒で消滅
-	BombFireRange     = 4   // 爆発の範囲
+	BombExplosionDelay = 3 * time.Second // 3秒後に爆発
+	BombFireDuration   = time.Second     // 1秒で消滅
+	BombFireRange      = 4               // 爆発の範囲
+	BombFireDamage     = 100             // 爆発の火に触れた時のダメージ
 )
 
 // Bomb ボムを表す
 type Bomb struct {
 	id       ItemID
 	position Position
+	// 設置したプレイヤー
+	ownerID PlayerID
+	// 爆発の範囲
+	fireRange int
+	// 設置してから爆発するまでのtick数
+	explosionTicks int
+	// 爆発の火が消えるまでのtick数
+	fireTicks int
+	// 既に爆発したか
+	exploded bool
 
 	// 現在のtick
 	tick int
@@ -23,11 +33,16 @@ type Bomb struct {
 	mu sync.RWMutex `exhaustruct:"optional"`
 }
 
-func NewBomb(id ItemID, position Position) *Bomb {
+func NewBomb(id ItemID, position Position, ownerID PlayerID, fireRange, explosionTicks, fireTicks int) *Bomb {
 	return &Bomb{
-		id:       id,
-		position: position,
-		tick:     0,
+		id:             id,
+		position:       position,
+		ownerID:        ownerID,
+		fireRange:      fireRange,
+		explosionTicks: explosionTicks,
+		fireTicks:      fireTicks,
+		exploded:       false,
+		tick:           0,
 	}
 }
 
@@ -39,6 +54,17 @@ func (b *Bomb) Type() ItemType {
 	return ItemTypeBomb
 }
 
+func (b *Bomb) OwnerID() PlayerID {
+	return b.ownerID
+}
+
+// FireRange 爆発の範囲
+func (b *Bomb) FireRange() int {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.fireRange
+}
+
 func (b *Bomb) Position() Position {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
@@ -52,34 +78,63 @@ func (b *Bomb) Update(provider gameOperationProvider) bool {
 	b.tick++
 
 	// 爆発するタイミングになったら
-	if b.tick >= BombExplosionTick {
-		// 爆発の範囲にBombFireを設置
-		pos := b.position
-		// 中心
-		provider.addItem(NewBombFire(ItemID(uuid.New().String()), pos))
-
-		// 上下左右
-		for i := 1; i <= BombFireRange; i++ {
-			// 上
-			provider.addItem(NewBombFire(ItemID(uuid.New().String()), Position{X: pos.X, Y: pos.Y - i}))
-			// 下
-			provider.addItem(NewBombFire(ItemID(uuid.New().String()), Position{X: pos.X, Y: pos.Y + i}))
-			// 左
-			provider.addItem(NewBombFire(ItemID(uuid.New().String()), Position{X: pos.X - i, Y: pos.Y}))
-			// 右
-			provider.addItem(NewBombFire(ItemID(uuid.New().String()), Position{X: pos.X + i, Y: pos.Y}))
-		}
-
-		// ボム自体を削除
-		provider.RemoveItem(b.id)
-		return true
+	if b.tick >= b.explosionTicks {
+		return b.explodeWithoutLock(provider)
 	}
 
 	return false
 }
 
+// explodeWithoutLock 爆発の範囲にBombFireを設置し、ボム自体を削除する
+// 火は障害物で止まり、壊せる障害物は壊す
+// 既に爆発していた場合は何もせずfalseを返す
+func (b *Bomb) explodeWithoutLock(provider gameOperationProvider) bool {
+	if b.exploded {
+		return false
+	}
+	b.exploded = true
+	provider.emit(BombExploded{Bomb: b})
+
+	pos := b.position
+	explosion := newExplosion()
+	// 中心
+	b.addFire(provider, pos, explosion)
+
+	// 上下左右に広がる
+	for _, direction := range []Direction{DirectionUp, DirectionDown, DirectionLeft, DirectionRight} {
+		dx, dy := direction.ToVector()
+		for i := 1; i <= b.fireRange; i++ {
+			position := Position{X: pos.X + dx*i, Y: pos.Y + dy*i}
+			// 障害物に当たったら火はそこで止まる。壊せる障害物は壊してから止まる
+			if ob := provider.obstacleAt(position); ob != nil {
+				provider.destroyObstacle(ob)
+				break
+			}
+			b.addFire(provider, position, explosion)
+		}
+	}
+
+	// ボム自体を削除
+	provider.RemoveItem(b.id)
+	return true
+}
+
+// addFire 指定位置にこのボムから出たBombFireを設置する
+func (b *Bomb) addFire(provider gameOperationProvider, position Position, explosion *explosion) {
+	fire := NewBombFire(provider.newItemID(), position, b.fireTicks)
+	fire.ownerID = b.ownerID
+	fire.explosion = explosion
+	provider.addItem(fire)
+}
+
 // OnCollideWith 他のオブジェクトと衝突した時の処理
 func (b *Bomb) OnCollideWith(other collidable, provider gameOperationProvider) bool {
+	// 爆発の火に触れたら即座に誘爆する
+	if _, ok := other.(*BombFire); ok {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		return b.explodeWithoutLock(provider)
+	}
 	return false
 }
 
@@ -87,6 +142,13 @@ func (b *Bomb) OnCollideWith(other collidable, provider gameOperationProvider) b
 type BombFire struct {
 	id       ItemID
 	position Position
+	// 元のボムを設置したプレイヤー
+	ownerID PlayerID
+	// 同じ爆発から出た火で共有する状態
+	explosion *explosion
+
+	// 消えるまでのtick数
+	durationTicks int
 
 	// 現在のtick
 	tick int
@@ -94,11 +156,14 @@ type BombFire struct {
 	mu sync.RWMutex `exhaustruct:"optional"`
 }
 
-func NewBombFire(id ItemID, position Position) *BombFire {
+func NewBombFire(id ItemID, position Position, durationTicks int) *BombFire {
 	return &BombFire{
-		id:       id,
-		position: position,
-		tick:     0,
+		id:            id,
+		position:      position,
+		ownerID:       "",
+		explosion:     newExplosion(),
+		durationTicks: durationTicks,
+		tick:          0,
 	}
 }
 
@@ -110,6 +175,20 @@ func (bf *BombFire) Type() ItemType {
 	return ItemTypeBombFire
 }
 
+func (bf *BombFire) OwnerID() PlayerID {
+	return bf.ownerID
+}
+
+func (bf *BombFire) Damage() int {
+	return BombFireDamage
+}
+
+// markDamaged この火を含む爆発でプレイヤーにダメージを与えたことを記録する
+// 既にダメージを与えていた場合はfalseを返す
+func (bf *BombFire) markDamaged(playerID PlayerID) bool {
+	return bf.explosion.markDamaged(playerID)
+}
+
 func (bf *BombFire) Position() Position {
 	bf.mu.RLock()
 	defer bf.mu.RUnlock()
@@ -123,7 +202,7 @@ func (bf *BombFire) Update(provider gameOperationProvider) bool {
 	bf.tick++
 
 	// 一定時間経過したら消滅
-	if bf.tick >= BombFireDuration {
+	if bf.tick >= bf.durationTicks {
 		provider.RemoveItem(bf.id)
 		return true
 	}
@@ -136,3 +215,28 @@ func (bf *BombFire) OnCollideWith(other collidable, provider gameOperationProvid
 	// 何かと当たったとしても何もしない
 	return false
 }
+
+// explosion 1つのボムの爆発を表す
+// 爆発の火に触れたプレイヤーには、1回の爆発につき1度だけダメージを与える
+type explosion struct {
+	damaged map[PlayerID]bool
+
+	mu sync.Mutex `exhaustruct:"optional"`
+}
+
+func newExplosion() *explosion {
+	return &explosion{
+		damaged: make(map[PlayerID]bool),
+	}
+}
+
+// markDamaged ダメージを与えたことを記録する。既に与えていた場合はfalseを返す
+func (e *explosion) markDamaged(playerID PlayerID) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.damaged[playerID] {
+		return false
+	}
+	e.damaged[playerID] = true
+	return true
+}
@@ -9,7 +9,7 @@ import (
 func Test_Bomb(t *testing.T) {
 	game := NewGame(30, 30)
 
-	bomb := NewBomb(ItemID("bomb1"), Position{X: 5, Y: 8})
+	bomb := newTestBomb(ItemID("bomb1"), Position{X: 5, Y: 8})
 	game.addItem(bomb)
 
 	assert.Equal(t, ItemID("bomb1"), bomb.ID())
@@ -75,3 +75,52 @@ func Test_Bomb(t *testing.T) {
 	}
 	assert.Empty(t, game.GetItems())
 }
+
+func Test_Bomb_ChainReaction(t *testing.T) {
+	game := NewGame(30, 30)
+
+	bomb1 := newTestBomb(ItemID("bomb1"), Position{X: 5, Y: 8})
+	bomb1.tick = testTimings().bombExplosion - 1
+	game.addItem(bomb1)
+	// bomb1の爆発範囲内
+	bomb2 := newTestBomb(ItemID("bomb2"), Position{X: 8, Y: 8})
+	game.addItem(bomb2)
+	// bomb1の爆発範囲外だが、bomb2の爆発範囲内
+	bomb3 := newTestBomb(ItemID("bomb3"), Position{X: 8, Y: 12})
+	game.addItem(bomb3)
+	// どの爆発範囲にも入らない
+	bomb4 := newTestBomb(ItemID("bomb4"), Position{X: 20, Y: 20})
+	game.addItem(bomb4)
+
+	game.Step()
+
+	items := game.GetItems()
+	assert.NotContains(t, items, ItemID("bomb1"))
+	assert.NotContains(t, items, ItemID("bomb2"), "爆発の火に触れたボムは即座に誘爆する")
+	assert.NotContains(t, items, ItemID("bomb3"), "誘爆の火でさらに誘爆する")
+	assert.Contains(t, items, ItemID("bomb4"))
+
+	// bomb3の火が出ている
+	positions := make(map[Position]bool)
+	for _, item := range items {
+		if item.Type() == ItemTypeBombFire {
+			positions[item.Position()] = true
+		}
+	}
+	assert.True(t, positions[Position{X: 8, Y: 16}])
+}
+
+func Test_Bomb_OnCollideWith(t *testing.T) {
+	game := NewGame(30, 30)
+
+	bomb := newTestBomb(ItemID("bomb1"), Position{X: 5, Y: 8})
+	game.addItem(bomb)
+
+	assert.False(t, bomb.OnCollideWith(NewBullet("bullet1", Position{X: 5, Y: 8}, DirectionUp), game), "弾では爆発しない")
+	assert.Len(t, game.GetItems(), 1)
+
+	assert.True(t, bomb.OnCollideWith(newTestBombFire("fire1", Position{X: 5, Y: 8}), game))
+	assert.Len(t, game.GetItems(), 17)
+
+	assert.False(t, bomb.OnCollideWith(newTestBombFire("fire2", Position{X: 5, Y: 8}), game), "爆発は1度だけ")
+}
@@ -2,12 +2,33 @@ package game
 
 import "sync"
 
+// 弾が当たった時のダメージ
+const BulletDamage = 25
+
 type Bullet struct {
 	id        ItemID
 	position  Position
 	direction Direction
+	// 1マス進むごとに進行方向と垂直にずれる量。扇状に広がる弾で使う
+	spread int
+	// 最後に動く前の位置。すれ違った弾同士の衝突判定に使う
+	previousPosition Position
+	// 発射したプレイヤー
+	ownerID PlayerID
+	// 発射した武器
+	weaponType WeaponType
+	// 当たった時のダメージ
+	damage int
 	// 何tickで動くか
 	moveTick int
+	// 消えるまでに進むマス数。0なら制限なし
+	maxRange int
+	// 進んだマス数
+	traveled int
+	// プレイヤーを貫通するか
+	piercing bool
+	// ダメージを与えたプレイヤー。貫通する弾が同じプレイヤーに何度も当たらないようにする
+	damaged map[PlayerID]bool
 
 	// 現在のtick
 	tick int
@@ -18,12 +39,28 @@ type Bullet struct {
 var _ Item = (*Bullet)(nil)
 
 func NewBullet(id ItemID, position Position, direction Direction) *Bullet {
+	return NewBulletWithWeapon(id, position, direction, WeaponTypePistol, 0)
+}
+
+// NewBulletWithWeapon 武器の性能に従って弾を作る
+// spreadは1マス進むごとに進行方向と垂直にずれる量
+func NewBulletWithWeapon(id ItemID, position Position, direction Direction, weaponType WeaponType, spread int) *Bullet {
+	spec := weaponType.Spec()
 	return &Bullet{
-		id:        id,
-		position:  position,
-		direction: direction,
-		moveTick:  30, // 60fpsで0.5秒
-		tick:      0,
+		id:               id,
+		position:         position,
+		direction:        direction,
+		spread:           spread,
+		previousPosition: position,
+		ownerID:          "",
+		weaponType:       weaponType,
+		damage:           spec.Damage,
+		moveTick:         spec.MoveTicks,
+		maxRange:         spec.Range,
+		traveled:         0,
+		piercing:         spec.Piercing,
+		damaged:          make(map[PlayerID]bool),
+		tick:             0,
 	}
 }
 
@@ -35,27 +72,68 @@ func (b *Bullet) Type() ItemType {
 	return ItemTypeBullet
 }
 
+func (b *Bullet) OwnerID() PlayerID {
+	return b.ownerID
+}
+
+// WeaponType 発射した武器
+func (b *Bullet) WeaponType() WeaponType {
+	return b.weaponType
+}
+
+func (b *Bullet) Damage() int {
+	return b.damage
+}
+
+// markDamaged プレイヤーにダメージを与えたことを記録する
+// 既にダメージを与えていた場合はfalseを返す
+func (b *Bullet) markDamaged(playerID PlayerID) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.damaged[playerID] {
+		return false
+	}
+	b.damaged[playerID] = true
+	return true
+}
+
 func (b *Bullet) Position() Position {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
 	return b.position
 }
 
-func (b *Bullet) Update(_ gameOperationProvider) bool {
+// Direction 進行方向
+func (b *Bullet) Direction() Direction {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.direction
+}
+
+// PreviousPosition 最後に動く前の位置
+func (b *Bullet) PreviousPosition() Position {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.previousPosition
+}
+
+func (b *Bullet) Update(provider gameOperationProvider) bool {
 	b.mu.Lock()
 	defer b.mu.Unlock()
 	b.tick++
 	if b.tick >= b.moveTick {
 		b.tick = 0
-		switch b.direction {
-		case DirectionUp:
-			b.position.Y--
-		case DirectionDown:
-			b.position.Y++
-		case DirectionLeft:
-			b.position.X--
-		case DirectionRight:
-			b.position.X++
+		b.previousPosition = b.position
+
+		// 進行方向に進み、spreadの分だけ垂直方向にずれる
+		dx, dy := b.direction.ToVector()
+		b.position.X += dx - dy*b.spread
+		b.position.Y += dy + dx*b.spread
+
+		// 射程を超えたら消滅
+		b.traveled++
+		if b.maxRange > 0 && b.traveled > b.maxRange {
+			provider.RemoveItem(b.id)
 		}
 		return true
 	}
@@ -63,11 +141,27 @@ func (b *Bullet) Update(_ gameOperationProvider) bool {
 }
 
 func (b *Bullet) OnCollideWith(other collidable, provider gameOperationProvider) bool {
-	switch other.(type) {
+	switch other := other.(type) {
 	case *Player:
+		// 味方に当たらない場合や貫通する場合はすり抜ける
+		if b.piercing || !provider.canDamage(b.ownerID, other.PlayerID) {
+			return false
+		}
 		// プレイヤーと衝突したら自分自身は消滅
 		provider.RemoveItem(b.ID())
 		return true
+	case *Bullet:
+		// 同じプレイヤーの弾同士はすり抜ける
+		if b.ownerID != "" && b.ownerID == other.OwnerID() {
+			return false
+		}
+		// 弾同士がぶつかったら消滅
+		provider.RemoveItem(b.ID())
+		return true
+	case *BombFire, *Wall, *Block:
+		// 爆発の火や障害物に触れたら消滅
+		provider.RemoveItem(b.ID())
+		return true
 	default:
 		return false
 	}
@@ -12,3 +12,9 @@ type collision struct {
 	Player *Player
 	Item   Item
 }
+
+// itemCollision は同じ位置にあるItem同士の衝突を表す
+type itemCollision struct {
+	A Item
+	B Item
+}
@@ -0,0 +1,123 @@
+package game
+
+import "sync"
+
+type EventType string
+
+const (
+	EventTypePlayerJoined     EventType = "player_joined"
+	EventTypePlayerLeft       EventType = "player_left"
+	EventTypePlayerMoved      EventType = "player_moved"
+	EventTypePlayerUpdated    EventType = "player_updated"
+	EventTypePlayerDied       EventType = "player_died"
+	EventTypeItemSpawned      EventType = "item_spawned"
+	EventTypeItemMoved        EventType = "item_moved"
+	EventTypeItemRemoved      EventType = "item_removed"
+	EventTypeBombExploded     EventType = "bomb_exploded"
+	EventTypePowerUpCollected EventType = "power_up_collected"
+	EventTypeMatchUpdated     EventType = "match_updated"
+)
+
+// Event ゲーム内で起きた出来事
+// Stepを呼ぶと、前回のStepからの間に起きた出来事が発生順に返される
+type Event interface {
+	Type() EventType
+}
+
+// PlayerJoined プレイヤーがゲームに参加した
+type PlayerJoined struct {
+	Player *Player
+}
+
+// PlayerLeft プレイヤーがゲームから抜けた
+type PlayerLeft struct {
+	Player *Player
+}
+
+// PlayerMoved プレイヤーの位置か向きが変わった
+type PlayerMoved struct {
+	Player *Player
+}
+
+// PlayerUpdated プレイヤーのHPやパワーアップ、武器など、位置以外の状態が変わった
+type PlayerUpdated struct {
+	Player *Player
+}
+
+// PlayerDied プレイヤーが倒された
+type PlayerDied struct {
+	Player *Player
+	// 倒したプレイヤー。爆発の火など持ち主のいない攻撃の場合は空
+	KillerID PlayerID
+	// 倒した攻撃のアイテム。弾か爆発の火
+	Cause Item
+}
+
+// ItemSpawned アイテムが盤面に追加された
+type ItemSpawned struct {
+	Item Item
+}
+
+// ItemMoved アイテムが動いた
+type ItemMoved struct {
+	Item Item
+}
+
+// ItemRemoved アイテムが盤面から削除された
+type ItemRemoved struct {
+	Item Item
+}
+
+// BombExploded ボムが爆発した。爆発の火はItemSpawnedとして別に通知される
+type BombExploded struct {
+	Bomb *Bomb
+}
+
+// PowerUpCollected プレイヤーがパワーアップを拾った
+type PowerUpCollected struct {
+	Player  *Player
+	PowerUp *PowerUp
+}
+
+// MatchUpdated マッチのフェーズやスコアが変わった
+type MatchUpdated struct {
+	Match *Match
+}
+
+func (e PlayerJoined) Type() EventType     { return EventTypePlayerJoined }
+func (e PlayerLeft) Type() EventType       { return EventTypePlayerLeft }
+func (e PlayerMoved) Type() EventType      { return EventTypePlayerMoved }
+func (e PlayerUpdated) Type() EventType    { return EventTypePlayerUpdated }
+func (e PlayerDied) Type() EventType       { return EventTypePlayerDied }
+func (e ItemSpawned) Type() EventType      { return EventTypeItemSpawned }
+func (e ItemMoved) Type() EventType        { return EventTypeItemMoved }
+func (e ItemRemoved) Type() EventType      { return EventTypeItemRemoved }
+func (e BombExploded) Type() EventType     { return EventTypeBombExploded }
+func (e PowerUpCollected) Type() EventType { return EventTypePowerUpCollected }
+func (e MatchUpdated) Type() EventType     { return EventTypeMatchUpdated }
+
+// queue Stepで返すまでの間、発生したイベントや受け付けた操作を溜めておく
+type queue[T any] struct {
+	values []T
+
+	mu sync.Mutex `exhaustruct:"optional"`
+}
+
+func newQueue[T any]() *queue[T] {
+	return &queue[T]{values: nil}
+}
+
+func (q *queue[T]) push(value T) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.values = append(q.values, value)
+}
+
+// drain 溜まっている値を全て取り出す
+func (q *queue[T]) drain() []T {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	values := q.values
+	q.values = nil
+	return values
+}
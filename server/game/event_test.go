@@ -0,0 +1,124 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// stepN n回Stepを呼び、起きたイベントをまとめて返す
+func stepN(game *Game, n int) []Event {
+	var events []Event
+	for range n {
+		events = append(events, game.Step()...)
+	}
+	return events
+}
+
+func eventTypes(events []Event) []EventType {
+	types := make([]EventType, 0, len(events))
+	for _, event := range events {
+		types = append(types, event.Type())
+	}
+	return types
+}
+
+// removedItemIDs ItemRemovedイベントで削除されたアイテムのID一覧を返す
+func removedItemIDs(events []Event) []ItemID {
+	var ids []ItemID
+	for _, event := range events {
+		if removed, ok := event.(ItemRemoved); ok {
+			ids = append(ids, removed.Item.ID())
+		}
+	}
+	return ids
+}
+
+func Test_Game_Events(t *testing.T) {
+	t.Run("プレイヤーの参加、移動、退出が通知される", func(t *testing.T) {
+		game := NewGame(30, 30)
+		game.AddPlayer("player1")
+		player := game.GetPlayers()["player1"]
+		game.MovePlayer("player1", Position{X: 3, Y: 3}, DirectionRight)
+		game.RemovePlayer("player1")
+
+		assert.Equal(t, []Event{
+			PlayerJoined{Player: player},
+			PlayerMoved{Player: player},
+			PlayerLeft{Player: player},
+		}, game.Step())
+
+		// 1度返したイベントは次のStepでは返さない
+		assert.Empty(t, game.Step())
+	})
+
+	t.Run("倒されたプレイヤーと倒したプレイヤーが通知される", func(t *testing.T) {
+		game := NewGame(30, 30)
+		game.AddPlayer("player1")
+		game.AddPlayer("player2")
+		game.MovePlayer("player1", Position{X: 2, Y: 3}, DirectionRight)
+		game.MovePlayer("player2", Position{X: 3, Y: 3}, DirectionLeft)
+		victim := game.GetPlayers()["player2"]
+		victim.hp = BulletDamage
+		game.ShootBullet("player1")
+
+		events := game.Step()
+		var died []PlayerDied
+		for _, event := range events {
+			if event, ok := event.(PlayerDied); ok {
+				died = append(died, event)
+			}
+		}
+		if assert.Len(t, died, 1) {
+			assert.Equal(t, victim, died[0].Player)
+			assert.Equal(t, PlayerID("player1"), died[0].KillerID)
+			bullet, ok := died[0].Cause.(*Bullet)
+			if assert.True(t, ok, "倒した攻撃の弾が通知される") {
+				assert.Equal(t, PlayerID("player1"), bullet.OwnerID())
+			}
+		}
+		assert.Contains(t, events, Event(PlayerUpdated{Player: victim}))
+	})
+
+	t.Run("パワーアップを拾ったプレイヤーが通知される", func(t *testing.T) {
+		game := NewGame(30, 30)
+		game.AddPlayer("player1")
+		player := game.GetPlayers()["player1"]
+		game.MovePlayer("player1", Position{X: 5, Y: 5}, DirectionRight)
+		game.Step()
+
+		powerUp := newTestPowerUp("powerup1", ItemTypeSpeedUp, Position{X: 5, Y: 5})
+		game.addItem(powerUp)
+
+		events := game.Step()
+		assert.Contains(t, events, Event(PowerUpCollected{Player: player, PowerUp: powerUp}))
+		assert.Contains(t, events, Event(ItemRemoved{Item: powerUp}))
+	})
+
+	t.Run("ボムが爆発すると爆発と火の追加とボムの削除が通知される", func(t *testing.T) {
+		game := NewGame(30, 30)
+		bomb := newTestBomb("bomb1", Position{X: 10, Y: 10})
+		game.addItem(bomb)
+		game.Step()
+
+		events := stepN(game, testTimings().bombExplosion)
+		types := eventTypes(events)
+		assert.Equal(t, EventTypeBombExploded, types[0])
+		assert.Equal(t, Event(ItemRemoved{Item: bomb}), events[len(events)-1])
+		for _, eventType := range types[1 : len(types)-1] {
+			assert.Equal(t, EventTypeItemSpawned, eventType)
+		}
+	})
+
+	t.Run("マッチの状態の変化が通知される", func(t *testing.T) {
+		game := NewGameWithConfig(Config{
+			Width:  30,
+			Height: 30,
+			Match:  MatchConfig{MinPlayers: 1, CountdownTicks: 1},
+		})
+		game.AddPlayer("player1")
+		game.Step()
+
+		assert.Contains(t, eventTypes(game.Step()), EventTypeMatchUpdated)
+	})
+}
@@ -4,10 +4,10 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
-	"github.com/google/uuid"
 	"github.com/shibayu36/terminal-shooter/server/stats"
 	"github.com/shibayu36/terminal-shooter/shared"
 )
@@ -20,116 +20,460 @@ type Game struct {
 	Players map[PlayerID]*Player
 	Items   map[ItemID]Item
 
-	// 新しく追加されたアイテムを管理する
-	// 1tickごとにFlushされる
-	AddedItems map[ItemID]Item
+	// アイテムとプレイヤーの位置の空間インデックス
+	// 追加・削除・移動のたびに更新する
+	itemIndex   *spatialIndex[ItemID, Item]
+	playerIndex *spatialIndex[PlayerID, *Player]
 
-	// 削除されたアイテムを管理する
-	RemovedItems map[ItemID]Item
+	mode     GameMode
+	match    *Match
+	weapon   WeaponConfig
+	movement MovementConfig
+	arena    ArenaConfig
+	loop     LoopConfig
+	// 1秒あたりのtick数
+	ticksPerSecond int
+	// 実時間で決めている長さをtick数に直したもの
+	timings timings
+
+	// 前回パワーアップを出現させてからのtick数
+	powerUpSpawnTick int
+
+	// 乱数とアイテムIDの生成器
+	random *random
+	seed   int64
+
+	// 次のStepで返すイベント
+	events *queue[Event]
+	// 次のStepで記録する、ゲームの外から受け付けた操作
+	inputs *queue[Input]
+	// ゲームの外からの操作とStepの盤面の更新を直列にする
+	// 操作はこのロックを取ったまま記録して反映するので、記録した順に反映され、Stepの途中に割り込まない
+	inputMu sync.Mutex `exhaustruct:"optional"`
+	// これまでにStepを呼んだ回数
+	tick int64
+
+	// Stepの最初に呼ぶ処理
+	stepHooks []func()
+	// Stepの最後に、そのStepの結果を渡して呼ぶ処理
+	stepObservers []func(StepResult)
 
 	mu sync.RWMutex `exhaustruct:"optional"`
 }
 
+// Config ゲームの設定
+type Config struct {
+	Width  int
+	Height int
+	Match  MatchConfig
+	// ゲームのルール。nilならDeathmatch
+	Mode GameMode
+	// プレイヤーの武器の制限
+	Weapon WeaponConfig
+	// プレイヤーの移動の制限
+	Movement MovementConfig
+	// 盤面に配置する障害物
+	Arena ArenaConfig
+	// 乱数のシード。同じシードで同じ操作をすれば同じ結果になる
+	Seed int64
+	// 更新ループの設定
+	Loop LoopConfig
+	// 1秒あたりのtick数。0ならTicksPerSecond
+	// ゲーム内の時間は全てtick数で決まるので、変えるとtick数で指定した時間の実時間での長さも変わる
+	TicksPerSecond int
+}
+
 // gameOperationProvider はアイテム更新や衝突時に必要な操作を提供するインターフェース。Gameのメソッドの一部だけを公開する
 type gameOperationProvider interface {
 	RemoveItem(id ItemID)
 	UpdatePlayerStatus(playerID PlayerID, status PlayerStatus) *Player
 	addItem(item Item)
+	onPlayerKilled(victimID PlayerID, killerID PlayerID, cause Item)
+	canDamage(attackerID PlayerID, victimID PlayerID) bool
+	obstacleAt(position Position) obstacle
+	destroyObstacle(ob obstacle)
+	newItemID() ItemID
+	hasItem(itemID ItemID) bool
+	emit(event Event)
 }
 
 var _ gameOperationProvider = (*Game)(nil)
 
 func NewGame(width, height int) *Game {
-	return &Game{
-		Width:        width,
-		Height:       height,
-		Players:      make(map[PlayerID]*Player),
-		Items:        make(map[ItemID]Item),
-		AddedItems:   make(map[ItemID]Item),
-		RemovedItems: make(map[ItemID]Item),
-	}
+	return NewGameWithConfig(Config{
+		Width:  width,
+		Height: height,
+		Match:  MatchConfig{MinPlayers: 0, CountdownTicks: 0, TimeLimitTicks: 0, ScoreLimit: 0, ResultTicks: 0},
+		Mode:   nil,
+		Weapon: WeaponConfig{
+			BulletCooldownTicks: 0,
+			MaxAmmo:             0,
+			ReloadTicks:         0,
+			MaxBombs:            0,
+			BombExplosionTicks:  0,
+			BombFireRange:       0,
+			BulletMoveTicks:     nil,
+		},
+		Movement:       MovementConfig{MoveTicks: 0},
+		Arena:          ArenaConfig{PillarInterval: 0, BlockDensity: 0, PowerUpChance: 0},
+		Seed:           0,
+		Loop:           LoopConfig{MaxCatchUpTicks: 0},
+		TicksPerSecond: 0,
+	})
 }
 
-type UpdatedResultType string
+func NewGameWithConfig(config Config) *Game {
+	mode := config.Mode
+	if mode == nil {
+		mode = &Deathmatch{}
+	}
+	ticksPerSecond := config.TicksPerSecond
+	if ticksPerSecond <= 0 {
+		ticksPerSecond = TicksPerSecond
+	}
+	timings := newTimings(ticksPerSecond)
+	if config.Weapon.BombExplosionTicks > 0 {
+		timings.bombExplosion = config.Weapon.BombExplosionTicks
+	}
 
-const (
-	UpdatedResultTypeItemsUpdated   UpdatedResultType = "items_updated"
-	UpdatedResultTypePlayersUpdated UpdatedResultType = "players_updated"
-)
+	g := &Game{
+		Width:            config.Width,
+		Height:           config.Height,
+		Players:          make(map[PlayerID]*Player),
+		Items:            make(map[ItemID]Item),
+		itemIndex:        newSpatialIndex[ItemID, Item](),
+		playerIndex:      newSpatialIndex[PlayerID, *Player](),
+		mode:             mode,
+		match:            NewMatch(config.Match, mode, ticksPerSecond),
+		weapon:           config.Weapon,
+		movement:         config.Movement,
+		arena:            config.Arena,
+		loop:             config.Loop,
+		ticksPerSecond:   ticksPerSecond,
+		timings:          timings,
+		powerUpSpawnTick: 0,
+		random:           newRandom(config.Seed),
+		seed:             config.Seed,
+		events:           newQueue[Event](),
+		inputs:           newQueue[Input](),
+		tick:             0,
+		stepHooks:        nil,
+		stepObservers:    nil,
+	}
+	g.placeWallsWithoutLock()
+	g.placeBlocksWithoutLock()
+	// 開始条件を既に満たしていればその場でマッチを始める
+	g.match.mu.Lock()
+	g.match.transitionWithoutLock(g.Players)
+	g.match.mu.Unlock()
+	return g
+}
+
+// 1秒あたりのtick数の初期値
+const TicksPerSecond = 60
 
-type UpdatedResult struct {
-	Type UpdatedResultType
+// TicksPerSecond このゲームの1秒あたりのtick数
+func (g *Game) TicksPerSecond() int {
+	return g.ticksPerSecond
 }
 
-// ゲーム状態を更新するループを開始する
-// アイテムが何らか更新されたことを通知するチャネルを返す
-func (g *Game) StartUpdateLoop(ctx context.Context) <-chan UpdatedResult {
-	updatedCh := make(chan UpdatedResult)
+// ゲーム状態を一定間隔で更新するループを開始する
+// ループは実時間に合わせてStepを呼び出すだけで、ゲームの状態はStepの呼び出し回数だけで決まる
+// ゲーム内で起きたイベントを発生順に通知するチャネルを返す
+func (g *Game) StartUpdateLoop(ctx context.Context) <-chan Event {
+	eventCh := make(chan Event)
 
 	go func() {
-		defer close(updatedCh)
+		defer close(eventCh)
 
-		ticker := time.NewTicker(16700 * time.Microsecond) // 16.7ms
+		interval := time.Second / time.Duration(g.ticksPerSecond)
+		ticker := time.NewTicker(interval)
 		defer ticker.Stop()
+		clock := newTickClock(interval, g.loop.MaxCatchUpTicks, time.Now())
 		for {
 			select {
 			case <-ticker.C:
-				start := time.Now()
-				g.update(updatedCh)
-				stats.GameLoopDuration.Observe(time.Since(start).Seconds())
+				steps, missed := clock.advance(time.Now())
+				if missed > 0 {
+					stats.MissedTicks.Add(float64(missed))
+				}
+				if steps > 1 {
+					stats.CatchUpTicks.Add(float64(steps - 1))
+				}
+				for range steps {
+					start := time.Now()
+					g.update(eventCh)
+					elapsed := time.Since(start)
+					stats.GameLoopDuration.Observe(elapsed.Seconds())
+					if elapsed > interval {
+						stats.TickOverruns.Inc()
+					}
+				}
 			case <-ctx.Done():
 				return
 			}
 		}
 	}()
 
-	return updatedCh
+	return eventCh
+}
+
+// ゲーム状態を1tick進め、起きたイベントをチャネルに通知する
+func (g *Game) update(eventCh chan<- Event) {
+	for _, event := range g.Step() {
+		// 受け取り側が詰まっているとここで待たされるので、待ち時間を記録する
+		start := time.Now()
+		eventCh <- event
+		stats.EventSendDuration.Observe(time.Since(start).Seconds())
+	}
 }
 
-// ゲーム状態を更新する
-func (g *Game) update(updatedCh chan<- UpdatedResult) {
-	items := g.GetItems()
+// Step ゲーム状態を1tick進め、前回のStepからの間に起きたイベントを発生順に返す
+// 実時間とは関係なく進むので、テストやボットは実時間より速くゲームを進められる
+// 同じシードで作ったゲームに同じ順番で同じ操作とStepを行えば、同じ結果になる
+func (g *Game) Step() []Event {
+	// ボットなどゲームの外からの操作を、盤面を更新する前に反映する
+	g.mu.RLock()
+	hooks := g.stepHooks
+	g.mu.RUnlock()
+	for _, hook := range hooks {
+		hook()
+	}
 
-	updatedItems := []Item{}
-	updatedPlayers := []*Player{}
+	// 盤面の更新中は操作を待たせ、更新が終わるまでに受け付けた操作をこのStepの操作として記録する
+	g.inputMu.Lock()
 
+	// 結果が再現できるように、アイテムはIDの順に更新する
+	items := g.getSortedItems()
+
+	var movedItems []Item
 	for _, item := range items {
 		if item.Update(g) {
-			updatedItems = append(updatedItems, item)
+			movedItems = append(movedItems, item)
 		}
 	}
-	for _, updatedItem := range updatedItems {
+	g.reindexItems(movedItems)
+	for _, item := range movedItems {
 		// 盤面外に出たアイテムを削除する
-		if !g.isWithinBounds(updatedItem) {
-			g.RemoveItem(updatedItem.ID())
+		if !g.isWithinBounds(item) {
+			g.RemoveItem(item.ID())
+			continue
+		}
+		// Updateの中で消滅したアイテムは動いたことにしない
+		if g.hasItem(item.ID()) {
+			g.emit(ItemMoved{Item: item})
 		}
 	}
 
+	g.resolveItemCollisions()
+
 	for _, collision := range g.detectCollisions() {
 		if collision.Player.OnCollideWith(collision.Item, g) {
-			updatedPlayers = append(updatedPlayers, collision.Player)
+			g.emit(PlayerUpdated{Player: collision.Player})
 		}
+		collision.Item.OnCollideWith(collision.Player, g)
+	}
+
+	for _, player := range g.respawnPlayers() {
+		g.emit(PlayerUpdated{Player: player})
+	}
+	for _, player := range g.tickPlayerEffects() {
+		g.emit(PlayerUpdated{Player: player})
+	}
+	for _, player := range g.tickWeapons() {
+		g.emit(PlayerUpdated{Player: player})
+	}
+	for _, player := range g.tickMovements() {
+		g.emit(PlayerMoved{Player: player})
+	}
+	g.spawnPowerUp()
+	g.updateMatch()
 
-		if collision.Item.OnCollideWith(collision.Player, g) {
-			updatedItems = append(updatedItems, collision.Item)
+	events := g.events.drain()
+	result := g.finishStep(events)
+	g.inputMu.Unlock()
+
+	g.notifyStepObservers(result)
+	return events
+}
+
+// finishStep tickを進め、このStepの結果を作る
+func (g *Game) finishStep(events []Event) StepResult {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.tick++
+	return StepResult{Tick: g.tick, Inputs: g.inputs.drain(), Events: events}
+}
+
+// notifyStepObservers Stepの結果を登録された処理に渡す
+func (g *Game) notifyStepObservers(result StepResult) {
+	g.mu.RLock()
+	observers := g.stepObservers
+	g.mu.RUnlock()
+
+	for _, observer := range observers {
+		observer(result)
+	}
+}
+
+// イベントを発生させる。次のStepで返される
+// アイテムなどのUpdateやOnCollideWithのために必要なprimitive操作
+func (g *Game) emit(event Event) {
+	g.events.push(event)
+}
+
+// 復活までの時間が経過したプレイヤーを盤面内の空いているランダムな位置で復活させる
+func (g *Game) respawnPlayers() []*Player {
+	if !g.mode.RespawnAllowed() || !g.match.IsRunning() {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var respawned []*Player
+	for _, player := range g.sortedPlayersWithoutLock() {
+		if player.tickDead() < g.timings.respawn {
+			continue
+		}
+		position, ok := g.findEmptyPositionWithoutLock()
+		if !ok {
+			// 空いている位置がなければ次のtickで再度試す
+			continue
 		}
+		player.respawn(position)
+		g.reindexPlayerWithoutLock(player)
+		respawned = append(respawned, player)
+	}
+	return respawned
+}
+
+// プレイヤーにかかっているパワーアップの効果の残り時間を進める
+func (g *Game) tickPlayerEffects() []*Player {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var updated []*Player
+	for _, player := range g.sortedPlayersWithoutLock() {
+		if player.tickEffects() {
+			updated = append(updated, player)
+		}
+	}
+	return updated
+}
+
+// プレイヤーの武器の発射間隔とリロードの残り時間を進める
+func (g *Game) tickWeapons() []*Player {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	activeBombs := g.countActiveBombsWithoutLock()
+	var updated []*Player
+	for _, player := range g.sortedPlayersWithoutLock() {
+		if player.tickWeapon(activeBombs[player.PlayerID]) {
+			updated = append(updated, player)
+		}
+	}
+	return updated
+}
+
+// プレイヤーごとに設置中のボムの数を数える
+func (g *Game) countActiveBombsWithoutLock() map[PlayerID]int {
+	counts := make(map[PlayerID]int)
+	for _, item := range g.Items {
+		if bomb, ok := item.(*Bomb); ok {
+			counts[bomb.OwnerID()]++
+		}
+	}
+	return counts
+}
+
+// 対戦中は一定間隔で盤面の空いている位置にパワーアップを出現させる
+func (g *Game) spawnPowerUp() {
+	if !g.match.IsRunning() {
+		return
 	}
 
-	// 新しく追加されたアイテムがあれば追加してFlushする
 	g.mu.Lock()
-	for _, item := range g.AddedItems {
-		updatedItems = append(updatedItems, item)
+	defer g.mu.Unlock()
+
+	g.powerUpSpawnTick++
+	if g.powerUpSpawnTick < g.timings.powerUpSpawnInterval {
+		return
+	}
+	g.powerUpSpawnTick = 0
+
+	powerUpCount := 0
+	for _, item := range g.Items {
+		if _, ok := item.(*PowerUp); ok {
+			powerUpCount++
+		}
 	}
-	g.AddedItems = make(map[ItemID]Item)
-	g.mu.Unlock()
+	if powerUpCount >= MaxPowerUps {
+		return
+	}
+
+	position, ok := g.findEmptyPositionWithoutLock()
+	if !ok {
+		return
+	}
+	itemType := PowerUpTypes[g.random.intn(len(PowerUpTypes))]
+	g.addItemWithoutLock(g.newPowerUp(itemType, position))
+}
 
-	if len(updatedItems) > 0 {
-		updatedCh <- UpdatedResult{Type: UpdatedResultTypeItemsUpdated}
+// newPowerUp このゲームのtick数で効果の持続時間と寿命を決めたパワーアップを作る
+func (g *Game) newPowerUp(itemType ItemType, position Position) *PowerUp {
+	effectTicks := g.timings.powerUpEffect
+	if itemType == ItemTypeShield {
+		effectTicks = g.timings.shieldEffect
+	}
+	return NewPowerUp(g.random.newItemID(), itemType, position, g.timings.powerUpLifetime, effectTicks)
+}
+
+// プレイヤーもアイテムもいないランダムな位置を探す
+func (g *Game) findEmptyPositionWithoutLock() (Position, bool) {
+	const maxAttempts = 100
+	for range maxAttempts {
+		position := Position{X: g.random.intn(g.Width), Y: g.random.intn(g.Height)}
+		if !g.itemIndex.has(position) && !g.playerIndex.has(position) {
+			return position, true
+		}
+	}
+	return Position{X: 0, Y: 0}, false
+}
+
+// マッチを1tick進め、フェーズの遷移に応じて盤面をリセットする
+func (g *Game) updateMatch() {
+	transition := g.match.advance(g.GetPlayers())
+
+	if transition.started || transition.finished {
+		g.resetBoard()
+	}
+
+	if transition.changed {
+		g.emit(MatchUpdated{Match: g.match})
+	}
+}
+
+// 盤面上の壁以外のアイテムを全て削除し、全プレイヤーを復活させる
+// ブロックは新しく配置し直す
+func (g *Game) resetBoard() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for _, item := range g.sortedItemsWithoutLock() {
+		if _, ok := item.(*Wall); ok {
+			continue
+		}
+		g.removeItemWithoutLock(item)
 	}
 
-	if len(updatedPlayers) > 0 {
-		updatedCh <- UpdatedResult{Type: UpdatedResultTypePlayersUpdated}
+	for _, player := range g.sortedPlayersWithoutLock() {
+		player.revive()
+		g.emit(PlayerUpdated{Player: player})
 	}
+
+	g.placeBlocksWithoutLock()
 }
 
 // detectCollisions は現在のゲーム状態から衝突しているオブジェクトのペアを検出する
@@ -139,14 +483,9 @@ func (g *Game) detectCollisions() []collision {
 
 	var collisions []collision
 
-	// プレイヤーと弾の衝突を検出
-	itemPosMap := make(map[Position][]Item)
-	for _, item := range g.Items {
-		itemPosMap[item.Position()] = append(itemPosMap[item.Position()], item)
-	}
-
-	for _, player := range g.Players {
-		for _, item := range itemPosMap[player.Position()] {
+	// プレイヤーと同じ位置にあるアイテムとの衝突を検出
+	for _, player := range g.sortedPlayersWithoutLock() {
+		for _, item := range g.itemIndex.at(player.Position()) {
 			collisions = append(collisions, collision{
 				Player: player,
 				Item:   item,
@@ -157,6 +496,58 @@ func (g *Game) detectCollisions() []collision {
 	return collisions
 }
 
+// detectItemCollisions は同じ位置にあるアイテム同士、およびすれ違った弾同士のペアを検出する
+func (g *Game) detectItemCollisions() []itemCollision {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var collisions []itemCollision
+
+	for _, items := range g.itemIndex.crowdedPoints() {
+		for i := range items {
+			for j := i + 1; j < len(items); j++ {
+				collisions = append(collisions, itemCollision{A: items[i], B: items[j]})
+			}
+		}
+	}
+
+	// 隣り合った弾が同時に動くと位置が入れ替わるので、正面衝突として扱う
+	for _, item := range g.sortedItemsWithoutLock() {
+		bullet, ok := item.(*Bullet)
+		if !ok || bullet.PreviousPosition() == bullet.Position() {
+			continue
+		}
+		for _, other := range g.itemIndex.at(bullet.PreviousPosition()) {
+			otherBullet, ok := other.(*Bullet)
+			// ペアを重複させないためにIDの小さい方から数える
+			if ok && bullet.ID() < otherBullet.ID() && otherBullet.PreviousPosition() == bullet.Position() {
+				collisions = append(collisions, itemCollision{A: bullet, B: otherBullet})
+			}
+		}
+	}
+
+	return collisions
+}
+
+// resolveItemCollisions はアイテム同士の衝突を処理する
+// 誘爆で新しく出た火がさらに別のアイテムに触れることがあるので、状態が変わらなくなるまで繰り返す
+func (g *Game) resolveItemCollisions() {
+	for {
+		changed := false
+		for _, collision := range g.detectItemCollisions() {
+			if collision.A.OnCollideWith(collision.B, g) {
+				changed = true
+			}
+			if collision.B.OnCollideWith(collision.A, g) {
+				changed = true
+			}
+		}
+		if !changed {
+			return
+		}
+	}
+}
+
 // アイテムが盤面内にあるかどうかを判定する
 func (g *Game) isWithinBounds(item Item) bool {
 	pos := item.Position()
@@ -165,26 +556,75 @@ func (g *Game) isWithinBounds(item Item) bool {
 
 // プレイヤーを追加する
 // 全てデフォルトで初期化する
-func (g *Game) AddPlayer(playerID PlayerID) {
+// 移動速度が制限されている場合はクライアントが位置を選べないので、空いているランダムな位置に配置する
+// 同じIDのプレイヤーが既にいる場合は上書きせずにfalseを返す
+func (g *Game) AddPlayer(playerID PlayerID) bool {
+	g.inputMu.Lock()
+	defer g.inputMu.Unlock()
+	g.recordInput(Input{Type: InputTypeJoin, PlayerID: playerID})
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
-	g.Players[playerID] = &Player{
-		PlayerID:  playerID,
-		position:  Position{X: 0, Y: 0},
-		direction: DirectionUp,
-		status:    PlayerStatusAlive,
+
+	if _, ok := g.Players[playerID]; ok {
+		return false
+	}
+	g.addPlayerWithoutLock(playerID, "")
+	return true
+}
+
+// addPlayerWithoutLock 表示名を付けてプレイヤーを追加し、参加したイベントを記録する
+func (g *Game) addPlayerWithoutLock(playerID PlayerID, displayName string) *Player {
+	position := Position{X: 0, Y: 0}
+	if g.movement.MoveTicks > 0 {
+		if empty, ok := g.findEmptyPositionWithoutLock(); ok {
+			position = empty
+		}
+	}
+
+	player := &Player{
+		PlayerID:       playerID,
+		displayName:    displayName,
+		position:       position,
+		direction:      DirectionUp,
+		status:         PlayerStatusAlive,
+		hp:             MaxHP,
+		team:           g.mode.AssignTeam(g.Players),
+		deadTicks:      0,
+		weapon:         newWeapon(g.weapon),
+		movement:       newMovement(g.movement),
+		ticksPerSecond: g.ticksPerSecond,
 	}
+	g.Players[playerID] = player
+	g.playerIndex.insert(playerID, player, position)
+	g.emit(PlayerJoined{Player: player})
+	return player
 }
 
 // プレイヤーを削除する
 func (g *Game) RemovePlayer(playerID PlayerID) {
+	g.inputMu.Lock()
+	defer g.inputMu.Unlock()
+	g.recordInput(Input{Type: InputTypeLeave, PlayerID: playerID})
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
+	player, ok := g.Players[playerID]
+	if !ok {
+		return
+	}
 	delete(g.Players, playerID)
+	g.playerIndex.remove(playerID)
+	g.match.removePlayer(playerID)
+	g.emit(PlayerLeft{Player: player})
 }
 
 // プレイヤーの位置を更新する
 func (g *Game) MovePlayer(playerID PlayerID, position Position, direction Direction) *Player {
+	g.inputMu.Lock()
+	defer g.inputMu.Unlock()
+	g.recordInput(Input{Type: InputTypeMove, PlayerID: playerID, Position: position, Direction: direction})
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
@@ -192,11 +632,36 @@ func (g *Game) MovePlayer(playerID PlayerID, position Position, direction Direct
 	if !ok {
 		return nil
 	}
-	player.Move(position, direction)
+	g.movePlayerWithoutLock(player, position, direction)
 
 	return player
 }
 
+// 盤面の外は盤面の端に寄せ、障害物のある位置には移動できず、向きだけ変える
+func (g *Game) movePlayerWithoutLock(player *Player, position Position, direction Direction) {
+	position = Position{X: min(max(position.X, 0), g.Width-1), Y: min(max(position.Y, 0), g.Height-1)}
+	if g.obstacleAtWithoutLock(position) != nil {
+		position = player.Position()
+	}
+	player.Move(position, direction)
+	g.reindexPlayerWithoutLock(player)
+	g.emit(PlayerMoved{Player: player})
+}
+
+// プレイヤーの移動を空間インデックスに反映する
+func (g *Game) reindexPlayerWithoutLock(player *Player) {
+	g.playerIndex.move(player.PlayerID, player.Position())
+}
+
+// Updateで動いたアイテムを空間インデックスに反映する
+func (g *Game) reindexItems(items []Item) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	for _, item := range items {
+		g.itemIndex.move(item.ID(), item.Position())
+	}
+}
+
 // プレイヤーのステータスを更新する
 func (g *Game) UpdatePlayerStatus(playerID PlayerID, status PlayerStatus) *Player {
 	g.mu.Lock()
@@ -210,6 +675,136 @@ func (g *Game) UpdatePlayerStatus(playerID PlayerID, status PlayerStatus) *Playe
 	return player
 }
 
+// プレイヤーが倒された時の処理
+// causeは倒した攻撃のアイテム
+// ゲームモードのルールに従って倒したプレイヤーのスコアを加算する
+func (g *Game) onPlayerKilled(victimID PlayerID, killerID PlayerID, cause Item) {
+	g.mu.RLock()
+	victim := g.Players[victimID]
+	killer := g.Players[killerID]
+	g.mu.RUnlock()
+
+	if victim == nil {
+		return
+	}
+	g.emit(PlayerDied{Player: victim, KillerID: killerID, Cause: cause})
+
+	if killer == nil {
+		return
+	}
+	g.match.addScore(killerID, killer.Team(), g.mode.KillScore(killer, victim))
+}
+
+// attackerの攻撃がvictimに当たるかどうか
+// 同じチームへの攻撃はゲームモードがフレンドリーファイアを許可している場合のみ当たる
+func (g *Game) canDamage(attackerID PlayerID, victimID PlayerID) bool {
+	if attackerID == "" || attackerID == victimID || g.mode.FriendlyFire() {
+		return true
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	attacker, ok := g.Players[attackerID]
+	if !ok {
+		return true
+	}
+	victim, ok := g.Players[victimID]
+	if !ok {
+		return true
+	}
+	return attacker.Team() == TeamNone || attacker.Team() != victim.Team()
+}
+
+// プレイヤーのチームを変更する
+// チーム戦の対戦中以外で、チームの人数差が広がらない場合のみ変更できる
+func (g *Game) SwitchTeam(playerID PlayerID, team Team) *Player {
+	g.inputMu.Lock()
+	defer g.inputMu.Unlock()
+	g.recordInput(Input{Type: InputTypeSwitchTeam, PlayerID: playerID, Team: team})
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	player, ok := g.Players[playerID]
+	if !ok {
+		return nil
+	}
+
+	current := player.Team()
+	if current == TeamNone || team == TeamNone || team == current || g.match.IsRunning() {
+		return nil
+	}
+
+	counts := countTeamMembers(g.Players)
+	if counts[team] >= counts[current] {
+		return nil
+	}
+
+	player.setTeam(team)
+	g.emit(PlayerUpdated{Player: player})
+	return player
+}
+
+// AddStepHook Stepのたびに、盤面を更新する前に呼ぶ処理を追加する
+// ロックを取らずに呼ぶので、hookの中からGameのメソッドを呼べる
+func (g *Game) AddStepHook(hook func()) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.stepHooks = append(g.stepHooks, hook)
+}
+
+// AddStepObserver Stepの最後に、そのStepで受け付けた操作と起きたイベントを渡して呼ぶ処理を追加する
+// ロックを取らずに呼ぶので、observerの中からGameのメソッドを呼べる
+func (g *Game) AddStepObserver(observer func(StepResult)) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.stepObservers = append(g.stepObservers, observer)
+}
+
+// ゲームモードを取得する
+func (g *Game) Mode() GameMode {
+	return g.mode
+}
+
+// マッチの状態を取得する
+func (g *Game) Match() *Match {
+	return g.match
+}
+
+// ToSharedMatchState マッチの状態を盤面の大きさと合わせてshared.MatchStateに変換する
+func (g *Game) ToSharedMatchState() *shared.MatchState {
+	state := g.match.ToSharedMatchState()
+	state.BoardWidth = int32(g.Width)
+	state.BoardHeight = int32(g.Height)
+	state.TicksPerSecond = int32(g.ticksPerSecond)
+	return state
+}
+
+// 盤面に配置する障害物の設定を取得する
+func (g *Game) Arena() ArenaConfig {
+	return g.arena
+}
+
+// 乱数のシードを取得する
+func (g *Game) Seed() int64 {
+	return g.seed
+}
+
+// 中心からの距離がradius以下の位置にあるアイテムを取得する
+// プレイヤーの周りの状態だけを配信する関心領域管理に使う
+func (g *Game) ItemsInRadius(center Position, radius int) []Item {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.itemIndex.inRadius(center, radius)
+}
+
+// 中心からの距離がradius以下の位置にいるプレイヤーを取得する
+func (g *Game) PlayersInRadius(center Position, radius int) []*Player {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.playerIndex.inRadius(center, radius)
+}
+
 // プレイヤー一覧を取得する
 func (g *Game) GetPlayers() map[PlayerID]*Player {
 	g.mu.RLock()
@@ -224,18 +819,44 @@ func (g *Game) GetItems() map[ItemID]Item {
 	return shared.CopyMap(g.Items)
 }
 
-// 削除されたアイテム一覧を取得する
-func (g *Game) GetRemovedItems() map[ItemID]Item {
+// IDの順に並べたアイテム一覧を取得する
+func (g *Game) getSortedItems() []Item {
 	g.mu.RLock()
 	defer g.mu.RUnlock()
-	return shared.CopyMap(g.RemovedItems)
+	return g.sortedItemsWithoutLock()
 }
 
-// 削除されたアイテムをクリアする
-func (g *Game) ClearRemovedItem(itemID ItemID) {
-	g.mu.Lock()
-	defer g.mu.Unlock()
-	delete(g.RemovedItems, itemID)
+func (g *Game) sortedItemsWithoutLock() []Item {
+	items := make([]Item, 0, len(g.Items))
+	for _, item := range g.Items {
+		items = append(items, item)
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].ID() < items[j].ID() })
+	return items
+}
+
+// IDの順に並べたプレイヤー一覧を取得する
+func (g *Game) sortedPlayersWithoutLock() []*Player {
+	players := make([]*Player, 0, len(g.Players))
+	for _, player := range g.Players {
+		players = append(players, player)
+	}
+	sort.Slice(players, func(i, j int) bool { return players[i].PlayerID < players[j].PlayerID })
+	return players
+}
+
+// 新しいアイテムIDを払い出す
+// アイテムなどのUpdateやOnCollideWithのために必要なprimitive操作
+func (g *Game) newItemID() ItemID {
+	return g.random.newItemID()
+}
+
+// アイテムが盤面にあるかどうか
+func (g *Game) hasItem(itemID ItemID) bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	_, ok := g.Items[itemID]
+	return ok
 }
 
 // アイテムを削除する
@@ -246,15 +867,21 @@ func (g *Game) RemoveItem(itemID ItemID) {
 	if !ok {
 		return
 	}
-	delete(g.Items, itemID)
-	g.RemovedItems[itemID] = item
+	g.removeItemWithoutLock(item)
+}
+
+func (g *Game) removeItemWithoutLock(item Item) {
+	delete(g.Items, item.ID())
+	g.itemIndex.remove(item.ID())
+	g.emit(ItemRemoved{Item: item})
 }
 
 // アイテム追加をLockなしで行う内部メソッド
 func (g *Game) addItemWithoutLock(item Item) {
 	if g.isWithinBounds(item) {
 		g.Items[item.ID()] = item
-		g.AddedItems[item.ID()] = item
+		g.itemIndex.insert(item.ID(), item, item.Position())
+		g.emit(ItemSpawned{Item: item})
 	}
 }
 
@@ -270,14 +897,19 @@ func (g *Game) addItem(item Item) {
 func (g *Game) AddBullet(position Position, direction Direction) ItemID {
 	g.mu.Lock()
 	defer g.mu.Unlock()
-	bullet := NewBullet(ItemID(uuid.New().String()), position, direction)
+	bullet := NewBullet(g.random.newItemID(), position, direction)
 	g.addItemWithoutLock(bullet)
 	return bullet.ID()
 }
 
 // あるプレイヤーから弾を発射する
+// 複数の弾を発射した場合は中央の弾のIDを返す
 // TODO: 追加した時に更新通知する必要がある
 func (g *Game) ShootBullet(playerID PlayerID) ItemID {
+	g.inputMu.Lock()
+	defer g.inputMu.Unlock()
+	g.recordInput(Input{Type: InputTypeShoot, PlayerID: playerID})
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
@@ -291,19 +923,52 @@ func (g *Game) ShootBullet(playerID PlayerID) ItemID {
 		return ItemID("")
 	}
 
-	// プレイヤーの前方に発射する
+	// 対戦中以外は弾を発射できない
+	if !g.match.IsRunning() {
+		return ItemID("")
+	}
+
+	// 発射間隔が空いていない、もしくはリロード中は弾を発射できない
+	if !player.shoot() {
+		return ItemID("")
+	}
+
+	// プレイヤーの前方に、選択中の武器の性能で発射する
+	// 複数の弾を発射する武器は、前方のマスを中心に横に並べて扇状に広げる
 	position := player.FowardPosition()
 	direction := player.Direction()
+	weaponType := player.WeaponType()
+	pellets := weaponType.Spec().Pellets
+	dx, dy := direction.ToVector()
 
-	bullet := NewBullet(ItemID(uuid.New().String()), position, direction)
-	g.addItemWithoutLock(bullet)
+	var centerID ItemID
+	for i := range pellets {
+		spread := i - pellets/2
+		bullet := NewBulletWithWeapon(
+			g.random.newItemID(),
+			Position{X: position.X - dy*spread, Y: position.Y + dx*spread},
+			direction,
+			weaponType,
+			spread,
+		)
+		bullet.ownerID = playerID
+		bullet.moveTick = g.weapon.bulletMoveTicks(weaponType)
+		g.addItemWithoutLock(bullet)
+		if spread == 0 {
+			centerID = bullet.ID()
+		}
+	}
 
-	return bullet.ID()
+	return centerID
 }
 
 // あるプレイヤーからボムを設置する
 // TODO: 追加した時に更新通知する必要がある
 func (g *Game) PlaceBomb(playerID PlayerID) ItemID {
+	g.inputMu.Lock()
+	defer g.inputMu.Unlock()
+	g.recordInput(Input{Type: InputTypePlaceBomb, PlayerID: playerID})
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
@@ -317,13 +982,57 @@ func (g *Game) PlaceBomb(playerID PlayerID) ItemID {
 		return ""
 	}
 
+	// 対戦中以外はボムを設置できない
+	if !g.match.IsRunning() {
+		return ""
+	}
+
+	// 同時に設置できる数を超えてボムを設置できない
+	if !player.canPlaceBomb(g.countActiveBombsWithoutLock()[playerID]) {
+		return ""
+	}
+
 	// プレイヤーの位置にボムを設置
-	bomb := NewBomb(ItemID(uuid.New().String()), player.Position())
+	bomb := NewBomb(g.random.newItemID(), player.Position(), playerID, player.BombRange(), g.timings.bombExplosion, g.timings.bombFire)
 	g.addItemWithoutLock(bomb)
 
 	return bomb.ID()
 }
 
+// プレイヤーの武器を持ち替える
+func (g *Game) SelectWeapon(playerID PlayerID, weaponType WeaponType) *Player {
+	g.inputMu.Lock()
+	defer g.inputMu.Unlock()
+	g.recordInput(Input{Type: InputTypeSelectWeapon, PlayerID: playerID, WeaponType: weaponType})
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	player, ok := g.Players[playerID]
+	if !ok {
+		return nil
+	}
+	player.selectWeapon(weaponType)
+	return player
+}
+
+// プレイヤーの弾のリロードを始める
+func (g *Game) Reload(playerID PlayerID) *Player {
+	g.inputMu.Lock()
+	defer g.inputMu.Unlock()
+	g.recordInput(Input{Type: InputTypeReload, PlayerID: playerID})
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	player, ok := g.Players[playerID]
+	if !ok {
+		return nil
+	}
+	player.reload()
+	return player
+}
+
 // GetState ゲームの状態をデバッグ用に表示する
 func (g *Game) String() string {
 	g.mu.RLock()
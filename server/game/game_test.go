@@ -2,12 +2,41 @@ package game
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/shibayu36/terminal-shooter/shared"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// testTimings 初期値の1秒あたりのtick数で、実時間で決めている長さをtick数に直したもの
+func testTimings() timings {
+	return newTimings(TicksPerSecond)
+}
+
+// newTestBomb 初期値の範囲と時間で爆発する、誰も設置していないボムを作る
+func newTestBomb(id ItemID, position Position) *Bomb {
+	return NewBomb(id, position, "", BombFireRange, testTimings().bombExplosion, testTimings().bombFire)
+}
+
+// newTestBombFire 初期値の時間で消える爆発の火を作る
+func newTestBombFire(id ItemID, position Position) *BombFire {
+	return NewBombFire(id, position, testTimings().bombFire)
+}
+
+// newTestPowerUp 初期値の寿命と効果の持続時間のパワーアップを作る
+func newTestPowerUp(id ItemID, itemType ItemType, position Position) *PowerUp {
+	effectTicks := testTimings().powerUpEffect
+	if itemType == ItemTypeShield {
+		effectTicks = testTimings().shieldEffect
+	}
+	return NewPowerUp(id, itemType, position, testTimings().powerUpLifetime, effectTicks)
+}
+
 func Test_Game(t *testing.T) {
 	t.Run("プレイヤーを追加できる", func(t *testing.T) {
 		game := NewGame(30, 30)
@@ -37,6 +66,19 @@ func Test_Game(t *testing.T) {
 		assert.Equal(t, 0, game.GetPlayers()["player2"].Position().X)
 	})
 
+	t.Run("同じIDのプレイヤーは上書きしない", func(t *testing.T) {
+		game := NewGame(30, 30)
+
+		assert.True(t, game.AddPlayer("player1"))
+		game.MovePlayer("player1", Position{X: 2, Y: 8}, DirectionRight)
+		player := game.GetPlayers()["player1"]
+
+		assert.False(t, game.AddPlayer("player1"))
+		assert.Len(t, game.GetPlayers(), 1)
+		assert.Same(t, player, game.GetPlayers()["player1"])
+		assert.Equal(t, Position{X: 2, Y: 8}, player.Position())
+	})
+
 	t.Run("弾を追加できる", func(t *testing.T) {
 		game := NewGame(30, 30)
 
@@ -103,7 +145,6 @@ func Test_Game_StartUpdateLoop(t *testing.T) {
 
 func Test_Game_update(t *testing.T) {
 	t.Run("弾が動く", func(t *testing.T) {
-		updatedCh := make(chan UpdatedResult, 10)
 		game := NewGame(30, 30)
 
 		playerID := PlayerID("player1")
@@ -113,34 +154,30 @@ func Test_Game_update(t *testing.T) {
 		// 弾を追加
 		bulletID1 := game.ShootBullet(playerID)
 		// 2回動かす
-		game.update(updatedCh)
-		game.update(updatedCh)
-		assert.Len(t, updatedCh, 1, "弾の追加が通知されている")
+		events := stepN(game, 2)
+		assert.Contains(t, eventTypes(events), EventTypeItemSpawned, "弾の追加が通知されている")
+		assert.NotContains(t, eventTypes(events), EventTypeItemMoved, "弾はまだ動かない")
 
 		// 弾をもう一つ追加
 		game.MovePlayer(playerID, Position{X: 1, Y: 3}, DirectionUp)
 		bulletID2 := game.ShootBullet(playerID)
-		game.update(updatedCh)
-		assert.Len(t, updatedCh, 2, "弾の追加が通知されている")
+		events = game.Step()
+		assert.Equal(t, []EventType{EventTypePlayerMoved, EventTypeItemSpawned}, eventTypes(events), "弾の追加が通知されている")
 
 		// あと27回動かすと、bullet1だけ動く
-		for range 27 {
-			game.update(updatedCh)
-		}
+		events = stepN(game, 27)
 		assert.Equal(t, Position{X: 2, Y: 8}, game.Items[bulletID1].Position())
 		assert.Equal(t, Position{X: 1, Y: 2}, game.Items[bulletID2].Position())
-		assert.Len(t, updatedCh, 3, "弾の追加x2 + 1回の更新で3件")
+		assert.Equal(t, []Event{ItemMoved{Item: game.Items[bulletID1]}}, events)
 
 		// さらに2回動かすと、bullet2が動く
-		game.update(updatedCh)
-		game.update(updatedCh)
+		events = stepN(game, 2)
 		assert.Equal(t, Position{X: 2, Y: 8}, game.Items[bulletID1].Position())
 		assert.Equal(t, Position{X: 1, Y: 1}, game.Items[bulletID2].Position())
-		assert.Len(t, updatedCh, 4, "弾の追加x2 + 2回の更新で4件")
+		assert.Equal(t, []Event{ItemMoved{Item: game.Items[bulletID2]}}, events)
 	})
 
 	t.Run("ボムを配置して180回更新すると爆発する", func(t *testing.T) {
-		updatedCh := make(chan UpdatedResult, 10)
 		game := NewGame(30, 30)
 
 		playerID := PlayerID("player1")
@@ -151,7 +188,7 @@ func Test_Game_update(t *testing.T) {
 		assert.NotEmpty(t, bombID)
 
 		for range 179 {
-			game.update(updatedCh)
+			game.Step()
 		}
 		// まだ爆発前
 		bomb, ok := game.Items[bombID].(*Bomb)
@@ -159,41 +196,34 @@ func Test_Game_update(t *testing.T) {
 		assert.Equal(t, Position{X: 5, Y: 8}, bomb.Position())
 
 		// 180回目に爆発する
-		game.update(updatedCh)
-		game.update(updatedCh)
+		events := stepN(game, 2)
 		assert.Len(t, game.GetItems(), 17, "爆発したので17個のBombFireが残っている")
-		assert.Len(t, game.GetRemovedItems(), 1)
-		assert.NotEmpty(t, game.GetRemovedItems()[bombID])
+		assert.Equal(t, []ItemID{bombID}, removedItemIDs(events))
+		assert.Contains(t, events, Event(BombExploded{Bomb: bomb}))
 
 		// 火の位置にプレイヤーがいるのでプレイヤーは死ぬ
 		assert.Equal(t, PlayerStatusDead, game.GetPlayers()[playerID].Status())
 	})
 
 	t.Run("アイテムが盤面外に出たら削除される", func(t *testing.T) {
-		updatedCh := make(chan UpdatedResult, 10)
 		game := NewGame(30, 30)
 
 		bulletID := game.AddBullet(Position{X: 1, Y: 0}, DirectionLeft)
 
 		// 30回更新したタイミングではまだ盤面上
 		for range 30 {
-			game.update(updatedCh)
+			game.Step()
 		}
 		assert.Len(t, game.GetItems(), 1)
 		assert.Equal(t, Position{X: 0, Y: 0}, game.GetItems()[bulletID].Position())
 
 		// さらに30回更新したら盤面外に出るので削除される
-		for range 30 {
-			game.update(updatedCh)
-		}
+		events := stepN(game, 30)
 		assert.Empty(t, game.GetItems())
-		assert.Len(t, game.GetRemovedItems(), 1)
-		assert.NotEmpty(t, game.GetRemovedItems()[bulletID])
+		assert.Equal(t, []ItemID{bulletID}, removedItemIDs(events))
 	})
 
-	t.Run("プレイヤーと弾が衝突するとプレイヤーがdeadになり、弾は消え、更新が通知される", func(t *testing.T) {
-		updatedCh := make(chan UpdatedResult, 10)
-
+	t.Run("プレイヤーと弾が衝突するとプレイヤーのHPが減り、弾は消え、更新が通知される", func(t *testing.T) {
 		game := NewGame(30, 30)
 
 		playerID := PlayerID("player1")
@@ -203,30 +233,297 @@ func Test_Game_update(t *testing.T) {
 		// 当たるように移動しておく
 		game.MovePlayer(playerID, Position{X: 4, Y: 3}, DirectionRight)
 
-		game.update(updatedCh)
+		events := game.Step()
 
 		// まだ衝突していない
 		assert.Equal(t, PlayerStatusAlive, game.GetPlayers()[playerID].Status())
 		assert.Len(t, game.GetItems(), 1)
-		assert.Empty(t, game.GetRemovedItems())
-		assert.Len(t, updatedCh, 1, "弾の追加のみが通知される")
+		assert.Empty(t, removedItemIDs(events))
+		assert.Contains(t, eventTypes(events), EventTypeItemSpawned, "弾の追加が通知される")
 
 		// 29回動くと弾が当たる
-		for range 29 {
-			game.update(updatedCh)
-		}
-		assert.Equal(t, PlayerStatusDead, game.GetPlayers()[playerID].Status())
+		events = stepN(game, 29)
+		player := game.GetPlayers()[playerID]
+		assert.Equal(t, PlayerStatusAlive, player.Status())
+		assert.Equal(t, MaxHP-BulletDamage, player.HP())
 		assert.Empty(t, game.GetItems())
-		assert.Len(t, game.GetRemovedItems(), 1)
-		assert.NotEmpty(t, game.GetRemovedItems()[bulletID])
-		assert.Len(t, updatedCh, 3, "弾の追加、弾の更新とプレイヤーの更新の3件が通知される")
+		assert.Equal(t, []ItemID{bulletID}, removedItemIDs(events))
+		assert.Contains(t, events, Event(PlayerUpdated{Player: player}), "プレイヤーの更新が通知される")
 	})
 }
 
-func Test_Game_update_checkCollisions(t *testing.T) {
-	t.Run("弾がプレイヤーに当たったらプレイヤーがdeadになり、弾は消える", func(t *testing.T) {
-		updatedCh := make(chan UpdatedResult, 10)
+func Test_Game_AddStepHook(t *testing.T) {
+	game := NewGame(30, 30)
+	game.AddPlayer("player1")
+	game.MovePlayer("player1", Position{X: 5, Y: 5}, DirectionRight)
+	game.Step()
+
+	// フックの中で行った操作は、同じStepで盤面に反映される
+	calls := 0
+	game.AddStepHook(func() {
+		calls++
+		game.ShootBullet("player1")
+	})
+
+	events := game.Step()
+	assert.Equal(t, 1, calls)
+	assert.Len(t, game.GetItems(), 1)
+	assert.Contains(t, eventTypes(events), EventTypeItemSpawned)
+
+	game.Step()
+	assert.Equal(t, 2, calls)
+}
+
+func Test_Game_AddStepObserver(t *testing.T) {
+	game := NewGame(30, 30)
+
+	var results []StepResult
+	game.AddStepObserver(func(result StepResult) {
+		results = append(results, result)
+	})
+
+	game.AddPlayer("player1")
+	game.MovePlayer("player1", Position{X: 5, Y: 5}, DirectionRight)
+	events := game.Step()
+
+	// Stepの前に受け付けた操作と、そのStepで返したイベントが渡される
+	assert.Len(t, results, 1)
+	assert.Equal(t, int64(1), results[0].Tick)
+	assert.Equal(t, []Input{
+		{Type: InputTypeJoin, PlayerID: "player1"},
+		{Type: InputTypeMove, PlayerID: "player1", Position: Position{X: 5, Y: 5}, Direction: DirectionRight},
+	}, results[0].Inputs)
+	assert.Equal(t, events, results[0].Events)
+
+	// 受け付けられなかった操作も記録される
+	game.SelectWeapon("player1", WeaponTypeShotgun)
+	game.ShootBullet("unknown")
+	game.Step()
+	assert.Len(t, results, 2)
+	assert.Equal(t, int64(2), results[1].Tick)
+	assert.Equal(t, []Input{
+		{Type: InputTypeSelectWeapon, PlayerID: "player1", WeaponType: WeaponTypeShotgun},
+		{Type: InputTypeShoot, PlayerID: "unknown"},
+	}, results[1].Inputs)
+
+	game.Step()
+	assert.Empty(t, results[2].Inputs)
+}
+
+// applyInput 記録された操作をゲームに反映する
+func applyInput(game *Game, input Input) {
+	switch input.Type {
+	case InputTypeJoin:
+		if input.DisplayName == "" {
+			game.AddPlayer(input.PlayerID)
+		} else {
+			_, _ = game.JoinPlayer(input.PlayerID, input.DisplayName)
+		}
+	case InputTypeLeave:
+		game.RemovePlayer(input.PlayerID)
+	case InputTypeMove:
+		game.RequestMove(input.PlayerID, input.Position, input.Direction)
+	case InputTypeShoot:
+		game.ShootBullet(input.PlayerID)
+	case InputTypePlaceBomb:
+		game.PlaceBomb(input.PlayerID)
+	case InputTypeSelectWeapon:
+		game.SelectWeapon(input.PlayerID, input.WeaponType)
+	case InputTypeReload:
+		game.Reload(input.PlayerID)
+	case InputTypeSwitchTeam:
+		game.SwitchTeam(input.PlayerID, input.Team)
+	case InputTypeSetDisplayName:
+		_, _ = game.SetDisplayName(input.PlayerID, input.DisplayName)
+	}
+}
+
+// 盤面の状態を比較できる文字列にする
+func gameSnapshot(game *Game) []string {
+	var states []string
+	for _, item := range game.getSortedItems() {
+		states = append(states, fmt.Sprintf("%s %s %v", item.ID(), item.Type(), item.Position()))
+	}
+	for _, player := range game.GetPlayers() {
+		states = append(states, fmt.Sprintf("%s %s %d %v", player.PlayerID, player.Status(), player.HP(), player.Position()))
+	}
+	sort.Strings(states)
+	return states
+}
+
+func Test_Game_StepResultReplay(t *testing.T) {
+	// Stepと並行に操作しても、記録された操作を各Stepの前に同じ順で反映すれば同じ結果になる
+	config := Config{
+		Width:  20,
+		Height: 20,
+		Arena:  ArenaConfig{PillarInterval: 2, BlockDensity: 0.3, PowerUpChance: 1},
+		Seed:   1,
+	}
+	original := NewGameWithConfig(config)
+
+	var results []StepResult
+	var eventIDs [][]string
+	original.AddStepObserver(func(result StepResult) {
+		results = append(results, result)
+		eventIDs = append(eventIDs, eventSummaries(result.Events))
+	})
+
+	stop := make(chan struct{})
+	stepped := make(chan struct{})
+	go func() {
+		defer close(stepped)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				original.Step()
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			playerID := PlayerID(fmt.Sprintf("player%d", i))
+			original.AddPlayer(playerID)
+			for j := range 200 {
+				switch j % 4 {
+				case 0:
+					direction := []Direction{DirectionRight, DirectionDown, DirectionLeft, DirectionUp}[j/4%4]
+					dx, dy := direction.ToVector()
+					position := original.GetPlayers()[playerID].Position()
+					original.RequestMove(playerID, Position{X: position.X + dx, Y: position.Y + dy}, direction)
+				case 1:
+					original.ShootBullet(playerID)
+				case 2:
+					original.PlaceBomb(playerID)
+				case 3:
+					original.SelectWeapon(playerID, []WeaponType{WeaponTypePistol, WeaponTypeRifle, WeaponTypeShotgun, WeaponTypePiercing}[j/4%4])
+				}
+			}
+			original.RemovePlayer(playerID)
+		}()
+	}
+	wg.Wait()
+	close(stop)
+	<-stepped
+	// 最後のStepの後に受け付けた操作を記録する
+	original.Step()
+
+	replayed := NewGameWithConfig(config)
+	for i, result := range results {
+		for _, input := range result.Inputs {
+			applyInput(replayed, input)
+		}
+		require.Equal(t, eventIDs[i], eventSummaries(replayed.Step()), "tick %d", result.Tick)
+	}
+	assert.Equal(t, gameSnapshot(original), gameSnapshot(replayed))
+}
 
+// eventSummaries イベントを、後から変わらない種類と対象のIDだけの文字列にする
+func eventSummaries(events []Event) []string {
+	summaries := make([]string, 0, len(events))
+	for _, event := range events {
+		var id string
+		switch event := event.(type) {
+		case PlayerJoined:
+			id = string(event.Player.PlayerID)
+		case PlayerLeft:
+			id = string(event.Player.PlayerID)
+		case PlayerMoved:
+			id = string(event.Player.PlayerID)
+		case PlayerUpdated:
+			id = string(event.Player.PlayerID)
+		case PlayerDied:
+			id = string(event.Player.PlayerID)
+		case ItemSpawned:
+			id = string(event.Item.ID())
+		case ItemMoved:
+			id = string(event.Item.ID())
+		case ItemRemoved:
+			id = string(event.Item.ID())
+		}
+		summaries = append(summaries, fmt.Sprintf("%s %s", event.Type(), id))
+	}
+	return summaries
+}
+
+func Test_Game_ToSharedMatchState(t *testing.T) {
+	game := NewGame(20, 10)
+
+	state := game.ToSharedMatchState()
+	assert.Equal(t, shared.MatchPhase_RUNNING, state.GetPhase())
+	assert.EqualValues(t, 20, state.GetBoardWidth())
+	assert.EqualValues(t, 10, state.GetBoardHeight())
+	assert.EqualValues(t, TicksPerSecond, state.GetTicksPerSecond())
+
+	// 1秒あたりのtick数を変えると、残り時間はそのtick数で秒に直される
+	game = NewGameWithConfig(Config{
+		Width:          20,
+		Height:         10,
+		Match:          MatchConfig{MinPlayers: 0, CountdownTicks: 0, TimeLimitTicks: 90, ScoreLimit: 0, ResultTicks: 0},
+		TicksPerSecond: 30,
+	})
+	state = game.ToSharedMatchState()
+	assert.EqualValues(t, 30, state.GetTicksPerSecond())
+	assert.EqualValues(t, 3, state.GetRemainingSeconds())
+}
+
+func Test_Game_Step(t *testing.T) {
+	// 同じ操作を同じシードのゲームに行い、盤面の状態を文字列にして返す
+	play := func(seed int64) []string {
+		game := NewGameWithConfig(Config{
+			Width:  30,
+			Height: 30,
+			Arena:  ArenaConfig{PillarInterval: 2, BlockDensity: 0.3, PowerUpChance: 1},
+			Seed:   seed,
+		})
+		game.AddPlayer("player1")
+		game.AddPlayer("player2")
+		game.MovePlayer("player1", Position{X: 0, Y: 0}, DirectionRight)
+		game.MovePlayer("player2", Position{X: 0, Y: 10}, DirectionDown)
+
+		for i := range 300 {
+			if i%60 == 0 {
+				game.ShootBullet("player1")
+				game.PlaceBomb("player2")
+			}
+			game.Step()
+		}
+
+		var states []string
+		for _, item := range game.getSortedItems() {
+			states = append(states, fmt.Sprintf("%s %s %v", item.ID(), item.Type(), item.Position()))
+		}
+		for _, player := range game.GetPlayers() {
+			states = append(states, fmt.Sprintf("%s %s %d %v", player.PlayerID, player.Status(), player.HP(), player.Position()))
+		}
+		sort.Strings(states)
+		return states
+	}
+
+	t.Run("同じシードなら同じ結果になる", func(t *testing.T) {
+		assert.Equal(t, play(1), play(1))
+	})
+
+	t.Run("シードが違えば盤面が変わる", func(t *testing.T) {
+		assert.NotEqual(t, play(1), play(2))
+	})
+
+	t.Run("更新されたものを返す", func(t *testing.T) {
+		game := NewGame(30, 30)
+		assert.Empty(t, game.Step())
+
+		bulletID := game.AddBullet(Position{X: 5, Y: 5}, DirectionRight)
+		assert.Equal(t, []Event{ItemSpawned{Item: game.Items[bulletID]}}, game.Step())
+	})
+}
+
+func Test_Game_update_checkCollisions(t *testing.T) {
+	t.Run("弾がプレイヤーに当たったらHPが減り、弾は消える", func(t *testing.T) {
 		game := NewGame(30, 30)
 
 		playerID := PlayerID("player1")
@@ -234,21 +531,63 @@ func Test_Game_update_checkCollisions(t *testing.T) {
 		game.MovePlayer(playerID, Position{X: 2, Y: 3}, DirectionRight)
 		bulletID := game.AddBullet(Position{X: 1, Y: 3}, DirectionRight)
 
-		game.update(updatedCh)
+		events := game.Step()
 
 		// まだ衝突していない
 		assert.Equal(t, PlayerStatusAlive, game.GetPlayers()[playerID].Status())
 		assert.Len(t, game.GetItems(), 1)
-		assert.Empty(t, game.GetRemovedItems())
+		assert.Empty(t, removedItemIDs(events))
 
 		// 29回動くと弾が当たる
-		for range 29 {
-			game.update(updatedCh)
+		events = stepN(game, 29)
+		assert.Equal(t, PlayerStatusAlive, game.GetPlayers()[playerID].Status())
+		assert.Equal(t, MaxHP-BulletDamage, game.GetPlayers()[playerID].HP())
+		assert.Empty(t, game.GetItems())
+		assert.Equal(t, []ItemID{bulletID}, removedItemIDs(events))
+	})
+
+	t.Run("HPが0になるまで弾が当たるとプレイヤーがdeadになる", func(t *testing.T) {
+		game := NewGame(30, 30)
+
+		playerID := PlayerID("player1")
+		game.AddPlayer(playerID)
+		game.MovePlayer(playerID, Position{X: 2, Y: 3}, DirectionRight)
+
+		for range MaxHP / BulletDamage {
+			game.AddBullet(Position{X: 2, Y: 3}, DirectionRight)
+			game.Step()
 		}
 		assert.Equal(t, PlayerStatusDead, game.GetPlayers()[playerID].Status())
-		assert.Empty(t, game.GetItems())
-		assert.Len(t, game.GetRemovedItems(), 1)
-		assert.NotEmpty(t, game.GetRemovedItems()[bulletID])
+		assert.Equal(t, 0, game.GetPlayers()[playerID].HP())
+	})
+
+	t.Run("爆発の火によるダメージは1回の爆発につき1度だけ受ける", func(t *testing.T) {
+		game := NewGame(30, 30)
+
+		playerID := PlayerID("player1")
+		game.AddPlayer(playerID)
+		game.MovePlayer(playerID, Position{X: 2, Y: 3}, DirectionRight)
+		game.GetPlayers()[playerID].hp = MaxHP + BombFireDamage
+
+		explosion := newExplosion()
+		for id, pos := range map[ItemID]Position{"fire1": {X: 2, Y: 3}, "fire2": {X: 3, Y: 3}} {
+			fire := newTestBombFire(id, pos)
+			fire.explosion = explosion
+			game.addItem(fire)
+		}
+
+		game.Step()
+		assert.Equal(t, MaxHP, game.GetPlayers()[playerID].HP())
+
+		// 同じ爆発の火の上を移動してもダメージを受けない
+		game.MovePlayer(playerID, Position{X: 3, Y: 3}, DirectionRight)
+		game.Step()
+		assert.Equal(t, MaxHP, game.GetPlayers()[playerID].HP())
+
+		// 別の爆発の火ではダメージを受ける
+		game.addItem(newTestBombFire("another", Position{X: 3, Y: 3}))
+		game.Step()
+		assert.Equal(t, PlayerStatusDead, game.GetPlayers()[playerID].Status())
 	})
 }
 
@@ -300,6 +639,86 @@ func Test_Game_detectCollisions(t *testing.T) {
 	})
 }
 
+func Test_Game_update_itemCollisions(t *testing.T) {
+	t.Run("爆発の火に触れた弾は消える", func(t *testing.T) {
+		game := NewGame(30, 30)
+
+		game.addItem(newTestBombFire("fire1", Position{X: 5, Y: 5}))
+		bulletID := game.AddBullet(Position{X: 5, Y: 5}, DirectionRight)
+
+		events := game.Step()
+
+		items := game.GetItems()
+		assert.NotContains(t, items, bulletID)
+		assert.Contains(t, items, ItemID("fire1"), "火は消えない")
+		assert.Equal(t, []ItemID{bulletID}, removedItemIDs(events))
+	})
+
+	t.Run("同じ位置に入った弾同士は相殺される", func(t *testing.T) {
+		game := NewGame(30, 30)
+
+		bulletID1 := game.AddBullet(Position{X: 4, Y: 5}, DirectionRight)
+		bulletID2 := game.AddBullet(Position{X: 6, Y: 5}, DirectionLeft)
+		bulletID3 := game.AddBullet(Position{X: 4, Y: 6}, DirectionRight)
+
+		for range 30 {
+			game.Step()
+		}
+
+		items := game.GetItems()
+		assert.NotContains(t, items, bulletID1)
+		assert.NotContains(t, items, bulletID2)
+		assert.Contains(t, items, bulletID3)
+	})
+
+	t.Run("隣り合った弾が正面からすれ違うと相殺される", func(t *testing.T) {
+		game := NewGame(30, 30)
+
+		bulletID1 := game.AddBullet(Position{X: 4, Y: 5}, DirectionRight)
+		bulletID2 := game.AddBullet(Position{X: 5, Y: 5}, DirectionLeft)
+
+		for range 30 {
+			game.Step()
+		}
+
+		items := game.GetItems()
+		assert.NotContains(t, items, bulletID1)
+		assert.NotContains(t, items, bulletID2)
+	})
+
+	t.Run("誘爆した火でもプレイヤーはダメージを受ける", func(t *testing.T) {
+		game := NewGame(30, 30)
+
+		playerID := PlayerID("player1")
+		game.AddPlayer(playerID)
+		game.MovePlayer(playerID, Position{X: 8, Y: 12}, DirectionRight)
+
+		bomb1 := newTestBomb("bomb1", Position{X: 5, Y: 8})
+		bomb1.tick = testTimings().bombExplosion - 1
+		game.addItem(bomb1)
+		game.addItem(newTestBomb("bomb2", Position{X: 8, Y: 8}))
+
+		game.Step()
+
+		assert.Equal(t, PlayerStatusDead, game.GetPlayers()[playerID].Status())
+	})
+}
+
+func Test_Game_detectItemCollisions(t *testing.T) {
+	game := NewGame(30, 30)
+
+	game.addItem(newTestBomb("bomb1", Position{X: 2, Y: 3}))
+	game.addItem(newTestBombFire("fire1", Position{X: 2, Y: 3}))
+	game.addItem(newTestBomb("bomb2", Position{X: 5, Y: 5}))
+
+	collisions := game.detectItemCollisions()
+	assert.Len(t, collisions, 1)
+	assert.ElementsMatch(t,
+		[]ItemID{"bomb1", "fire1"},
+		[]ItemID{collisions[0].A.ID(), collisions[0].B.ID()},
+	)
+}
+
 func Test_Game_isWithinBounds(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -353,23 +772,14 @@ func Test_Game_ItemsOperation(t *testing.T) {
 		bulletID2: bullet2,
 	}, items)
 
-	// RemovedItemsにはbullet1とbullet3が残っている
-	removedItems := game.GetRemovedItems()
-	assert.Len(t, removedItems, 2)
-	assert.Equal(t, map[ItemID]Item{
-		bulletID1: bullet1,
-		bulletID3: bullet3,
-	}, removedItems)
-
-	// ClearRemovedItemでbullet1のみ削除する
-	game.ClearRemovedItem(bulletID1)
-
-	// RemovedItemsにはbullet1のみ残っている
-	removedItems = game.GetRemovedItems()
-	assert.Len(t, removedItems, 1)
-	assert.Equal(t, map[ItemID]Item{
-		bulletID3: bullet3,
-	}, removedItems)
+	// 追加と削除がイベントとして通知される
+	assert.Equal(t, []Event{
+		ItemSpawned{Item: bullet1},
+		ItemSpawned{Item: bullet2},
+		ItemSpawned{Item: bullet3},
+		ItemRemoved{Item: bullet1},
+		ItemRemoved{Item: bullet3},
+	}, game.events.drain())
 }
 
 func Test_Game_MovePlayer(t *testing.T) {
@@ -478,3 +888,62 @@ func Test_Game_PlaceBomb(t *testing.T) {
 		assert.Empty(t, bombID)
 	})
 }
+
+func Test_Game_Match(t *testing.T) {
+	newMatchGame := func() *Game {
+		return NewGameWithConfig(Config{
+			Width:  30,
+			Height: 30,
+			Match:  MatchConfig{MinPlayers: 2, CountdownTicks: 3, TimeLimitTicks: 0, ScoreLimit: 1, ResultTicks: 3},
+		})
+	}
+
+	t.Run("対戦中以外は弾の発射やボムの設置ができない", func(t *testing.T) {
+		game := newMatchGame()
+
+		playerID := PlayerID("player1")
+		game.AddPlayer(playerID)
+		game.AddPlayer("player2")
+		game.MovePlayer(playerID, Position{X: 5, Y: 5}, DirectionRight)
+
+		assert.Equal(t, MatchPhaseWaiting, game.Match().Phase())
+		assert.Empty(t, game.ShootBullet(playerID))
+		assert.Empty(t, game.PlaceBomb(playerID))
+
+		// カウントダウン中もできない
+		game.Step()
+		assert.Equal(t, MatchPhaseCountdown, game.Match().Phase())
+		assert.Empty(t, game.ShootBullet(playerID))
+
+		for range 3 {
+			game.Step()
+		}
+		assert.Equal(t, MatchPhaseRunning, game.Match().Phase())
+		assert.NotEmpty(t, game.ShootBullet(playerID))
+		assert.NotEmpty(t, game.PlaceBomb(playerID))
+	})
+
+	t.Run("他のプレイヤーを倒すとスコアが加算され、勝利スコアに達すると盤面がリセットされる", func(t *testing.T) {
+		game := newMatchGame()
+
+		shooterID := PlayerID("player1")
+		targetID := PlayerID("player2")
+		game.AddPlayer(shooterID)
+		game.AddPlayer(targetID)
+		for range 4 {
+			game.Step()
+		}
+		assert.Equal(t, MatchPhaseRunning, game.Match().Phase())
+
+		game.MovePlayer(shooterID, Position{X: 2, Y: 3}, DirectionRight)
+		game.MovePlayer(targetID, Position{X: 3, Y: 3}, DirectionLeft)
+		game.GetPlayers()[targetID].hp = BulletDamage
+		game.ShootBullet(shooterID)
+		game.Step()
+
+		assert.Equal(t, MatchPhaseFinished, game.Match().Phase())
+		assert.Equal(t, []PlayerID{shooterID}, game.Match().Winners())
+		assert.Empty(t, game.GetItems(), "盤面のアイテムは削除される")
+		assert.Equal(t, PlayerStatusAlive, game.GetPlayers()[targetID].Status(), "倒されたプレイヤーも復活する")
+	})
+}
@@ -0,0 +1,49 @@
+package game
+
+// InputType ゲームの外から受け付けた操作の種類
+type InputType string
+
+const (
+	InputTypeJoin           InputType = "join"
+	InputTypeLeave          InputType = "leave"
+	InputTypeMove           InputType = "move"
+	InputTypeShoot          InputType = "shoot"
+	InputTypePlaceBomb      InputType = "place_bomb"
+	InputTypeSelectWeapon   InputType = "select_weapon"
+	InputTypeReload         InputType = "reload"
+	InputTypeSwitchTeam     InputType = "switch_team"
+	InputTypeSetDisplayName InputType = "set_display_name"
+)
+
+// Input ゲームの外から受け付けた操作
+// 操作が受け付けられたかどうかに関わらず、要求された内容をそのまま記録する
+type Input struct {
+	Type     InputType
+	PlayerID PlayerID
+
+	// InputTypeMoveの移動先と向き
+	Position  Position  `exhaustruct:"optional"`
+	Direction Direction `exhaustruct:"optional"`
+	// InputTypeSwitchTeamのチーム
+	Team Team `exhaustruct:"optional"`
+	// InputTypeSelectWeaponの武器
+	WeaponType WeaponType `exhaustruct:"optional"`
+	// InputTypeJoinとInputTypeSetDisplayNameの表示名。InputTypeJoinはAddPlayerで追加した場合は空
+	DisplayName string `exhaustruct:"optional"`
+}
+
+// StepResult 1回のStepで起きたこと
+type StepResult struct {
+	// 何回目のStepか。1から始まる
+	Tick int64
+	// 前回のStepからこのStepまでの間に受け付けた操作。受け付けた順に並ぶ
+	Inputs []Input
+	// このStepで返したイベント
+	Events []Event
+}
+
+// recordInput 受け付けた操作を次のStepの結果として記録する
+// 記録した順と反映した順が一致するように、inputMuを取ってから呼び、ロックを取ったまま反映する
+func (g *Game) recordInput(input Input) {
+	g.inputs.push(input)
+}
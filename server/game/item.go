@@ -12,6 +12,17 @@ const (
 	ItemTypeBullet   ItemType = "bullet"
 	ItemTypeBomb     ItemType = "bomb"
 	ItemTypeBombFire ItemType = "bomb_fire"
+
+	// パワーアップアイテム
+	ItemTypeBombCapacityUp ItemType = "bomb_capacity_up"
+	ItemTypeBombRangeUp    ItemType = "bomb_range_up"
+	ItemTypeSpeedUp        ItemType = "speed_up"
+	ItemTypeShield         ItemType = "shield"
+	ItemTypeRapidFire      ItemType = "rapid_fire"
+
+	// 障害物
+	ItemTypeWall  ItemType = "wall"
+	ItemTypeBlock ItemType = "block"
 )
 
 // ToSharedItemType ItemTypeをshared.ItemTypeに変換する
@@ -23,6 +34,20 @@ func (t ItemType) ToSharedItemType() shared.ItemType {
 		return shared.ItemType_BOMB
 	case ItemTypeBombFire:
 		return shared.ItemType_BOMB_FIRE
+	case ItemTypeBombCapacityUp:
+		return shared.ItemType_BOMB_CAPACITY_UP
+	case ItemTypeBombRangeUp:
+		return shared.ItemType_BOMB_RANGE_UP
+	case ItemTypeSpeedUp:
+		return shared.ItemType_SPEED_UP
+	case ItemTypeShield:
+		return shared.ItemType_SHIELD
+	case ItemTypeRapidFire:
+		return shared.ItemType_RAPID_FIRE
+	case ItemTypeWall:
+		return shared.ItemType_WALL
+	case ItemTypeBlock:
+		return shared.ItemType_BLOCK
 	default:
 		panic(fmt.Sprintf("invalid item type: %s", t))
 	}
@@ -35,3 +60,27 @@ type Item interface {
 	Position() Position
 	Update(provider gameOperationProvider) bool
 }
+
+// ownedItem は発射・設置したプレイヤーを持つアイテムを表すインターフェース
+type ownedItem interface {
+	OwnerID() PlayerID
+}
+
+// itemOwnerID アイテムの持ち主を取得する。持ち主がいなければ空文字を返す
+func itemOwnerID(item collidable) PlayerID {
+	if owned, ok := item.(ownedItem); ok {
+		return owned.OwnerID()
+	}
+	return ""
+}
+
+// damager はプレイヤーにダメージを与えるアイテムを表すインターフェース
+type damager interface {
+	Damage() int
+}
+
+// damageTracker は同じプレイヤーに1度だけダメージを与えるアイテムを表すインターフェース
+type damageTracker interface {
+	// ダメージを与えたことを記録する。既に与えていた場合はfalseを返す
+	markDamaged(playerID PlayerID) bool
+}
@@ -0,0 +1,277 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/shibayu36/terminal-shooter/shared"
+)
+
+// MatchPhase マッチの進行フェーズ
+type MatchPhase string
+
+const (
+	MatchPhaseWaiting   MatchPhase = "waiting"   // プレイヤーが揃うのを待っている
+	MatchPhaseCountdown MatchPhase = "countdown" // 開始前のカウントダウン中
+	MatchPhaseRunning   MatchPhase = "running"   // 対戦中
+	MatchPhaseFinished  MatchPhase = "finished"  // 結果表示中
+)
+
+// ToSharedMatchPhase MatchPhaseをshared.MatchPhaseに変換する
+func (mp MatchPhase) ToSharedMatchPhase() shared.MatchPhase {
+	switch mp {
+	case MatchPhaseWaiting:
+		return shared.MatchPhase_WAITING
+	case MatchPhaseCountdown:
+		return shared.MatchPhase_COUNTDOWN
+	case MatchPhaseRunning:
+		return shared.MatchPhase_RUNNING
+	case MatchPhaseFinished:
+		return shared.MatchPhase_FINISHED
+	default:
+		panic(fmt.Sprintf("invalid match phase: %s", mp))
+	}
+}
+
+// MatchConfig マッチの進行ルール
+// ゼロ値の場合は待機もカウントダウンもなく即座に開始し、終了しない
+type MatchConfig struct {
+	// マッチ開始に必要なプレイヤー数
+	MinPlayers int
+	// カウントダウンのtick数
+	CountdownTicks int
+	// 制限時間のtick数。0なら制限なし
+	TimeLimitTicks int
+	// 勝利に必要なスコア。0なら制限なし
+	ScoreLimit int
+	// 結果を表示してから次のマッチを始めるまでのtick数
+	ResultTicks int
+}
+
+// Match 1つのマッチの進行状態を管理する
+type Match struct {
+	config MatchConfig
+	mode   GameMode
+	phase  MatchPhase
+	// 現在のフェーズに入ってからのtick数
+	phaseTick int
+
+	scores map[PlayerID]int
+	// チームごとのスコア。倒した時点のチームに加算するので、プレイヤーが抜けても減らない
+	teamScores map[Team]int
+	winners    []PlayerID
+
+	// 残り時間を秒に直すための1秒あたりのtick数
+	ticksPerSecond int
+
+	mu sync.RWMutex `exhaustruct:"optional"`
+}
+
+func NewMatch(config MatchConfig, mode GameMode, ticksPerSecond int) *Match {
+	return &Match{
+		config:         config,
+		mode:           mode,
+		phase:          MatchPhaseWaiting,
+		phaseTick:      0,
+		scores:         make(map[PlayerID]int),
+		teamScores:     make(map[Team]int),
+		winners:        nil,
+		ticksPerSecond: ticksPerSecond,
+	}
+}
+
+func (m *Match) Phase() MatchPhase {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.phase
+}
+
+// IsRunning 対戦中かどうか
+func (m *Match) IsRunning() bool {
+	return m.Phase() == MatchPhaseRunning
+}
+
+// Scores プレイヤーごとのスコアを取得する
+func (m *Match) Scores() map[PlayerID]int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return shared.CopyMap(m.scores)
+}
+
+// TeamScores チームごとのスコアを取得する
+func (m *Match) TeamScores() map[Team]int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return shared.CopyMap(m.teamScores)
+}
+
+// Winners 直近に終了したマッチの勝者を取得する
+func (m *Match) Winners() []PlayerID {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]PlayerID(nil), m.winners...)
+}
+
+// RemainingTicks 現在のフェーズの残りtick数。制限がないフェーズでは0を返す
+func (m *Match) RemainingTicks() int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.remainingTicksWithoutLock()
+}
+
+func (m *Match) remainingTicksWithoutLock() int {
+	var limit int
+	switch m.phase {
+	case MatchPhaseCountdown:
+		limit = m.config.CountdownTicks
+	case MatchPhaseRunning:
+		limit = m.config.TimeLimitTicks
+	case MatchPhaseFinished:
+		limit = m.config.ResultTicks
+	case MatchPhaseWaiting:
+		return 0
+	}
+	if limit <= 0 {
+		return 0
+	}
+	return max(limit-m.phaseTick, 0)
+}
+
+// remainingSecondsWithoutLock 残り時間を秒単位で切り上げて返す
+func (m *Match) remainingSecondsWithoutLock() int {
+	return ticksToSeconds(m.remainingTicksWithoutLock(), m.ticksPerSecond)
+}
+
+// addScore プレイヤーと、その時点でプレイヤーが所属しているチームのスコアを加算する。対戦中以外は加算しない
+func (m *Match) addScore(playerID PlayerID, team Team, delta int) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.phase != MatchPhaseRunning {
+		return
+	}
+	m.scores[playerID] += delta
+	if team != TeamNone {
+		m.teamScores[team] += delta
+	}
+}
+
+// removePlayer 退出したプレイヤーのスコアを削除する。チームのスコアには残す
+func (m *Match) removePlayer(playerID PlayerID) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.scores, playerID)
+}
+
+// matchTransition advanceによって起きたフェーズ遷移
+type matchTransition struct {
+	// フェーズもしくは残り秒数が変わったか
+	changed bool
+	// 対戦が始まったか
+	started bool
+	// 対戦が終わったか
+	finished bool
+}
+
+// advance 1tick進めて、必要であればフェーズを遷移させる
+func (m *Match) advance(players map[PlayerID]*Player) matchTransition {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	prevPhase := m.phase
+	prevRemaining := m.remainingSecondsWithoutLock()
+
+	m.phaseTick++
+	transition := m.transitionWithoutLock(players)
+
+	transition.changed = prevPhase != m.phase || prevRemaining != m.remainingSecondsWithoutLock()
+	return transition
+}
+
+// transitionWithoutLock 条件を満たす限りフェーズを遷移させる
+// 対戦開始直後は決着の判定をしない
+func (m *Match) transitionWithoutLock(players map[PlayerID]*Player) matchTransition {
+	transition := matchTransition{changed: false, started: false, finished: false}
+	numPlayers := len(players)
+
+	for {
+		switch m.phase {
+		case MatchPhaseWaiting:
+			if numPlayers < m.config.MinPlayers {
+				return transition
+			}
+			m.enterWithoutLock(MatchPhaseCountdown)
+		case MatchPhaseCountdown:
+			if numPlayers < m.config.MinPlayers {
+				m.enterWithoutLock(MatchPhaseWaiting)
+				return transition
+			}
+			if m.phaseTick < m.config.CountdownTicks {
+				return transition
+			}
+			m.scores = make(map[PlayerID]int)
+			m.teamScores = make(map[Team]int)
+			m.winners = nil
+			m.enterWithoutLock(MatchPhaseRunning)
+			transition.started = true
+			return transition
+		case MatchPhaseRunning:
+			timeUp := m.config.TimeLimitTicks > 0 && m.phaseTick >= m.config.TimeLimitTicks
+			winners, decided := m.mode.Judge(players, m.scores, m.teamScores, m.config.ScoreLimit, timeUp)
+			if !decided {
+				return transition
+			}
+			m.winners = winners
+			m.enterWithoutLock(MatchPhaseFinished)
+			transition.finished = true
+		case MatchPhaseFinished:
+			if m.phaseTick < m.config.ResultTicks {
+				return transition
+			}
+			m.enterWithoutLock(MatchPhaseWaiting)
+		}
+	}
+}
+
+func (m *Match) enterWithoutLock(phase MatchPhase) {
+	m.phase = phase
+	m.phaseTick = 0
+}
+
+// ToSharedMatchState マッチの状態をshared.MatchStateに変換する
+func (m *Match) ToSharedMatchState() *shared.MatchState {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	scores := make([]*shared.PlayerScore, 0, len(m.scores))
+	for playerID, score := range m.scores {
+		scores = append(scores, &shared.PlayerScore{
+			PlayerId: string(playerID),
+			Score:    int32(score),
+		})
+	}
+	sort.Slice(scores, func(i, j int) bool { return scores[i].GetPlayerId() < scores[j].GetPlayerId() })
+
+	teamScores := make([]*shared.TeamScore, 0, len(m.teamScores))
+	for team, score := range m.teamScores {
+		teamScores = append(teamScores, &shared.TeamScore{
+			Team:  team.ToSharedTeam(),
+			Score: int32(score),
+		})
+	}
+	sort.Slice(teamScores, func(i, j int) bool { return teamScores[i].GetTeam() < teamScores[j].GetTeam() })
+
+	winnerIDs := make([]string, 0, len(m.winners))
+	for _, winner := range m.winners {
+		winnerIDs = append(winnerIDs, string(winner))
+	}
+
+	return &shared.MatchState{
+		Mode:             m.mode.Name().ToSharedGameMode(),
+		Phase:            m.phase.ToSharedMatchPhase(),
+		RemainingSeconds: int32(m.remainingSecondsWithoutLock()),
+		Scores:           scores,
+		TeamScores:       teamScores,
+		WinnerIds:        winnerIDs,
+	}
+}
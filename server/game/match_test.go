@@ -0,0 +1,142 @@
+package game
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/shibayu36/terminal-shooter/shared"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Match(t *testing.T) {
+	t.Run("プレイヤーが揃うとカウントダウンを経て対戦が始まる", func(t *testing.T) {
+		match := NewMatch(MatchConfig{MinPlayers: 2, CountdownTicks: 3, TimeLimitTicks: 0, ScoreLimit: 0, ResultTicks: 0}, &Deathmatch{}, TicksPerSecond)
+		assert.Equal(t, MatchPhaseWaiting, match.Phase())
+
+		// 1人では始まらない
+		match.advance(newTestPlayers(1))
+		assert.Equal(t, MatchPhaseWaiting, match.Phase())
+
+		// 2人揃うとカウントダウン
+		transition := match.advance(newTestPlayers(2))
+		assert.True(t, transition.changed)
+		assert.Equal(t, MatchPhaseCountdown, match.Phase())
+		assert.Equal(t, 3, match.RemainingTicks())
+
+		match.advance(newTestPlayers(2))
+		match.advance(newTestPlayers(2))
+		assert.Equal(t, MatchPhaseCountdown, match.Phase())
+
+		transition = match.advance(newTestPlayers(2))
+		assert.True(t, transition.started)
+		assert.Equal(t, MatchPhaseRunning, match.Phase())
+	})
+
+	t.Run("カウントダウン中に人数が減ると待機に戻る", func(t *testing.T) {
+		match := NewMatch(MatchConfig{MinPlayers: 2, CountdownTicks: 3, TimeLimitTicks: 0, ScoreLimit: 0, ResultTicks: 0}, &Deathmatch{}, TicksPerSecond)
+		match.advance(newTestPlayers(2))
+		assert.Equal(t, MatchPhaseCountdown, match.Phase())
+
+		match.advance(newTestPlayers(1))
+		assert.Equal(t, MatchPhaseWaiting, match.Phase())
+	})
+
+	t.Run("制限時間に達すると終了し、結果表示の後に次のマッチが始まる", func(t *testing.T) {
+		match := NewMatch(MatchConfig{MinPlayers: 1, CountdownTicks: 0, TimeLimitTicks: 2, ScoreLimit: 0, ResultTicks: 2}, &Deathmatch{}, TicksPerSecond)
+		match.advance(newTestPlayers(1))
+		assert.Equal(t, MatchPhaseRunning, match.Phase())
+
+		match.addScore("player1", TeamNone, 2)
+		match.addScore("player2", TeamNone, 1)
+
+		match.advance(newTestPlayers(1))
+		transition := match.advance(newTestPlayers(1))
+		assert.True(t, transition.finished)
+		assert.Equal(t, MatchPhaseFinished, match.Phase())
+		assert.Equal(t, []PlayerID{"player1"}, match.Winners())
+
+		// 結果表示中はスコアが加算されない
+		match.addScore("player2", TeamNone, 5)
+		assert.Equal(t, 1, match.Scores()["player2"])
+
+		match.advance(newTestPlayers(1))
+		transition = match.advance(newTestPlayers(1))
+		assert.True(t, transition.started)
+		assert.Equal(t, MatchPhaseRunning, match.Phase())
+		assert.Empty(t, match.Scores(), "新しいマッチではスコアがリセットされる")
+	})
+
+	t.Run("勝利スコアに達すると終了する", func(t *testing.T) {
+		match := NewMatch(MatchConfig{MinPlayers: 1, CountdownTicks: 0, TimeLimitTicks: 0, ScoreLimit: 3, ResultTicks: 60}, &Deathmatch{}, TicksPerSecond)
+		match.advance(newTestPlayers(1))
+
+		match.addScore("player1", TeamNone, 2)
+		match.advance(newTestPlayers(1))
+		assert.Equal(t, MatchPhaseRunning, match.Phase())
+
+		match.addScore("player1", TeamNone, 1)
+		match.advance(newTestPlayers(1))
+		assert.Equal(t, MatchPhaseFinished, match.Phase())
+		assert.Equal(t, []PlayerID{"player1"}, match.Winners())
+	})
+
+	t.Run("チームのスコアは抜けたプレイヤーの得点も含めて決着に使われる", func(t *testing.T) {
+		match := NewMatch(MatchConfig{MinPlayers: 1, CountdownTicks: 0, TimeLimitTicks: 0, ScoreLimit: 3, ResultTicks: 60}, &TeamDeathmatch{}, TicksPerSecond)
+		players := map[PlayerID]*Player{
+			"red1":  {PlayerID: "red1", status: PlayerStatusAlive, team: TeamRed},
+			"red2":  {PlayerID: "red2", status: PlayerStatusAlive, team: TeamRed},
+			"blue1": {PlayerID: "blue1", status: PlayerStatusAlive, team: TeamBlue},
+		}
+		match.advance(players)
+
+		match.addScore("red1", TeamRed, 2)
+		match.addScore("blue1", TeamBlue, 2)
+
+		// red1が抜けてもREDチームのスコアは残る
+		match.removePlayer("red1")
+		delete(players, "red1")
+		assert.Equal(t, map[Team]int{TeamRed: 2, TeamBlue: 2}, match.TeamScores())
+
+		match.addScore("red2", TeamRed, 1)
+		match.advance(players)
+		assert.Equal(t, MatchPhaseFinished, match.Phase())
+		assert.Equal(t, []PlayerID{"red2"}, match.Winners())
+	})
+
+	t.Run("残り時間の変化が秒単位で通知される", func(t *testing.T) {
+		match := NewMatch(MatchConfig{MinPlayers: 1, CountdownTicks: 0, TimeLimitTicks: TicksPerSecond * 2, ScoreLimit: 0, ResultTicks: 0}, &Deathmatch{}, TicksPerSecond)
+		match.advance(newTestPlayers(1))
+
+		changedCount := 0
+		for range TicksPerSecond {
+			if match.advance(newTestPlayers(1)).changed {
+				changedCount++
+			}
+		}
+		assert.Equal(t, 1, changedCount)
+	})
+}
+
+func Test_Match_ToSharedMatchState(t *testing.T) {
+	match := NewMatch(MatchConfig{MinPlayers: 1, CountdownTicks: 0, TimeLimitTicks: TicksPerSecond * 3, ScoreLimit: 0, ResultTicks: 0}, &Deathmatch{}, TicksPerSecond)
+	match.advance(newTestPlayers(1))
+	match.addScore("player2", TeamNone, 1)
+	match.addScore("player1", TeamNone, 2)
+
+	state := match.ToSharedMatchState()
+	assert.Equal(t, shared.MatchPhase_RUNNING, state.GetPhase())
+	assert.EqualValues(t, 3, state.GetRemainingSeconds())
+	assert.Len(t, state.GetScores(), 2)
+	assert.Equal(t, "player1", state.GetScores()[0].GetPlayerId())
+	assert.EqualValues(t, 2, state.GetScores()[0].GetScore())
+	assert.Empty(t, state.GetTeamScores())
+}
+
+func newTestPlayers(n int) map[PlayerID]*Player {
+	players := make(map[PlayerID]*Player)
+	for i := range n {
+		playerID := PlayerID(fmt.Sprintf("player%d", i+1))
+		players[playerID] = &Player{PlayerID: playerID, status: PlayerStatusAlive}
+	}
+	return players
+}
@@ -0,0 +1,239 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/shibayu36/terminal-shooter/shared"
+)
+
+// GameModeName ゲームモードの種類
+type GameModeName string
+
+const (
+	GameModeDeathmatch      GameModeName = "deathmatch"
+	GameModeLastManStanding GameModeName = "last_man_standing"
+	GameModeTeamDeathmatch  GameModeName = "team_deathmatch"
+)
+
+// ToSharedGameMode GameModeNameをshared.GameModeに変換する
+func (n GameModeName) ToSharedGameMode() shared.GameMode {
+	switch n {
+	case GameModeDeathmatch:
+		return shared.GameMode_DEATHMATCH
+	case GameModeLastManStanding:
+		return shared.GameMode_LAST_MAN_STANDING
+	case GameModeTeamDeathmatch:
+		return shared.GameMode_TEAM_DEATHMATCH
+	default:
+		panic(fmt.Sprintf("invalid game mode: %s", n))
+	}
+}
+
+// 倒されてから復活するまでの時間
+const RespawnDelay = 3 * time.Second
+
+// GameMode 勝利条件やスコアなどのゲームのルールを表すインターフェース
+type GameMode interface {
+	Name() GameModeName
+	// 同じチームのプレイヤーにダメージを与えられるか
+	FriendlyFire() bool
+	// 倒されたプレイヤーが復活できるか
+	RespawnAllowed() bool
+	// 新しく参加するプレイヤーのチームを決める
+	AssignTeam(players map[PlayerID]*Player) Team
+	// victimを倒したkillerに加算するスコアを返す。倒したプレイヤーがいない場合killerはnil
+	KillScore(killer, victim *Player) int
+	// 決着がついていれば勝者を返す。timeUpがtrueの場合は必ず決着をつける
+	// teamScoresは倒した時点のチームに加算したスコアで、抜けたプレイヤーの得点も含む
+	Judge(players map[PlayerID]*Player, scores map[PlayerID]int, teamScores map[Team]int, scoreLimit int, timeUp bool) ([]PlayerID, bool)
+}
+
+// GameModeOptions ゲームモードごとに調整可能なルール
+type GameModeOptions struct {
+	// チーム戦で味方の攻撃が当たるか
+	FriendlyFire bool
+}
+
+// NewGameMode 名前からゲームモードを作る
+func NewGameMode(name GameModeName, options GameModeOptions) (GameMode, error) {
+	switch name {
+	case GameModeDeathmatch:
+		return &Deathmatch{}, nil
+	case GameModeLastManStanding:
+		return &LastManStanding{}, nil
+	case GameModeTeamDeathmatch:
+		return &TeamDeathmatch{AllowFriendlyFire: options.FriendlyFire}, nil
+	default:
+		return nil, errors.Newf("unknown game mode: %s", name)
+	}
+}
+
+// Deathmatch 全員が敵で、倒した数を競うモード
+type Deathmatch struct{}
+
+var _ GameMode = (*Deathmatch)(nil)
+
+func (m *Deathmatch) Name() GameModeName {
+	return GameModeDeathmatch
+}
+
+func (m *Deathmatch) FriendlyFire() bool {
+	return true
+}
+
+func (m *Deathmatch) RespawnAllowed() bool {
+	return true
+}
+
+func (m *Deathmatch) AssignTeam(_ map[PlayerID]*Player) Team {
+	return TeamNone
+}
+
+func (m *Deathmatch) KillScore(killer, victim *Player) int {
+	return individualKillScore(killer, victim)
+}
+
+func (m *Deathmatch) Judge(_ map[PlayerID]*Player, scores map[PlayerID]int, _ map[Team]int, scoreLimit int, timeUp bool) ([]PlayerID, bool) {
+	winners, best := topScorers(scores)
+	if timeUp || (scoreLimit > 0 && best >= scoreLimit) {
+		return winners, true
+	}
+	return nil, false
+}
+
+// LastManStanding 復活なしで最後まで生き残ったプレイヤーが勝つモード
+type LastManStanding struct{}
+
+var _ GameMode = (*LastManStanding)(nil)
+
+func (m *LastManStanding) Name() GameModeName {
+	return GameModeLastManStanding
+}
+
+func (m *LastManStanding) FriendlyFire() bool {
+	return true
+}
+
+func (m *LastManStanding) RespawnAllowed() bool {
+	return false
+}
+
+func (m *LastManStanding) AssignTeam(_ map[PlayerID]*Player) Team {
+	return TeamNone
+}
+
+func (m *LastManStanding) KillScore(killer, victim *Player) int {
+	return individualKillScore(killer, victim)
+}
+
+func (m *LastManStanding) Judge(players map[PlayerID]*Player, _ map[PlayerID]int, _ map[Team]int, _ int, timeUp bool) ([]PlayerID, bool) {
+	survivors := alivePlayerIDs(players)
+	// 1人で遊んでいる場合は決着がつかない
+	if !timeUp && (len(players) < 2 || len(survivors) > 1) {
+		return nil, false
+	}
+	return survivors, true
+}
+
+// TeamDeathmatch チームに分かれて、チームの合計スコアを競うモード
+type TeamDeathmatch struct {
+	// 味方の攻撃が当たるか
+	AllowFriendlyFire bool
+}
+
+var _ GameMode = (*TeamDeathmatch)(nil)
+
+func (m *TeamDeathmatch) Name() GameModeName {
+	return GameModeTeamDeathmatch
+}
+
+func (m *TeamDeathmatch) FriendlyFire() bool {
+	return m.AllowFriendlyFire
+}
+
+func (m *TeamDeathmatch) RespawnAllowed() bool {
+	return true
+}
+
+// AssignTeam 人数の少ないチームに振り分ける
+func (m *TeamDeathmatch) AssignTeam(players map[PlayerID]*Player) Team {
+	counts := countTeamMembers(players)
+	if counts[TeamBlue] < counts[TeamRed] {
+		return TeamBlue
+	}
+	return TeamRed
+}
+
+func (m *TeamDeathmatch) KillScore(killer, victim *Player) int {
+	if killer != nil && killer != victim && killer.Team() == victim.Team() {
+		// 味方を倒した場合は減点
+		return -1
+	}
+	return individualKillScore(killer, victim)
+}
+
+func (m *TeamDeathmatch) Judge(players map[PlayerID]*Player, _ map[PlayerID]int, teamScores map[Team]int, scoreLimit int, timeUp bool) ([]PlayerID, bool) {
+	winnerTeams, best := topScorers(teamScores)
+	if !timeUp && (scoreLimit <= 0 || best < scoreLimit) {
+		return nil, false
+	}
+
+	var winners []PlayerID
+	for playerID, player := range players {
+		for _, team := range winnerTeams {
+			if player.Team() == team {
+				winners = append(winners, playerID)
+			}
+		}
+	}
+	sortPlayerIDs(winners)
+	return winners, true
+}
+
+// individualKillScore 他のプレイヤーを倒せば加点、自滅なら減点する
+func individualKillScore(killer, victim *Player) int {
+	switch {
+	case killer == nil:
+		return 0
+	case killer == victim:
+		return -1
+	default:
+		return 1
+	}
+}
+
+// topScorers 最高スコアのキー一覧とそのスコアを返す。誰も得点していなければ空を返す
+func topScorers[K ~string](scores map[K]int) ([]K, int) {
+	var winners []K
+	best := 0
+	for key, score := range scores {
+		switch {
+		case score > best:
+			best = score
+			winners = []K{key}
+		case score == best && score > 0:
+			winners = append(winners, key)
+		}
+	}
+	sort.Slice(winners, func(i, j int) bool { return winners[i] < winners[j] })
+	return winners, best
+}
+
+// alivePlayerIDs 生きているプレイヤーのID一覧を返す
+func alivePlayerIDs(players map[PlayerID]*Player) []PlayerID {
+	var alive []PlayerID
+	for playerID, player := range players {
+		if player.Status() == PlayerStatusAlive {
+			alive = append(alive, playerID)
+		}
+	}
+	sortPlayerIDs(alive)
+	return alive
+}
+
+func sortPlayerIDs(playerIDs []PlayerID) {
+	sort.Slice(playerIDs, func(i, j int) bool { return playerIDs[i] < playerIDs[j] })
+}
@@ -0,0 +1,161 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_NewGameMode(t *testing.T) {
+	for _, name := range []GameModeName{GameModeDeathmatch, GameModeLastManStanding, GameModeTeamDeathmatch} {
+		mode, err := NewGameMode(name, GameModeOptions{FriendlyFire: false})
+		require.NoError(t, err)
+		assert.Equal(t, name, mode.Name())
+	}
+
+	_, err := NewGameMode("unknown", GameModeOptions{FriendlyFire: false})
+	assert.Error(t, err)
+}
+
+func Test_Deathmatch(t *testing.T) {
+	mode := &Deathmatch{}
+	player1 := &Player{PlayerID: "player1", status: PlayerStatusAlive}
+	player2 := &Player{PlayerID: "player2", status: PlayerStatusAlive}
+
+	t.Run("倒せば加点、自滅なら減点される", func(t *testing.T) {
+		assert.Equal(t, 1, mode.KillScore(player1, player2))
+		assert.Equal(t, -1, mode.KillScore(player1, player1))
+		assert.Equal(t, 0, mode.KillScore(nil, player1))
+	})
+
+	t.Run("勝利スコアに達したら決着する", func(t *testing.T) {
+		_, decided := mode.Judge(nil, map[PlayerID]int{"player1": 2}, nil, 3, false)
+		assert.False(t, decided)
+
+		winners, decided := mode.Judge(nil, map[PlayerID]int{"player1": 3, "player2": 1}, nil, 3, false)
+		assert.True(t, decided)
+		assert.Equal(t, []PlayerID{"player1"}, winners)
+	})
+
+	t.Run("時間切れなら最高スコアのプレイヤーが勝つ", func(t *testing.T) {
+		winners, decided := mode.Judge(nil, map[PlayerID]int{"player1": 1, "player2": 1}, nil, 0, true)
+		assert.True(t, decided)
+		assert.Equal(t, []PlayerID{"player1", "player2"}, winners)
+	})
+}
+
+func Test_LastManStanding(t *testing.T) {
+	mode := &LastManStanding{}
+	assert.False(t, mode.RespawnAllowed())
+
+	t.Run("生き残りが1人になったら決着する", func(t *testing.T) {
+		players := newTestPlayers(3)
+		_, decided := mode.Judge(players, nil, nil, 0, false)
+		assert.False(t, decided)
+
+		players["player1"].status = PlayerStatusDead
+		_, decided = mode.Judge(players, nil, nil, 0, false)
+		assert.False(t, decided)
+
+		players["player3"].status = PlayerStatusDead
+		winners, decided := mode.Judge(players, nil, nil, 0, false)
+		assert.True(t, decided)
+		assert.Equal(t, []PlayerID{"player2"}, winners)
+	})
+
+	t.Run("1人で遊んでいる場合は決着しない", func(t *testing.T) {
+		_, decided := mode.Judge(newTestPlayers(1), nil, nil, 0, false)
+		assert.False(t, decided)
+	})
+}
+
+func Test_TeamDeathmatch(t *testing.T) {
+	mode := &TeamDeathmatch{}
+	red1 := &Player{PlayerID: "red1", status: PlayerStatusAlive, team: TeamRed}
+	red2 := &Player{PlayerID: "red2", status: PlayerStatusAlive, team: TeamRed}
+	blue1 := &Player{PlayerID: "blue1", status: PlayerStatusAlive, team: TeamBlue}
+	players := map[PlayerID]*Player{"red1": red1, "red2": red2, "blue1": blue1}
+
+	t.Run("人数の少ないチームに振り分けられる", func(t *testing.T) {
+		assert.Equal(t, TeamRed, mode.AssignTeam(map[PlayerID]*Player{}))
+		assert.Equal(t, TeamBlue, mode.AssignTeam(map[PlayerID]*Player{"red1": red1}))
+		assert.Equal(t, TeamBlue, mode.AssignTeam(players))
+	})
+
+	t.Run("味方を倒すと減点される", func(t *testing.T) {
+		assert.Equal(t, 1, mode.KillScore(red1, blue1))
+		assert.Equal(t, -1, mode.KillScore(red1, red2))
+	})
+
+	t.Run("チームの合計スコアで決着し、チーム全員が勝者になる", func(t *testing.T) {
+		_, decided := mode.Judge(players, nil, map[Team]int{TeamRed: 2, TeamBlue: 2}, 3, false)
+		assert.False(t, decided)
+
+		winners, decided := mode.Judge(players, nil, map[Team]int{TeamRed: 3, TeamBlue: 2}, 3, false)
+		assert.True(t, decided)
+		assert.Equal(t, []PlayerID{"red1", "red2"}, winners)
+	})
+}
+
+func Test_Game_GameMode(t *testing.T) {
+	newModeGame := func(mode GameMode) *Game {
+		return NewGameWithConfig(Config{
+			Width:  30,
+			Height: 30,
+			Match:  MatchConfig{MinPlayers: 0, CountdownTicks: 0, TimeLimitTicks: 0, ScoreLimit: 0, ResultTicks: 60},
+			Mode:   mode,
+		})
+	}
+
+	t.Run("Deathmatchでは倒されても一定時間後に復活する", func(t *testing.T) {
+		game := newModeGame(&Deathmatch{})
+		game.AddPlayer("player1")
+		game.UpdatePlayerStatus("player1", PlayerStatusDead)
+
+		for range testTimings().respawn - 1 {
+			game.Step()
+		}
+		assert.Equal(t, PlayerStatusDead, game.GetPlayers()["player1"].Status())
+
+		game.Step()
+		assert.Equal(t, PlayerStatusAlive, game.GetPlayers()["player1"].Status())
+	})
+
+	t.Run("1秒あたりのtick数を変えても、復活までの秒数は変わらない", func(t *testing.T) {
+		const ticksPerSecond = 30
+		game := NewGameWithConfig(Config{
+			Width:          30,
+			Height:         30,
+			Match:          MatchConfig{MinPlayers: 0, CountdownTicks: 0, TimeLimitTicks: 0, ScoreLimit: 0, ResultTicks: 60},
+			Mode:           &Deathmatch{},
+			TicksPerSecond: ticksPerSecond,
+		})
+		game.AddPlayer("player1")
+		game.UpdatePlayerStatus("player1", PlayerStatusDead)
+
+		ticks := 0
+		for game.GetPlayers()["player1"].Status() == PlayerStatusDead {
+			game.Step()
+			ticks++
+			require.Less(t, ticks, 10*ticksPerSecond, "復活しない")
+		}
+		assert.Equal(t, RespawnDelay, time.Duration(ticks)*time.Second/ticksPerSecond)
+	})
+
+	t.Run("LastManStandingでは復活せず、最後の1人が勝者になる", func(t *testing.T) {
+		game := newModeGame(&LastManStanding{})
+		game.AddPlayer("player1")
+		game.AddPlayer("player2")
+
+		game.MovePlayer("player1", Position{X: 2, Y: 3}, DirectionRight)
+		game.MovePlayer("player2", Position{X: 3, Y: 3}, DirectionLeft)
+		game.GetPlayers()["player2"].hp = BulletDamage
+		game.ShootBullet("player1")
+		game.Step()
+
+		assert.Equal(t, MatchPhaseFinished, game.Match().Phase())
+		assert.Equal(t, []PlayerID{"player1"}, game.Match().Winners())
+	})
+}
@@ -0,0 +1,127 @@
+package game
+
+// スピードアップ中の移動間隔の短縮率
+const SpeedUpMoveTicksDivisor = 2
+
+// MovementConfig プレイヤーの移動の制限
+// ゼロ値の場合は速度を制限しないが、隣のマスへの移動だけを受け付けるのは変わらない
+type MovementConfig struct {
+	// 1マス移動するのに必要なtick数。0なら制限なし
+	MoveTicks int
+}
+
+// MoveResult 移動リクエストの処理結果
+type MoveResult string
+
+const (
+	MoveResultApplied  MoveResult = "applied"  // 移動した
+	MoveResultQueued   MoveResult = "queued"   // 移動間隔が空くまで待っている
+	MoveResultRejected MoveResult = "rejected" // 移動できなかった
+)
+
+// moveRequest 移動間隔が空くのを待っている移動リクエスト
+type moveRequest struct {
+	position  Position
+	direction Direction
+}
+
+// movement プレイヤーごとの移動の状態
+// Playerのロックの中で操作する
+type movement struct {
+	config MovementConfig
+	// 次に移動できるまでの残りtick数
+	cooldown int
+	// 移動間隔が空くのを待っているリクエスト。最後のリクエストだけを保持する
+	pending *moveRequest
+}
+
+func newMovement(config MovementConfig) movement {
+	return movement{
+		config:   config,
+		cooldown: 0,
+		pending:  nil,
+	}
+}
+
+// moveTicks 1マス移動するのに必要なtick数。0なら制限なし
+func (m *movement) moveTicks(speedUp bool) int {
+	if speedUp {
+		return max(m.config.MoveTicks/SpeedUpMoveTicksDivisor, 1)
+	}
+	return m.config.MoveTicks
+}
+
+// isAdjacent 上下左右に1マス隣り合っているかどうか
+func isAdjacent(a, b Position) bool {
+	dx, dy := a.X-b.X, a.Y-b.Y
+	return dx*dx+dy*dy == 1
+}
+
+// RequestMove クライアントからの移動リクエストを処理する
+// 盤面の中の隣のマスへの移動だけを受け付け、移動速度が制限されている場合は移動間隔が空くまでリクエストを保留する
+// 向きだけを変えるリクエストは即座に反映する
+func (g *Game) RequestMove(playerID PlayerID, position Position, direction Direction) (*Player, MoveResult) {
+	g.inputMu.Lock()
+	defer g.inputMu.Unlock()
+	g.recordInput(Input{Type: InputTypeMove, PlayerID: playerID, Position: position, Direction: direction})
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	player, ok := g.Players[playerID]
+	if !ok {
+		return nil, MoveResultRejected
+	}
+
+	if player.Status() == PlayerStatusDead {
+		return player, MoveResultRejected
+	}
+
+	current := player.Position()
+	if position == current {
+		player.Move(current, direction)
+		g.emit(PlayerMoved{Player: player})
+		return player, MoveResultApplied
+	}
+	if !g.canMoveWithoutLock(current, position) {
+		return player, MoveResultRejected
+	}
+
+	if !player.requestMove(position, direction) {
+		return player, MoveResultQueued
+	}
+	g.reindexPlayerWithoutLock(player)
+	g.emit(PlayerMoved{Player: player})
+	return player, MoveResultApplied
+}
+
+// canMoveWithoutLock fromからtoへ1マス移動できるかどうか
+func (g *Game) canMoveWithoutLock(from, to Position) bool {
+	return isAdjacent(from, to) &&
+		to.X >= 0 && to.X < g.Width && to.Y >= 0 && to.Y < g.Height &&
+		g.obstacleAtWithoutLock(to) == nil
+}
+
+// 移動間隔を進め、保留していた移動リクエストを処理する
+// 保留していたリクエストで移動したプレイヤーを返す
+func (g *Game) tickMovements() []*Player {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var moved []*Player
+	for _, player := range g.sortedPlayersWithoutLock() {
+		request := player.tickMove()
+		if request == nil {
+			continue
+		}
+		// 保留している間に状況が変わっていれば移動しない
+		if player.Status() == PlayerStatusDead || !g.canMoveWithoutLock(player.Position(), request.position) {
+			continue
+		}
+		if player.requestMove(request.position, request.direction) {
+			g.reindexPlayerWithoutLock(player)
+			moved = append(moved, player)
+		}
+	}
+	return moved
+}
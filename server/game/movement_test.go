@@ -0,0 +1,121 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Game_RequestMove(t *testing.T) {
+	newMovementGame := func(moveTicks int) *Game {
+		game := NewGameWithConfig(Config{
+			Width:    30,
+			Height:   30,
+			Movement: MovementConfig{MoveTicks: moveTicks},
+		})
+		game.AddPlayer("player1")
+		game.MovePlayer("player1", Position{X: 10, Y: 10}, DirectionUp)
+		return game
+	}
+
+	t.Run("移動速度が制限されていなければ隣のマスへすぐに移動する", func(t *testing.T) {
+		game := newMovementGame(0)
+
+		player, result := game.RequestMove("player1", Position{X: 11, Y: 10}, DirectionRight)
+		assert.Equal(t, MoveResultApplied, result)
+		assert.Equal(t, Position{X: 11, Y: 10}, player.Position())
+
+		player, result = game.RequestMove("player1", Position{X: 12, Y: 10}, DirectionRight)
+		assert.Equal(t, MoveResultApplied, result)
+		assert.Equal(t, Position{X: 12, Y: 10}, player.Position())
+	})
+
+	t.Run("移動速度が制限されていなくても、隣のマス以外や盤面の外への移動は受け付けない", func(t *testing.T) {
+		game := newMovementGame(0)
+
+		player, result := game.RequestMove("player1", Position{X: 20, Y: 5}, DirectionRight)
+		assert.Equal(t, MoveResultRejected, result)
+		assert.Equal(t, Position{X: 10, Y: 10}, player.Position())
+
+		game.MovePlayer("player1", Position{X: 0, Y: 0}, DirectionUp)
+		_, result = game.RequestMove("player1", Position{X: -1, Y: 0}, DirectionLeft)
+		assert.Equal(t, MoveResultRejected, result)
+		_, result = game.RequestMove("player1", Position{X: 0, Y: -1}, DirectionUp)
+		assert.Equal(t, MoveResultRejected, result)
+		assert.Equal(t, Position{X: 0, Y: 0}, player.Position())
+	})
+
+	t.Run("位置を直接指定した場合も盤面の外には出ない", func(t *testing.T) {
+		game := newMovementGame(0)
+
+		player := game.MovePlayer("player1", Position{X: 40, Y: -3}, DirectionRight)
+		assert.Equal(t, Position{X: 29, Y: 0}, player.Position())
+	})
+
+	t.Run("移動間隔が空くまでのリクエストは保留され、間隔が空いたら移動する", func(t *testing.T) {
+		game := newMovementGame(3)
+
+		player, result := game.RequestMove("player1", Position{X: 11, Y: 10}, DirectionRight)
+		assert.Equal(t, MoveResultApplied, result)
+		assert.Equal(t, Position{X: 11, Y: 10}, player.Position())
+
+		_, result = game.RequestMove("player1", Position{X: 12, Y: 10}, DirectionRight)
+		assert.Equal(t, MoveResultQueued, result)
+		assert.Equal(t, Position{X: 11, Y: 10}, player.Position())
+
+		game.Step()
+		game.Step()
+		assert.Equal(t, Position{X: 11, Y: 10}, player.Position())
+
+		events := game.Step()
+		assert.Equal(t, Position{X: 12, Y: 10}, player.Position())
+		assert.Equal(t, []Event{PlayerMoved{Player: player}}, events)
+	})
+
+	t.Run("隣のマス以外への移動は受け付けない", func(t *testing.T) {
+		game := newMovementGame(3)
+
+		player, result := game.RequestMove("player1", Position{X: 12, Y: 10}, DirectionRight)
+		assert.Equal(t, MoveResultRejected, result)
+		assert.Equal(t, Position{X: 10, Y: 10}, player.Position())
+
+		_, result = game.RequestMove("player1", Position{X: 11, Y: 11}, DirectionRight)
+		assert.Equal(t, MoveResultRejected, result)
+	})
+
+	t.Run("向きだけを変えるリクエストは移動間隔に関係なく反映される", func(t *testing.T) {
+		game := newMovementGame(3)
+
+		game.RequestMove("player1", Position{X: 11, Y: 10}, DirectionRight)
+		player, result := game.RequestMove("player1", Position{X: 11, Y: 10}, DirectionDown)
+		assert.Equal(t, MoveResultApplied, result)
+		assert.Equal(t, DirectionDown, player.Direction())
+	})
+
+	t.Run("スピードアップ中は移動間隔が短くなる", func(t *testing.T) {
+		game := newMovementGame(4)
+		player := game.GetPlayers()["player1"]
+		player.addEffect(ItemTypeSpeedUp, testTimings().powerUpEffect)
+		assert.Equal(t, 2, player.MoveTicks())
+		assert.EqualValues(t, 2, player.ToSharedPlayerState().GetMoveTicks())
+
+		game.RequestMove("player1", Position{X: 11, Y: 10}, DirectionRight)
+		game.Step()
+		game.Step()
+		_, result := game.RequestMove("player1", Position{X: 12, Y: 10}, DirectionRight)
+		assert.Equal(t, MoveResultApplied, result)
+	})
+
+	t.Run("移動速度が制限されていると空いているランダムな位置に配置される", func(t *testing.T) {
+		game := NewGameWithConfig(Config{
+			Width:    30,
+			Height:   30,
+			Movement: MovementConfig{MoveTicks: 3},
+			Arena:    ArenaConfig{PillarInterval: 2},
+		})
+		for range 10 {
+			game.AddPlayer("player1")
+			assert.Nil(t, game.obstacleAt(game.GetPlayers()["player1"].Position()))
+		}
+	})
+}
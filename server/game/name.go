@@ -0,0 +1,121 @@
+package game
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/errors"
+)
+
+// 表示名の最大文字数
+const MaxDisplayNameLength = 16
+
+var (
+	// ErrDisplayNameInvalid 表示名が空、長すぎる、表示できない文字や不適切な言葉を含む
+	ErrDisplayNameInvalid = errors.New("invalid display name")
+	// ErrDisplayNameTaken 表示名が他のプレイヤーに使われている
+	ErrDisplayNameTaken = errors.New("display name is already taken")
+)
+
+// 表示名に使えない言葉。大文字小文字を区別せず、部分一致で判定する
+var bannedWords = []string{ //nolint:gochecknoglobals
+	"fuck",
+	"shit",
+	"bitch",
+	"cunt",
+	"nigger",
+	"faggot",
+}
+
+// normalizeDisplayName 前後の空白を取り除き、表示名として使えるか検証する
+func normalizeDisplayName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+
+	length := utf8.RuneCountInString(name)
+	if length == 0 || length > MaxDisplayNameLength {
+		return "", errors.Wrapf(ErrDisplayNameInvalid, "length must be 1-%d: %d", MaxDisplayNameLength, length)
+	}
+	for _, r := range name {
+		if !unicode.IsPrint(r) {
+			return "", errors.Wrapf(ErrDisplayNameInvalid, "unprintable character: %U", r)
+		}
+	}
+
+	lower := strings.ToLower(name)
+	for _, word := range bannedWords {
+		if strings.Contains(lower, word) {
+			return "", errors.Wrap(ErrDisplayNameInvalid, "contains banned word")
+		}
+	}
+
+	return name, nil
+}
+
+// JoinPlayer 表示名を付けてプレイヤーを参加させる
+// 表示名を検証してから追加するので、表示名を受け付けなかった場合はプレイヤーを作らない
+// 既に参加している場合は表示名だけを変える
+func (g *Game) JoinPlayer(playerID PlayerID, name string) (*Player, error) {
+	g.inputMu.Lock()
+	defer g.inputMu.Unlock()
+	g.recordInput(Input{Type: InputTypeJoin, PlayerID: playerID, DisplayName: name})
+
+	name, err := normalizeDisplayName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if err := g.checkDisplayNameWithoutLock(playerID, name); err != nil {
+		return nil, err
+	}
+
+	player, ok := g.Players[playerID]
+	if !ok {
+		return g.addPlayerWithoutLock(playerID, name), nil
+	}
+	player.setDisplayName(name)
+	g.emit(PlayerUpdated{Player: player})
+	return player, nil
+}
+
+// SetDisplayName プレイヤーの表示名を設定する
+// 表示名は大文字小文字を区別せずに他のプレイヤーと重複できない
+func (g *Game) SetDisplayName(playerID PlayerID, name string) (*Player, error) {
+	g.inputMu.Lock()
+	defer g.inputMu.Unlock()
+	g.recordInput(Input{Type: InputTypeSetDisplayName, PlayerID: playerID, DisplayName: name})
+
+	name, err := normalizeDisplayName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	player, ok := g.Players[playerID]
+	if !ok {
+		return nil, errors.Newf("player not found: %s", playerID)
+	}
+
+	if err := g.checkDisplayNameWithoutLock(playerID, name); err != nil {
+		return nil, err
+	}
+
+	player.setDisplayName(name)
+	g.emit(PlayerUpdated{Player: player})
+	return player, nil
+}
+
+// checkDisplayNameWithoutLock 表示名が他のプレイヤーに使われていないか検証する
+func (g *Game) checkDisplayNameWithoutLock(playerID PlayerID, name string) error {
+	for otherID, other := range g.Players {
+		if otherID != playerID && strings.EqualFold(other.DisplayName(), name) {
+			return errors.Wrapf(ErrDisplayNameTaken, "name: %s", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package game
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_normalizeDisplayName(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "そのまま使える", input: "alice", want: "alice", wantErr: false},
+		{name: "前後の空白は取り除く", input: "  bob ", want: "bob", wantErr: false},
+		{name: "マルチバイト文字も1文字として数える", input: strings.Repeat("あ", MaxDisplayNameLength), want: strings.Repeat("あ", MaxDisplayNameLength), wantErr: false},
+		{name: "空白だけは使えない", input: "   ", want: "", wantErr: true},
+		{name: "長すぎる", input: strings.Repeat("a", MaxDisplayNameLength+1), want: "", wantErr: true},
+		{name: "制御文字は使えない", input: "a\nb", want: "", wantErr: true},
+		{name: "不適切な言葉は大文字小文字を区別せずに使えない", input: "xShitx", want: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeDisplayName(tt.input)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrDisplayNameInvalid))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_Game_SetDisplayName(t *testing.T) {
+	game := NewGame(30, 30)
+	game.AddPlayer("player1")
+	game.AddPlayer("player2")
+	game.Step()
+
+	t.Run("表示名を設定すると通知される", func(t *testing.T) {
+		player, err := game.SetDisplayName("player1", " Alice ")
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", player.DisplayName())
+		assert.Equal(t, "Alice", player.ToSharedPlayerState().GetDisplayName())
+		assert.Equal(t, []Event{PlayerUpdated{Player: player}}, game.Step())
+	})
+
+	t.Run("自分の表示名は変更できる", func(t *testing.T) {
+		player, err := game.SetDisplayName("player1", "alice")
+		require.NoError(t, err)
+		assert.Equal(t, "alice", player.DisplayName())
+	})
+
+	t.Run("他のプレイヤーと大文字小文字違いの表示名は使えない", func(t *testing.T) {
+		_, err := game.SetDisplayName("player2", "ALICE")
+		assert.True(t, errors.Is(err, ErrDisplayNameTaken))
+		assert.Equal(t, "", game.GetPlayers()["player2"].DisplayName())
+	})
+
+	t.Run("不正な表示名は使えない", func(t *testing.T) {
+		_, err := game.SetDisplayName("player2", "")
+		assert.True(t, errors.Is(err, ErrDisplayNameInvalid))
+	})
+
+	t.Run("存在しないプレイヤーには設定できない", func(t *testing.T) {
+		_, err := game.SetDisplayName("unknown", "carol")
+		assert.Error(t, err)
+	})
+}
+
+func Test_Game_JoinPlayer(t *testing.T) {
+	game := NewGame(30, 30)
+	game.AddPlayer("player1")
+	_, err := game.SetDisplayName("player1", "alice")
+	require.NoError(t, err)
+	game.Step()
+
+	t.Run("表示名を付けてプレイヤーを追加する", func(t *testing.T) {
+		player, err := game.JoinPlayer("player2", " bob ")
+		require.NoError(t, err)
+		assert.Equal(t, "bob", player.DisplayName())
+		assert.Equal(t, []Event{PlayerJoined{Player: player}}, game.Step())
+	})
+
+	t.Run("表示名を受け付けなかった場合はプレイヤーを作らない", func(t *testing.T) {
+		_, err := game.JoinPlayer("player3", "ALICE")
+		assert.True(t, errors.Is(err, ErrDisplayNameTaken))
+		_, err = game.JoinPlayer("player3", "")
+		assert.True(t, errors.Is(err, ErrDisplayNameInvalid))
+
+		assert.NotContains(t, game.GetPlayers(), PlayerID("player3"))
+		assert.Empty(t, game.Step())
+	})
+
+	t.Run("参加済みの場合は表示名だけを変える", func(t *testing.T) {
+		player, err := game.JoinPlayer("player2", "carol")
+		require.NoError(t, err)
+		assert.Equal(t, "carol", player.DisplayName())
+		assert.Equal(t, []Event{PlayerUpdated{Player: player}}, game.Step())
+	})
+}
@@ -0,0 +1,90 @@
+package game
+
+// obstacle はプレイヤーの移動や爆発の火を遮るアイテムを表すインターフェース
+type obstacle interface {
+	Item
+	// 爆発で壊せるかどうか
+	Destructible() bool
+}
+
+// Wall 壊せない壁を表す
+type Wall struct {
+	id       ItemID
+	position Position
+}
+
+var _ obstacle = (*Wall)(nil)
+
+func NewWall(id ItemID, position Position) *Wall {
+	return &Wall{
+		id:       id,
+		position: position,
+	}
+}
+
+func (w *Wall) ID() ItemID {
+	return w.id
+}
+
+func (w *Wall) Type() ItemType {
+	return ItemTypeWall
+}
+
+func (w *Wall) Position() Position {
+	return w.position
+}
+
+func (w *Wall) Destructible() bool {
+	return false
+}
+
+// Update 壁は何も変化しない
+func (w *Wall) Update(_ gameOperationProvider) bool {
+	return false
+}
+
+// OnCollideWith 他のオブジェクトと衝突した時の処理
+func (w *Wall) OnCollideWith(_ collidable, _ gameOperationProvider) bool {
+	return false
+}
+
+// Block 爆発で壊せるブロックを表す
+type Block struct {
+	id       ItemID
+	position Position
+}
+
+var _ obstacle = (*Block)(nil)
+
+func NewBlock(id ItemID, position Position) *Block {
+	return &Block{
+		id:       id,
+		position: position,
+	}
+}
+
+func (b *Block) ID() ItemID {
+	return b.id
+}
+
+func (b *Block) Type() ItemType {
+	return ItemTypeBlock
+}
+
+func (b *Block) Position() Position {
+	return b.position
+}
+
+func (b *Block) Destructible() bool {
+	return true
+}
+
+// Update ブロックは爆発で壊されるまで何も変化しない
+func (b *Block) Update(_ gameOperationProvider) bool {
+	return false
+}
+
+// OnCollideWith 他のオブジェクトと衝突した時の処理
+func (b *Block) OnCollideWith(_ collidable, _ gameOperationProvider) bool {
+	return false
+}
@@ -0,0 +1,146 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newArenaGame(arena ArenaConfig) *Game {
+	return NewGameWithConfig(Config{
+		Width:  30,
+		Height: 30,
+		Arena:  arena,
+	})
+}
+
+func Test_Game_Arena(t *testing.T) {
+	t.Run("1マスおきに壁が配置される", func(t *testing.T) {
+		game := newArenaGame(ArenaConfig{PillarInterval: 2})
+
+		items := game.GetItems()
+		assert.Len(t, items, 15*15)
+		for _, item := range items {
+			assert.Equal(t, ItemTypeWall, item.Type())
+			assert.Equal(t, 1, item.Position().X%2)
+			assert.Equal(t, 1, item.Position().Y%2)
+		}
+	})
+
+	t.Run("ブロックはプレイヤーの周りを避けて配置される", func(t *testing.T) {
+		game := newArenaGame(ArenaConfig{})
+		game.AddPlayer("player1")
+		game.MovePlayer("player1", Position{X: 10, Y: 10}, DirectionUp)
+
+		game.arena.BlockDensity = 1
+		game.resetBoard()
+
+		items := game.GetItems()
+		assert.Len(t, items, 30*30-5)
+		for _, item := range items {
+			assert.Equal(t, ItemTypeBlock, item.Type())
+		}
+		assert.Nil(t, game.obstacleAt(Position{X: 10, Y: 10}))
+		assert.Nil(t, game.obstacleAt(Position{X: 10, Y: 11}))
+		assert.NotNil(t, game.obstacleAt(Position{X: 11, Y: 11}))
+	})
+
+	t.Run("盤面のリセットで壁は残り、ブロックは配置し直される", func(t *testing.T) {
+		game := newArenaGame(ArenaConfig{PillarInterval: 2, BlockDensity: 1})
+		walls := 0
+		for _, item := range game.GetItems() {
+			if item.Type() == ItemTypeWall {
+				walls++
+			}
+		}
+		assert.Equal(t, 15*15, walls)
+		block := game.obstacleAt(Position{X: 0, Y: 0})
+		assert.NotNil(t, block)
+
+		game.Step()
+		game.resetBoard()
+		events := game.Step()
+
+		assert.Len(t, game.GetItems(), 30*30)
+		assert.NotContains(t, game.GetItems(), block.ID(), "ブロックは新しく配置し直される")
+		assert.Contains(t, game.GetItems(), ItemID("wall-1-1"), "壁は残る")
+		assert.Contains(t, removedItemIDs(events), block.ID())
+		assert.NotContains(t, removedItemIDs(events), ItemID("wall-1-1"))
+	})
+
+	t.Run("障害物のある位置には移動できない", func(t *testing.T) {
+		game := newArenaGame(ArenaConfig{PillarInterval: 2})
+		game.AddPlayer("player1")
+
+		game.MovePlayer("player1", Position{X: 1, Y: 0}, DirectionRight)
+		player := game.MovePlayer("player1", Position{X: 1, Y: 1}, DirectionDown)
+
+		assert.Equal(t, Position{X: 1, Y: 0}, player.Position())
+		assert.Equal(t, DirectionDown, player.Direction(), "向きだけは変わる")
+	})
+
+	t.Run("弾は障害物に当たると消える", func(t *testing.T) {
+		game := newArenaGame(ArenaConfig{PillarInterval: 2})
+
+		bulletID := game.AddBullet(Position{X: 0, Y: 1}, DirectionRight)
+		for range 30 {
+			game.Step()
+		}
+
+		assert.NotContains(t, game.GetItems(), bulletID)
+		assert.Contains(t, game.GetItems(), ItemID("wall-1-1"))
+	})
+}
+
+func Test_Bomb_Obstacles(t *testing.T) {
+	t.Run("爆発の火は壁で止まる", func(t *testing.T) {
+		game := newArenaGame(ArenaConfig{})
+		game.addItem(NewWall("wall1", Position{X: 7, Y: 8}))
+
+		bomb := newTestBomb("bomb1", Position{X: 5, Y: 8})
+		bomb.explodeWithoutLock(game)
+
+		positions := make(map[Position]bool)
+		for _, item := range game.GetItems() {
+			if item.Type() == ItemTypeBombFire {
+				positions[item.Position()] = true
+			}
+		}
+		assert.Len(t, positions, 1+3*BombFireRange+1)
+		assert.True(t, positions[Position{X: 6, Y: 8}])
+		assert.False(t, positions[Position{X: 7, Y: 8}])
+		assert.False(t, positions[Position{X: 8, Y: 8}], "壁の向こうには火が届かない")
+		assert.Contains(t, game.GetItems(), ItemID("wall1"))
+	})
+
+	t.Run("爆発の火はブロックを壊して止まる", func(t *testing.T) {
+		game := newArenaGame(ArenaConfig{})
+		game.addItem(NewBlock("block1", Position{X: 5, Y: 6}))
+		game.addItem(NewBlock("block2", Position{X: 5, Y: 5}))
+
+		bomb := newTestBomb("bomb1", Position{X: 5, Y: 8})
+		bomb.explodeWithoutLock(game)
+
+		items := game.GetItems()
+		assert.NotContains(t, items, ItemID("block1"))
+		assert.Contains(t, items, ItemID("block2"), "手前のブロックで火が止まる")
+		assert.Nil(t, game.obstacleAt(Position{X: 5, Y: 6}))
+	})
+
+	t.Run("壊したブロックからパワーアップが出現することがある", func(t *testing.T) {
+		game := newArenaGame(ArenaConfig{PowerUpChance: 1})
+		game.addItem(NewBlock("block1", Position{X: 5, Y: 6}))
+
+		bomb := newTestBomb("bomb1", Position{X: 5, Y: 8})
+		bomb.explodeWithoutLock(game)
+
+		var powerUps []Item
+		for _, item := range game.GetItems() {
+			if _, ok := item.(*PowerUp); ok {
+				powerUps = append(powerUps, item)
+			}
+		}
+		assert.Len(t, powerUps, 1)
+		assert.Equal(t, Position{X: 5, Y: 6}, powerUps[0].Position())
+	})
+}
@@ -2,6 +2,7 @@ package game
 
 import (
 	"fmt"
+	"sort"
 	"sync"
 
 	"github.com/shibayu36/terminal-shooter/shared"
@@ -14,19 +15,49 @@ const (
 	PlayerStatusDead  PlayerStatus = "dead"
 )
 
+// プレイヤーの最大HP
+const MaxHP = 100
+
 // プレイヤーの状態を管理する
 type Player struct {
 	PlayerID PlayerID
 
+	// 表示名。参加リクエストを受け付けるまでは空
+	displayName string
+
 	position  Position
 	direction Direction
 	status    PlayerStatus
+	hp        int
+	team      Team
+	// DEADになってからのtick数
+	deadTicks int
+	// パワーアップの種類ごとの効果の残りtick数
+	effects map[ItemType]int
+	// 連射やボムの数の制限
+	weapon weapon
+	// 移動速度の制限
+	movement movement
+	// パワーアップの効果の残り時間を秒に直すための1秒あたりのtick数
+	ticksPerSecond int
 
 	mu sync.RWMutex `exhaustruct:"optional"`
 }
 
 var _ collidable = (*Player)(nil)
 
+func (p *Player) DisplayName() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.displayName
+}
+
+func (p *Player) setDisplayName(name string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.displayName = name
+}
+
 func (p *Player) Position() Position {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
@@ -45,6 +76,165 @@ func (p *Player) Status() PlayerStatus {
 	return p.status
 }
 
+func (p *Player) HP() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.hp
+}
+
+// HasEffect パワーアップの効果がかかっているかどうか
+func (p *Player) HasEffect(itemType ItemType) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.effects[itemType] > 0
+}
+
+// BombRange 設置するボムの爆発の範囲
+func (p *Player) BombRange() int {
+	p.mu.RLock()
+	fireRange := p.weapon.config.bombFireRange()
+	p.mu.RUnlock()
+
+	if p.HasEffect(ItemTypeBombRangeUp) {
+		return fireRange + BombRangeBonus
+	}
+	return fireRange
+}
+
+// addEffect パワーアップの効果をかける。既にかかっている場合は持続時間を延長する
+func (p *Player) addEffect(itemType ItemType, duration int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.effects == nil {
+		p.effects = make(map[ItemType]int)
+	}
+	p.effects[itemType] = max(p.effects[itemType], duration)
+}
+
+// tickEffects パワーアップの効果の残り時間を進める
+// 残り秒数が変わった効果があればtrueを返す
+func (p *Player) tickEffects() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	changed := false
+	for itemType, remaining := range p.effects {
+		if remaining%p.ticksPerSecond == 1 {
+			changed = true
+		}
+		if remaining <= 1 {
+			delete(p.effects, itemType)
+			continue
+		}
+		p.effects[itemType] = remaining - 1
+	}
+	return changed
+}
+
+// clearEffectsWithoutLock 全てのパワーアップの効果を消す
+func (p *Player) clearEffectsWithoutLock() {
+	p.effects = make(map[ItemType]int)
+}
+
+// shoot 弾を1発消費する。発射間隔が空いていない、もしくはリロード中の場合はfalseを返す
+func (p *Player) shoot() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.weapon.shoot(p.effects[ItemTypeRapidFire] > 0)
+}
+
+// MoveTicks 1マス移動するのに必要なtick数。0なら制限なし
+func (p *Player) MoveTicks() int {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.movement.moveTicks(p.effects[ItemTypeSpeedUp] > 0)
+}
+
+// requestMove 移動間隔が空いていれば移動し、空いていなければリクエストを保留してfalseを返す
+func (p *Player) requestMove(position Position, direction Direction) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.movement.cooldown > 0 {
+		p.movement.pending = &moveRequest{position: position, direction: direction}
+		return false
+	}
+	p.position = position
+	p.direction = direction
+	p.movement.cooldown = p.movement.moveTicks(p.effects[ItemTypeSpeedUp] > 0)
+	p.movement.pending = nil
+	return true
+}
+
+// tickMove 移動間隔を進める。移動できるようになった時に保留していたリクエストがあれば返す
+func (p *Player) tickMove() *moveRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.movement.cooldown > 0 {
+		p.movement.cooldown--
+	}
+	if p.movement.cooldown > 0 || p.movement.pending == nil {
+		return nil
+	}
+	request := p.movement.pending
+	p.movement.pending = nil
+	return request
+}
+
+// WeaponType 選択中の武器
+func (p *Player) WeaponType() WeaponType {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.weapon.weaponType
+}
+
+// selectWeapon 武器を持ち替える
+func (p *Player) selectWeapon(weaponType WeaponType) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.weapon.weaponType = weaponType
+}
+
+// canPlaceBomb 設置中のボムがactiveBombs個の時にさらにボムを設置できるか
+func (p *Player) canPlaceBomb(activeBombs int) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.weapon.canPlaceBomb(activeBombs, p.effects[ItemTypeBombCapacityUp] > 0)
+}
+
+// reload 弾のリロードを始める
+func (p *Player) reload() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.weapon.startReload()
+}
+
+// tickWeapon 発射間隔とリロードの残り時間を進める
+// 前回の通知からクライアントに見える武器の状態が変わっていればtrueを返す
+func (p *Player) tickWeapon(activeBombs int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.weapon.tick(activeBombs)
+	status := p.weapon.status(p.effects[ItemTypeBombCapacityUp] > 0)
+	changed := status != p.weapon.notified
+	p.weapon.notified = status
+	return changed
+}
+
+func (p *Player) Team() Team {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.team
+}
+
+func (p *Player) setTeam(team Team) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.team = team
+}
+
 func (p *Player) Move(position Position, direction Direction) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -64,6 +254,53 @@ func (p *Player) UpdateStatus(status PlayerStatus) {
 		return
 	}
 	p.status = status
+	if status == PlayerStatusDead {
+		p.hp = 0
+	}
+}
+
+// takeDamage HPを減らす。HPが0になったらtrueを返す
+func (p *Player) takeDamage(damage int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.hp = max(p.hp-damage, 0)
+	return p.hp == 0
+}
+
+// revive 新しいマッチのためにプレイヤーを復活させる
+func (p *Player) revive() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = PlayerStatusAlive
+	p.hp = MaxHP
+	p.deadTicks = 0
+	p.clearEffectsWithoutLock()
+	p.weapon.reset()
+	p.movement = newMovement(p.movement.config)
+}
+
+// respawn 倒されたプレイヤーを指定位置で復活させる
+func (p *Player) respawn(position Position) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = PlayerStatusAlive
+	p.hp = MaxHP
+	p.deadTicks = 0
+	p.clearEffectsWithoutLock()
+	p.weapon.reset()
+	p.movement = newMovement(p.movement.config)
+	p.position = position
+}
+
+// tickDead DEADであれば経過tick数を進めて返す。ALIVEなら0を返す
+func (p *Player) tickDead() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.status != PlayerStatusDead {
+		return 0
+	}
+	p.deadTicks++
+	return p.deadTicks
 }
 
 // プレイヤーの前方の座標を取得する
@@ -78,14 +315,31 @@ func (p *Player) FowardPosition() Position {
 func (p *Player) ToSharedPlayerState() *shared.PlayerState {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
+
+	effects := make([]*shared.PowerUpEffect, 0, len(p.effects))
+	for itemType, remaining := range p.effects {
+		effects = append(effects, &shared.PowerUpEffect{
+			Type:             itemType.ToSharedItemType(),
+			RemainingSeconds: int32(ticksToSeconds(remaining, p.ticksPerSecond)),
+		})
+	}
+	sort.Slice(effects, func(i, j int) bool { return effects[i].GetType() < effects[j].GetType() })
+
 	return &shared.PlayerState{
 		PlayerId: string(p.PlayerID),
 		Position: &shared.Position{
 			X: int32(p.position.X),
 			Y: int32(p.position.Y),
 		},
-		Direction: p.direction.ToSharedDirection(),
-		Status:    p.status.ToSharedStatus(),
+		Direction:   p.direction.ToSharedDirection(),
+		Status:      p.status.ToSharedStatus(),
+		Team:        p.team.ToSharedTeam(),
+		Hp:          int32(p.hp),
+		MaxHp:       MaxHP,
+		Effects:     effects,
+		Weapon:      p.weapon.toSharedWeaponState(p.effects[ItemTypeBombCapacityUp] > 0),
+		MoveTicks:   int32(p.movement.moveTicks(p.effects[ItemTypeSpeedUp] > 0)),
+		DisplayName: p.displayName,
 	}
 }
 
@@ -101,13 +355,46 @@ func (ps PlayerStatus) ToSharedStatus() shared.Status {
 }
 
 func (p *Player) OnCollideWith(other collidable, provider gameOperationProvider) bool {
-	switch other.(type) {
-	case *Bullet, *BombFire:
-		// 弾や爆弾の火と衝突したらプレイヤーはDEAD
-		// TODO: 本来はプレイヤーのステータスをPlayer struct自体が持ちたい
-		provider.UpdatePlayerStatus(p.PlayerID, PlayerStatusDead)
+	// パワーアップを拾ったら効果がかかる
+	// 同じtickに複数のプレイヤーが重なった場合は、最初に拾ったプレイヤーが盤面から取り除くので、残りのプレイヤーには効果がかからない
+	if powerUp, ok := other.(*PowerUp); ok {
+		if p.Status() == PlayerStatusDead || !provider.hasItem(powerUp.ID()) {
+			return false
+		}
+		p.addEffect(powerUp.Type(), powerUp.EffectDuration())
+		provider.emit(PowerUpCollected{Player: p, PowerUp: powerUp})
 		return true
-	default:
+	}
+
+	item, ok := other.(damager)
+	if !ok {
+		return false
+	}
+
+	// 既にDEADなら何もしない
+	if p.Status() == PlayerStatusDead {
+		return false
+	}
+	// 味方の攻撃が無効なら何もしない
+	attackerID := itemOwnerID(other)
+	if !provider.canDamage(attackerID, p.PlayerID) {
 		return false
 	}
+	// 爆発の火や貫通する弾からは1度だけダメージを受ける
+	if tracker, ok := other.(damageTracker); ok && !tracker.markDamaged(p.PlayerID) {
+		return false
+	}
+	// シールドがあればダメージを受けない
+	if p.HasEffect(ItemTypeShield) {
+		return false
+	}
+
+	// HPが0になったらプレイヤーはDEAD
+	if p.takeDamage(item.Damage()) {
+		// TODO: 本来はプレイヤーのステータスをPlayer struct自体が持ちたい
+		provider.UpdatePlayerStatus(p.PlayerID, PlayerStatusDead)
+		cause, _ := other.(Item)
+		provider.onPlayerKilled(p.PlayerID, attackerID, cause)
+	}
+	return true
 }
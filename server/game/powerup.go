@@ -0,0 +1,106 @@
+package game
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	PowerUpSpawnInterval = 10 * time.Second // 10秒ごとに出現
+	PowerUpLifetime      = 20 * time.Second // 取られなければ20秒で消滅
+	MaxPowerUps          = 3                // 盤面上に同時に存在できる数
+
+	PowerUpEffectDuration = 15 * time.Second // 効果の持続時間
+	ShieldEffectDuration  = 5 * time.Second  // シールドの持続時間
+
+	BombCapacityBonus = 1 // 同時に設置できるボムの増加数
+	BombRangeBonus    = 2 // 爆発の範囲の増加量
+)
+
+// PowerUpTypes 出現しうるパワーアップアイテムの種類
+var PowerUpTypes = []ItemType{ //nolint:gochecknoglobals
+	ItemTypeBombCapacityUp,
+	ItemTypeBombRangeUp,
+	ItemTypeSpeedUp,
+	ItemTypeShield,
+	ItemTypeRapidFire,
+}
+
+// PowerUp 拾うとプレイヤーに一定時間効果を与えるアイテムを表す
+type PowerUp struct {
+	id       ItemID
+	itemType ItemType
+	position Position
+
+	// 取られずに消えるまでのtick数
+	lifetimeTicks int
+	// 拾った時に効果が続くtick数
+	effectTicks int
+
+	// 現在のtick
+	tick int
+
+	mu sync.RWMutex `exhaustruct:"optional"`
+}
+
+var _ Item = (*PowerUp)(nil)
+
+func NewPowerUp(id ItemID, itemType ItemType, position Position, lifetimeTicks, effectTicks int) *PowerUp {
+	return &PowerUp{
+		id:            id,
+		itemType:      itemType,
+		position:      position,
+		lifetimeTicks: lifetimeTicks,
+		effectTicks:   effectTicks,
+		tick:          0,
+	}
+}
+
+func (pu *PowerUp) ID() ItemID {
+	return pu.id
+}
+
+func (pu *PowerUp) Type() ItemType {
+	return pu.itemType
+}
+
+func (pu *PowerUp) Position() Position {
+	pu.mu.RLock()
+	defer pu.mu.RUnlock()
+	return pu.position
+}
+
+// EffectDuration 拾った時に効果が続くtick数
+func (pu *PowerUp) EffectDuration() int {
+	return pu.effectTicks
+}
+
+// Update 状態を更新する
+func (pu *PowerUp) Update(provider gameOperationProvider) bool {
+	pu.mu.Lock()
+	defer pu.mu.Unlock()
+	pu.tick++
+
+	// 一定時間取られなければ消滅
+	if pu.tick >= pu.lifetimeTicks {
+		provider.RemoveItem(pu.id)
+		return true
+	}
+
+	return false
+}
+
+// OnCollideWith 他のオブジェクトと衝突した時の処理
+func (pu *PowerUp) OnCollideWith(other collidable, provider gameOperationProvider) bool {
+	switch other := other.(type) {
+	case *Player:
+		// 生きているプレイヤーに拾われたら消滅
+		if other.Status() == PlayerStatusDead {
+			return false
+		}
+		provider.RemoveItem(pu.id)
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,114 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_PowerUp(t *testing.T) {
+	game := NewGame(30, 30)
+
+	powerUp := newTestPowerUp("powerup1", ItemTypeShield, Position{X: 3, Y: 8})
+	game.addItem(powerUp)
+
+	assert.Equal(t, ItemID("powerup1"), powerUp.ID())
+	assert.Equal(t, ItemTypeShield, powerUp.Type())
+	assert.Equal(t, Position{X: 3, Y: 8}, powerUp.Position())
+
+	// 取られなければ一定時間で消滅する
+	for range testTimings().powerUpLifetime - 1 {
+		assert.False(t, powerUp.Update(game))
+	}
+	assert.True(t, powerUp.Update(game))
+	assert.Empty(t, game.GetItems())
+}
+
+func Test_Game_PowerUp(t *testing.T) {
+	t.Run("パワーアップを拾うと効果がかかり、時間経過で切れる", func(t *testing.T) {
+		game := NewGame(30, 30)
+		playerID := PlayerID("player1")
+		game.AddPlayer(playerID)
+		game.MovePlayer(playerID, Position{X: 3, Y: 8}, DirectionRight)
+
+		powerUpID := ItemID("powerup1")
+		game.addItem(newTestPowerUp(powerUpID, ItemTypeSpeedUp, Position{X: 3, Y: 8}))
+		game.Step()
+
+		player := game.GetPlayers()[playerID]
+		assert.True(t, player.HasEffect(ItemTypeSpeedUp))
+		assert.NotContains(t, game.GetItems(), powerUpID, "拾われたパワーアップは消える")
+		assert.Len(t, player.ToSharedPlayerState().GetEffects(), 1)
+
+		for range testTimings().powerUpEffect {
+			game.Step()
+		}
+		assert.False(t, player.HasEffect(ItemTypeSpeedUp))
+	})
+
+	t.Run("同じtickに2人が重なっても、効果がかかるのは1人だけ", func(t *testing.T) {
+		game := NewGame(30, 30)
+		game.AddPlayer("player1")
+		game.AddPlayer("player2")
+		game.MovePlayer("player1", Position{X: 3, Y: 8}, DirectionRight)
+		game.MovePlayer("player2", Position{X: 3, Y: 8}, DirectionLeft)
+		game.Step()
+
+		game.addItem(newTestPowerUp(ItemID("powerup1"), ItemTypeSpeedUp, Position{X: 3, Y: 8}))
+		events := game.Step()
+
+		collected := 0
+		for _, event := range events {
+			if _, ok := event.(PowerUpCollected); ok {
+				collected++
+			}
+		}
+		assert.Equal(t, 1, collected)
+		players := game.GetPlayers()
+		assert.NotEqual(t, players["player1"].HasEffect(ItemTypeSpeedUp), players["player2"].HasEffect(ItemTypeSpeedUp))
+	})
+
+	t.Run("シールドがあるとダメージを受けない", func(t *testing.T) {
+		game := NewGame(30, 30)
+		playerID := PlayerID("player1")
+		game.AddPlayer(playerID)
+		game.MovePlayer(playerID, Position{X: 3, Y: 8}, DirectionRight)
+		game.GetPlayers()[playerID].addEffect(ItemTypeShield, testTimings().shieldEffect)
+
+		game.AddBullet(Position{X: 3, Y: 8}, DirectionRight)
+		game.Step()
+
+		assert.Equal(t, MaxHP, game.GetPlayers()[playerID].HP())
+	})
+
+	t.Run("爆発範囲アップがあるとボムの爆発範囲が広がる", func(t *testing.T) {
+		game := NewGame(30, 30)
+		playerID := PlayerID("player1")
+		game.AddPlayer(playerID)
+		game.MovePlayer(playerID, Position{X: 10, Y: 10}, DirectionRight)
+		game.GetPlayers()[playerID].addEffect(ItemTypeBombRangeUp, testTimings().powerUpEffect)
+
+		game.PlaceBomb(playerID)
+		for range testTimings().bombExplosion {
+			game.Step()
+		}
+
+		assert.Len(t, game.GetItems(), 1+4*(BombFireRange+BombRangeBonus))
+	})
+
+	t.Run("対戦中は一定間隔でパワーアップが出現する", func(t *testing.T) {
+		game := NewGame(30, 30)
+
+		for range testTimings().powerUpSpawnInterval - 1 {
+			game.Step()
+		}
+		assert.Empty(t, game.GetItems())
+
+		game.Step()
+		items := game.GetItems()
+		assert.Len(t, items, 1)
+		for _, item := range items {
+			assert.Contains(t, PowerUpTypes, item.Type())
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package game
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+)
+
+// random ゲーム内で使う乱数とアイテムIDの生成器
+// 同じシードからは同じ順番で同じ値を生成するので、同じ入力を与えればゲームの結果も同じになる
+type random struct {
+	rng *rand.Rand
+	// 次に払い出すアイテムIDの連番
+	nextID uint64
+
+	mu sync.Mutex `exhaustruct:"optional"`
+}
+
+func newRandom(seed int64) *random {
+	return &random{
+		//nolint:gosec
+		rng:    rand.New(rand.NewSource(seed)),
+		nextID: 0,
+	}
+}
+
+// intn [0, n)の乱数を返す
+func (r *random) intn(n int) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Intn(n)
+}
+
+// float64 [0.0, 1.0)の乱数を返す
+func (r *random) float64() float64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.rng.Float64()
+}
+
+// newItemID ゲーム内で一意なアイテムIDを払い出す
+func (r *random) newItemID() ItemID {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	return ItemID(fmt.Sprintf("item-%d", r.nextID))
+}
@@ -0,0 +1,177 @@
+package game
+
+import (
+	"cmp"
+	"slices"
+)
+
+// 空間インデックスの1セルの一辺のマス数
+const spatialCellSize = 8
+
+// spatialIndex はオブジェクトを位置ごとに管理する空間インデックス
+// 追加・削除・移動のたびに差分だけを更新し、点・矩形・半径による検索を提供する
+// 検索結果はキーの順に並べるので、同じ状態からは常に同じ順番で結果が得られる
+// 排他制御はしないので、Gameのロックの中で操作する
+type spatialIndex[K cmp.Ordered, V any] struct {
+	// オブジェクトごとの登録されている位置
+	positions map[K]Position
+	// 位置ごとのオブジェクト。点の検索に使う
+	points map[Position]map[K]V
+	// セルごとのオブジェクト。範囲の検索に使う
+	cells map[Position]map[K]V
+}
+
+func newSpatialIndex[K cmp.Ordered, V any]() *spatialIndex[K, V] {
+	return &spatialIndex[K, V]{
+		positions: make(map[K]Position),
+		points:    make(map[Position]map[K]V),
+		cells:     make(map[Position]map[K]V),
+	}
+}
+
+// cellOf 位置が含まれるセルを返す
+func cellOf(position Position) Position {
+	return Position{X: floorDiv(position.X, spatialCellSize), Y: floorDiv(position.Y, spatialCellSize)}
+}
+
+// floorDiv 負の数でも切り捨てになる割り算
+func floorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && a < 0 {
+		q--
+	}
+	return q
+}
+
+// insert オブジェクトを登録する。既に登録されている場合は位置を更新する
+func (s *spatialIndex[K, V]) insert(key K, value V, position Position) {
+	s.remove(key)
+	s.positions[key] = position
+	addToBucket(s.points, position, key, value)
+	addToBucket(s.cells, cellOf(position), key, value)
+}
+
+// remove オブジェクトの登録を解除する
+func (s *spatialIndex[K, V]) remove(key K) {
+	position, ok := s.positions[key]
+	if !ok {
+		return
+	}
+	delete(s.positions, key)
+	removeFromBucket(s.points, position, key)
+	removeFromBucket(s.cells, cellOf(position), key)
+}
+
+// move 登録されているオブジェクトの位置を更新する。位置が変わっていなければ何もしない
+func (s *spatialIndex[K, V]) move(key K, position Position) {
+	current, ok := s.positions[key]
+	if !ok || current == position {
+		return
+	}
+	value := s.points[current][key]
+	s.insert(key, value, position)
+}
+
+// at 指定位置にあるオブジェクトを返す
+func (s *spatialIndex[K, V]) at(position Position) []V {
+	bucket := s.points[position]
+	if len(bucket) == 0 {
+		return nil
+	}
+	keys := make([]K, 0, len(bucket))
+	for key := range bucket {
+		keys = append(keys, key)
+	}
+	return s.valuesOf(keys, bucket)
+}
+
+// has 指定位置にオブジェクトがあるかどうか
+func (s *spatialIndex[K, V]) has(position Position) bool {
+	return len(s.points[position]) > 0
+}
+
+// inRect 矩形の範囲(境界を含む)にあるオブジェクトを返す
+func (s *spatialIndex[K, V]) inRect(minPos, maxPos Position) []V {
+	return s.collect(minPos, maxPos, func(Position) bool { return true })
+}
+
+// inRadius 中心からのユークリッド距離がradius以下の位置にあるオブジェクトを返す
+func (s *spatialIndex[K, V]) inRadius(center Position, radius int) []V {
+	minPos := Position{X: center.X - radius, Y: center.Y - radius}
+	maxPos := Position{X: center.X + radius, Y: center.Y + radius}
+	return s.collect(minPos, maxPos, func(position Position) bool {
+		dx, dy := position.X-center.X, position.Y-center.Y
+		return dx*dx+dy*dy <= radius*radius
+	})
+}
+
+// collect 矩形の範囲に重なるセルを走査し、条件を満たす位置にあるオブジェクトを返す
+func (s *spatialIndex[K, V]) collect(minPos, maxPos Position, match func(Position) bool) []V {
+	var keys []K
+	found := make(map[K]V)
+	minCell, maxCell := cellOf(minPos), cellOf(maxPos)
+	for cx := minCell.X; cx <= maxCell.X; cx++ {
+		for cy := minCell.Y; cy <= maxCell.Y; cy++ {
+			for key, value := range s.cells[Position{X: cx, Y: cy}] {
+				position := s.positions[key]
+				if position.X < minPos.X || position.X > maxPos.X || position.Y < minPos.Y || position.Y > maxPos.Y {
+					continue
+				}
+				if match(position) {
+					keys = append(keys, key)
+					found[key] = value
+				}
+			}
+		}
+	}
+	return s.valuesOf(keys, found)
+}
+
+// valuesOf キーの順に並べた値を返す
+func (s *spatialIndex[K, V]) valuesOf(keys []K, values map[K]V) []V {
+	if len(keys) == 0 {
+		return nil
+	}
+	slices.Sort(keys)
+	sorted := make([]V, 0, len(keys))
+	for _, key := range keys {
+		sorted = append(sorted, values[key])
+	}
+	return sorted
+}
+
+// crowdedPoints 2つ以上のオブジェクトが重なっている位置ごとのオブジェクトを、位置の順に返す
+func (s *spatialIndex[K, V]) crowdedPoints() [][]V {
+	var positions []Position
+	for position, bucket := range s.points {
+		if len(bucket) >= 2 {
+			positions = append(positions, position)
+		}
+	}
+	slices.SortFunc(positions, func(a, b Position) int {
+		return cmp.Or(cmp.Compare(a.X, b.X), cmp.Compare(a.Y, b.Y))
+	})
+
+	crowded := make([][]V, 0, len(positions))
+	for _, position := range positions {
+		crowded = append(crowded, s.at(position))
+	}
+	return crowded
+}
+
+func addToBucket[K cmp.Ordered, V any](buckets map[Position]map[K]V, position Position, key K, value V) {
+	bucket, ok := buckets[position]
+	if !ok {
+		bucket = make(map[K]V)
+		buckets[position] = bucket
+	}
+	bucket[key] = value
+}
+
+func removeFromBucket[K cmp.Ordered, V any](buckets map[Position]map[K]V, position Position, key K) {
+	bucket := buckets[position]
+	delete(bucket, key)
+	if len(bucket) == 0 {
+		delete(buckets, position)
+	}
+}
@@ -0,0 +1,232 @@
+package game
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sortedStrings(values []string) []string {
+	sort.Strings(values)
+	return values
+}
+
+func Test_spatialIndex(t *testing.T) {
+	t.Run("点で検索できる", func(t *testing.T) {
+		index := newSpatialIndex[string, string]()
+		index.insert("a", "a", Position{X: 1, Y: 1})
+		index.insert("b", "b", Position{X: 1, Y: 1})
+		index.insert("c", "c", Position{X: 2, Y: 1})
+
+		assert.Equal(t, []string{"a", "b"}, sortedStrings(index.at(Position{X: 1, Y: 1})))
+		assert.Equal(t, []string{"c"}, index.at(Position{X: 2, Y: 1}))
+		assert.Empty(t, index.at(Position{X: 3, Y: 1}))
+		assert.True(t, index.has(Position{X: 1, Y: 1}))
+		assert.False(t, index.has(Position{X: 3, Y: 1}))
+	})
+
+	t.Run("移動と削除が反映される", func(t *testing.T) {
+		index := newSpatialIndex[string, string]()
+		index.insert("a", "a", Position{X: 1, Y: 1})
+
+		// セルをまたいで移動する
+		index.move("a", Position{X: 20, Y: 20})
+		assert.False(t, index.has(Position{X: 1, Y: 1}))
+		assert.Equal(t, []string{"a"}, index.at(Position{X: 20, Y: 20}))
+		assert.Empty(t, index.inRect(Position{X: 0, Y: 0}, Position{X: 7, Y: 7}))
+
+		// 登録されていないオブジェクトは移動しない
+		index.move("b", Position{X: 1, Y: 1})
+		assert.False(t, index.has(Position{X: 1, Y: 1}))
+
+		index.remove("a")
+		assert.False(t, index.has(Position{X: 20, Y: 20}))
+		assert.Empty(t, index.inRect(Position{X: 0, Y: 0}, Position{X: 30, Y: 30}))
+	})
+
+	t.Run("矩形で検索できる", func(t *testing.T) {
+		index := newSpatialIndex[string, string]()
+		index.insert("inside", "inside", Position{X: 5, Y: 5})
+		index.insert("edge", "edge", Position{X: 10, Y: 3})
+		index.insert("outside", "outside", Position{X: 11, Y: 5})
+		index.insert("negative", "negative", Position{X: -1, Y: 5})
+
+		assert.Equal(t,
+			[]string{"edge", "inside"},
+			sortedStrings(index.inRect(Position{X: 0, Y: 3}, Position{X: 10, Y: 5})),
+		)
+		assert.Equal(t,
+			[]string{"negative"},
+			index.inRect(Position{X: -5, Y: 0}, Position{X: -1, Y: 10}),
+		)
+	})
+
+	t.Run("半径で検索できる", func(t *testing.T) {
+		index := newSpatialIndex[string, string]()
+		index.insert("center", "center", Position{X: 10, Y: 10})
+		index.insert("near", "near", Position{X: 13, Y: 14})
+		index.insert("corner", "corner", Position{X: 14, Y: 14})
+		index.insert("far", "far", Position{X: 20, Y: 10})
+
+		assert.Equal(t,
+			[]string{"center", "near"},
+			sortedStrings(index.inRadius(Position{X: 10, Y: 10}, 5)),
+		)
+	})
+
+	t.Run("重なっている位置を列挙できる", func(t *testing.T) {
+		index := newSpatialIndex[string, string]()
+		index.insert("a", "a", Position{X: 1, Y: 1})
+		index.insert("b", "b", Position{X: 1, Y: 1})
+		index.insert("c", "c", Position{X: 2, Y: 1})
+
+		crowded := index.crowdedPoints()
+		assert.Len(t, crowded, 1)
+		assert.Equal(t, []string{"a", "b"}, sortedStrings(crowded[0]))
+	})
+}
+
+func Test_Game_SpatialQueries(t *testing.T) {
+	t.Run("アイテムの追加・移動・削除がインデックスに反映される", func(t *testing.T) {
+		game := NewGame(30, 30)
+		bullet := NewBullet("bullet1", Position{X: 5, Y: 5}, DirectionRight)
+		game.addItem(bullet)
+
+		assert.Equal(t, []Item{bullet}, game.ItemsInRadius(Position{X: 5, Y: 5}, 0))
+
+		for range bullet.moveTick {
+			game.Step()
+		}
+		assert.Empty(t, game.ItemsInRadius(Position{X: 5, Y: 5}, 0))
+		assert.Equal(t, []Item{bullet}, game.ItemsInRadius(Position{X: 6, Y: 5}, 0))
+
+		game.RemoveItem("bullet1")
+		assert.Empty(t, game.ItemsInRadius(Position{X: 6, Y: 5}, 0))
+	})
+
+	t.Run("範囲内のアイテムとプレイヤーを取得できる", func(t *testing.T) {
+		game := NewGame(30, 30)
+		game.addItem(NewBullet("near", Position{X: 11, Y: 10}, DirectionUp))
+		game.addItem(NewBullet("far", Position{X: 25, Y: 25}, DirectionUp))
+		game.AddPlayer("player1")
+		game.AddPlayer("player2")
+		game.MovePlayer("player1", Position{X: 10, Y: 12}, DirectionUp)
+		game.MovePlayer("player2", Position{X: 20, Y: 20}, DirectionUp)
+
+		items := game.ItemsInRadius(Position{X: 10, Y: 10}, 3)
+		assert.Len(t, items, 1)
+		assert.Equal(t, ItemID("near"), items[0].ID())
+
+		players := game.PlayersInRadius(Position{X: 10, Y: 10}, 3)
+		assert.Len(t, players, 1)
+		assert.Equal(t, PlayerID("player1"), players[0].PlayerID)
+
+		game.RemovePlayer("player1")
+		assert.Empty(t, game.PlayersInRadius(Position{X: 10, Y: 10}, 3))
+	})
+}
+
+// newCrowdedGame 多数のプレイヤーとアイテムがいる広い盤面を作る
+func newCrowdedGame(size, players, items int) *Game {
+	game := NewGame(size, size)
+	for i := range players {
+		playerID := PlayerID(fmt.Sprintf("player%d", i))
+		game.AddPlayer(playerID)
+		game.MovePlayer(playerID, Position{X: (i * 7) % size, Y: (i * 13) % size}, DirectionUp)
+	}
+	for i := range items {
+		position := Position{X: (i * 11) % size, Y: (i * 17) % size}
+		game.addItem(NewBullet(ItemID(fmt.Sprintf("bullet%d", i)), position, DirectionUp))
+	}
+	return game
+}
+
+// detectCollisionsNaive 空間インデックスを使わず、毎回全アイテムから位置のマップを作って衝突を検出する
+func (g *Game) detectCollisionsNaive() []collision {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	var collisions []collision
+	itemPosMap := make(map[Position][]Item)
+	for _, item := range g.Items {
+		itemPosMap[item.Position()] = append(itemPosMap[item.Position()], item)
+	}
+	for _, player := range g.Players {
+		for _, item := range itemPosMap[player.Position()] {
+			collisions = append(collisions, collision{Player: player, Item: item})
+		}
+	}
+	return collisions
+}
+
+// findEmptyPositionNaive 空間インデックスを使わず、毎回埋まっている位置の一覧を作って空いている位置を探す
+func (g *Game) findEmptyPositionNaive() bool {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	occupied := make(map[Position]bool)
+	for _, player := range g.Players {
+		occupied[player.Position()] = true
+	}
+	for _, item := range g.Items {
+		occupied[item.Position()] = true
+	}
+	return !occupied[Position{X: 0, Y: 0}]
+}
+
+func Benchmark_DetectCollisions(b *testing.B) {
+	game := newCrowdedGame(200, 100, 2000)
+
+	b.Run("naive", func(b *testing.B) {
+		for range b.N {
+			game.detectCollisionsNaive()
+		}
+	})
+	b.Run("spatialIndex", func(b *testing.B) {
+		for range b.N {
+			game.detectCollisions()
+		}
+	})
+}
+
+func Benchmark_FindEmptyPosition(b *testing.B) {
+	game := newCrowdedGame(200, 100, 2000)
+
+	b.Run("naive", func(b *testing.B) {
+		for range b.N {
+			game.findEmptyPositionNaive()
+		}
+	})
+	b.Run("spatialIndex", func(b *testing.B) {
+		for range b.N {
+			game.mu.RLock()
+			game.findEmptyPositionWithoutLock()
+			game.mu.RUnlock()
+		}
+	})
+}
+
+func Benchmark_ItemsInRadius(b *testing.B) {
+	game := newCrowdedGame(200, 100, 2000)
+	center := Position{X: 100, Y: 100}
+	const radius = 10
+
+	b.Run("naive", func(b *testing.B) {
+		for range b.N {
+			var items []Item
+			for _, item := range game.GetItems() {
+				dx, dy := item.Position().X-center.X, item.Position().Y-center.Y
+				if dx*dx+dy*dy <= radius*radius {
+					items = append(items, item)
+				}
+			}
+		}
+	})
+	b.Run("spatialIndex", func(b *testing.B) {
+		for range b.N {
+			game.ItemsInRadius(center, radius)
+		}
+	})
+}
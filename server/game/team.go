@@ -0,0 +1,66 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/shibayu36/terminal-shooter/shared"
+)
+
+// Team プレイヤーの所属チーム
+type Team string
+
+const (
+	TeamNone Team = "" // チームに所属していない
+	TeamRed  Team = "red"
+	TeamBlue Team = "blue"
+)
+
+// ToSharedTeam Teamをshared.Teamに変換する
+func (t Team) ToSharedTeam() shared.Team {
+	switch t {
+	case TeamNone:
+		return shared.Team_NO_TEAM
+	case TeamRed:
+		return shared.Team_RED
+	case TeamBlue:
+		return shared.Team_BLUE
+	default:
+		panic(fmt.Sprintf("invalid team: %s", t))
+	}
+}
+
+// Opponent 相手チームを返す
+func (t Team) Opponent() Team {
+	switch t {
+	case TeamRed:
+		return TeamBlue
+	case TeamBlue:
+		return TeamRed
+	default:
+		return TeamNone
+	}
+}
+
+// shared.TeamをTeamに変換する
+func FromSharedTeam(t shared.Team) (Team, error) {
+	switch t {
+	case shared.Team_NO_TEAM:
+		return TeamNone, nil
+	case shared.Team_RED:
+		return TeamRed, nil
+	case shared.Team_BLUE:
+		return TeamBlue, nil
+	default:
+		return "", errors.Newf("invalid team: %d", t)
+	}
+}
+
+// countTeamMembers チームごとの人数を数える
+func countTeamMembers(players map[PlayerID]*Player) map[Team]int {
+	counts := make(map[Team]int)
+	for _, player := range players {
+		counts[player.Team()]++
+	}
+	return counts
+}
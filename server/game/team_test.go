@@ -0,0 +1,93 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTeamGame(friendlyFire bool, match MatchConfig) *Game {
+	return NewGameWithConfig(Config{
+		Width:  30,
+		Height: 30,
+		Match:  match,
+		Mode:   &TeamDeathmatch{AllowFriendlyFire: friendlyFire},
+	})
+}
+
+func Test_Game_AddPlayer_Team(t *testing.T) {
+	game := newTeamGame(false, MatchConfig{})
+	game.AddPlayer("player1")
+	game.AddPlayer("player2")
+	game.AddPlayer("player3")
+
+	assert.Equal(t, TeamRed, game.GetPlayers()["player1"].Team())
+	assert.Equal(t, TeamBlue, game.GetPlayers()["player2"].Team())
+	assert.Equal(t, TeamRed, game.GetPlayers()["player3"].Team())
+
+	// Deathmatchではチームに所属しない
+	deathmatch := NewGame(30, 30)
+	deathmatch.AddPlayer("player1")
+	assert.Equal(t, TeamNone, deathmatch.GetPlayers()["player1"].Team())
+}
+
+func Test_Game_SwitchTeam(t *testing.T) {
+	waiting := MatchConfig{MinPlayers: 10, CountdownTicks: 0, TimeLimitTicks: 0, ScoreLimit: 0, ResultTicks: 0}
+
+	t.Run("人数の少ないチームに移動できる", func(t *testing.T) {
+		game := newTeamGame(false, waiting)
+		game.AddPlayer("player1") // red
+		game.AddPlayer("player2") // blue
+		game.AddPlayer("player3") // red
+
+		assert.NotNil(t, game.SwitchTeam("player3", TeamBlue))
+		assert.Equal(t, TeamBlue, game.GetPlayers()["player3"].Team())
+	})
+
+	t.Run("人数差が広がる移動はできない", func(t *testing.T) {
+		game := newTeamGame(false, waiting)
+		game.AddPlayer("player1") // red
+		game.AddPlayer("player2") // blue
+
+		assert.Nil(t, game.SwitchTeam("player1", TeamBlue))
+		assert.Equal(t, TeamRed, game.GetPlayers()["player1"].Team())
+	})
+
+	t.Run("対戦中は移動できない", func(t *testing.T) {
+		game := newTeamGame(false, MatchConfig{})
+		game.AddPlayer("player1") // red
+		game.AddPlayer("player2") // blue
+		game.AddPlayer("player3") // red
+
+		assert.Nil(t, game.SwitchTeam("player3", TeamBlue))
+	})
+}
+
+func Test_Game_FriendlyFire(t *testing.T) {
+	setup := func(friendlyFire bool) (*Game, ItemID) {
+		game := newTeamGame(friendlyFire, MatchConfig{})
+		game.AddPlayer("player1") // red
+		game.AddPlayer("player2") // blue
+		game.AddPlayer("player3") // red
+
+		game.MovePlayer("player1", Position{X: 2, Y: 3}, DirectionRight)
+		game.MovePlayer("player3", Position{X: 3, Y: 3}, DirectionLeft)
+		game.GetPlayers()["player3"].hp = BulletDamage
+		bulletID := game.ShootBullet("player1")
+		game.Step()
+		return game, bulletID
+	}
+
+	t.Run("フレンドリーファイアが無効なら味方の弾はすり抜ける", func(t *testing.T) {
+		game, bulletID := setup(false)
+		assert.Equal(t, PlayerStatusAlive, game.GetPlayers()["player3"].Status())
+		assert.Contains(t, game.GetItems(), bulletID)
+	})
+
+	t.Run("フレンドリーファイアが有効なら味方の弾も当たる", func(t *testing.T) {
+		game, bulletID := setup(true)
+		assert.Equal(t, PlayerStatusDead, game.GetPlayers()["player3"].Status())
+		assert.NotContains(t, game.GetItems(), bulletID)
+		assert.Equal(t, -1, game.Match().Scores()["player1"], "味方を倒したので減点")
+	})
+}
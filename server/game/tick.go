@@ -0,0 +1,76 @@
+package game
+
+import "time"
+
+// tick数を秒数に切り上げる
+func ticksToSeconds(ticks, ticksPerSecond int) int {
+	return (ticks + ticksPerSecond - 1) / ticksPerSecond
+}
+
+// durationToTicks 実時間の長さをtick数に直す。1tickより短くはしない
+func durationToTicks(d time.Duration, ticksPerSecond int) int {
+	return max(1, int(d*time.Duration(ticksPerSecond)/time.Second))
+}
+
+// timings 実時間で決めている長さを、ゲームの1秒あたりのtick数でtick数に直したもの
+// 1秒あたりのtick数を変えても、復活やパワーアップなどの実時間での長さは変わらない
+type timings struct {
+	respawn              int
+	bombExplosion        int
+	bombFire             int
+	powerUpSpawnInterval int
+	powerUpLifetime      int
+	powerUpEffect        int
+	shieldEffect         int
+}
+
+func newTimings(ticksPerSecond int) timings {
+	return timings{
+		respawn:              durationToTicks(RespawnDelay, ticksPerSecond),
+		bombExplosion:        durationToTicks(BombExplosionDelay, ticksPerSecond),
+		bombFire:             durationToTicks(BombFireDuration, ticksPerSecond),
+		powerUpSpawnInterval: durationToTicks(PowerUpSpawnInterval, ticksPerSecond),
+		powerUpLifetime:      durationToTicks(PowerUpLifetime, ticksPerSecond),
+		powerUpEffect:        durationToTicks(PowerUpEffectDuration, ticksPerSecond),
+		shieldEffect:         durationToTicks(ShieldEffectDuration, ticksPerSecond),
+	}
+}
+
+// LoopConfig 更新ループの設定
+// ゼロ値の場合は遅れを取り戻さず、間に合わなかったtickは飛ばす
+type LoopConfig struct {
+	// 処理が遅れた時に、本来のtickに追いつくために1回の起床で追加で進めるtick数の上限
+	MaxCatchUpTicks int
+}
+
+// tickClock 更新ループの開始時刻からの経過時間で、何tick進めるべきかを管理する
+// time.Tickerは処理が間に合わないとtickを黙って捨てるので、経過時間から遅れを数える
+type tickClock struct {
+	interval   time.Duration
+	maxCatchUp int
+	start      time.Time
+	// 実行したか、間に合わずに飛ばしたtick数
+	ticks int64
+}
+
+func newTickClock(interval time.Duration, maxCatchUp int, start time.Time) *tickClock {
+	return &tickClock{
+		interval:   interval,
+		maxCatchUp: maxCatchUp,
+		start:      start,
+		ticks:      0,
+	}
+}
+
+// advance nowまでに進めるべきtickのうち、実行するtick数と追いつけずに飛ばすtick数を返す
+func (c *tickClock) advance(now time.Time) (int, int) {
+	due := int64(now.Sub(c.start) / c.interval)
+	behind := due - c.ticks
+	if behind <= 0 {
+		return 0, 0
+	}
+	c.ticks = due
+
+	steps := min(behind, int64(1+c.maxCatchUp))
+	return int(steps), int(behind - steps)
+}
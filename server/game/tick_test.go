@@ -0,0 +1,57 @@
+package game
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_tickClock(t *testing.T) {
+	start := time.Unix(0, 0)
+	at := func(ms int) time.Time {
+		return start.Add(time.Duration(ms) * time.Millisecond)
+	}
+
+	t.Run("間隔どおりに起きれば1tickずつ進める", func(t *testing.T) {
+		clock := newTickClock(10*time.Millisecond, 0, start)
+
+		steps, missed := clock.advance(at(10))
+		assert.Equal(t, 1, steps)
+		assert.Equal(t, 0, missed)
+
+		// 次のtickの時刻になっていなければ進めない
+		steps, missed = clock.advance(at(15))
+		assert.Equal(t, 0, steps)
+		assert.Equal(t, 0, missed)
+
+		steps, missed = clock.advance(at(20))
+		assert.Equal(t, 1, steps)
+		assert.Equal(t, 0, missed)
+	})
+
+	t.Run("遅れた分は上限まで追加で進め、残りは飛ばす", func(t *testing.T) {
+		clock := newTickClock(10*time.Millisecond, 2, start)
+
+		steps, missed := clock.advance(at(30))
+		assert.Equal(t, 3, steps)
+		assert.Equal(t, 0, missed)
+
+		steps, missed = clock.advance(at(90))
+		assert.Equal(t, 3, steps)
+		assert.Equal(t, 3, missed)
+
+		// 飛ばしたtickは取り戻さない
+		steps, missed = clock.advance(at(100))
+		assert.Equal(t, 1, steps)
+		assert.Equal(t, 0, missed)
+	})
+
+	t.Run("追いつかない設定なら遅れた分は全て飛ばす", func(t *testing.T) {
+		clock := newTickClock(10*time.Millisecond, 0, start)
+
+		steps, missed := clock.advance(at(50))
+		assert.Equal(t, 1, steps)
+		assert.Equal(t, 4, missed)
+	})
+}
@@ -0,0 +1,189 @@
+package game
+
+import (
+	"github.com/shibayu36/terminal-shooter/shared"
+)
+
+// ラピッドファイア中の発射間隔の短縮率
+const RapidFireCooldownDivisor = 3
+
+// WeaponConfig プレイヤーの武器の制限と性能
+// ゼロ値の場合は連射も弾数もボムの数も制限せず、ボムと弾の性能は初期値を使う
+type WeaponConfig struct {
+	// 弾を発射してから次の弾を発射できるまでのtick数
+	BulletCooldownTicks int
+	// 弾倉の弾数。0なら無制限
+	MaxAmmo int
+	// 弾切れからリロードが完了するまでのtick数
+	ReloadTicks int
+	// 同時に設置できるボムの数。0なら無制限
+	MaxBombs int
+	// ボムを設置してから爆発するまでのtick数。0ならBombExplosionDelayをtick数に直した値
+	BombExplosionTicks int
+	// ボムの爆発の範囲。0ならBombFireRange
+	BombFireRange int
+	// 武器ごとの弾が1マス進むのにかかるtick数。指定しなかった武器はWeaponSpecの値を使う
+	BulletMoveTicks map[WeaponType]int
+}
+
+// bombFireRange ボムの爆発の範囲
+func (c WeaponConfig) bombFireRange() int {
+	if c.BombFireRange > 0 {
+		return c.BombFireRange
+	}
+	return BombFireRange
+}
+
+// bulletMoveTicks 武器の弾が1マス進むのにかかるtick数
+func (c WeaponConfig) bulletMoveTicks(weaponType WeaponType) int {
+	if moveTicks := c.BulletMoveTicks[weaponType]; moveTicks > 0 {
+		return moveTicks
+	}
+	return weaponType.Spec().MoveTicks
+}
+
+// weapon プレイヤーごとの武器の状態
+// Playerのロックの中で操作する
+type weapon struct {
+	config WeaponConfig
+	// 選択中の武器
+	weaponType WeaponType
+
+	// 次の弾を発射できるまでの残りtick数
+	cooldown int
+	// 残りの弾数
+	ammo int
+	// リロード完了までの残りtick数。0ならリロード中ではない
+	reload int
+	// 設置中のボムの数
+	activeBombs int
+
+	// 最後にクライアントへ通知した状態
+	notified weaponStatus
+}
+
+// weaponStatus クライアントに通知する武器の状態
+type weaponStatus struct {
+	weaponType  WeaponType
+	bulletReady bool
+	ammo        int
+	reloading   bool
+	activeBombs int
+	maxBombs    int
+}
+
+func newWeapon(config WeaponConfig) weapon {
+	w := weapon{
+		config:      config,
+		weaponType:  WeaponTypePistol,
+		cooldown:    0,
+		ammo:        config.MaxAmmo,
+		reload:      0,
+		activeBombs: 0,
+		notified:    weaponStatus{weaponType: "", bulletReady: false, ammo: 0, reloading: false, activeBombs: 0, maxBombs: 0},
+	}
+	w.notified = w.status(false)
+	return w
+}
+
+// reset 選択中の武器はそのままで、弾数や発射間隔を初期状態に戻す
+func (w *weapon) reset() {
+	weaponType := w.weaponType
+	*w = newWeapon(w.config)
+	w.weaponType = weaponType
+	w.notified = w.status(false)
+}
+
+// canShoot 弾を発射できるかどうか
+func (w *weapon) canShoot() bool {
+	return w.cooldown == 0 && w.reload == 0
+}
+
+// shoot 弾を1発消費する。発射できない場合はfalseを返す
+func (w *weapon) shoot(rapidFire bool) bool {
+	if !w.canShoot() {
+		return false
+	}
+
+	w.cooldown = w.config.BulletCooldownTicks
+	if rapidFire {
+		w.cooldown /= RapidFireCooldownDivisor
+	}
+
+	if w.config.MaxAmmo > 0 {
+		w.ammo--
+		// 弾切れになったら自動でリロードする
+		if w.ammo <= 0 {
+			w.startReload()
+		}
+	}
+	return true
+}
+
+// startReload リロードを始める。弾数無制限や既にリロード中の場合は何もしない
+func (w *weapon) startReload() {
+	if w.config.MaxAmmo == 0 || w.reload > 0 || w.ammo == w.config.MaxAmmo {
+		return
+	}
+	if w.config.ReloadTicks <= 0 {
+		w.ammo = w.config.MaxAmmo
+		return
+	}
+	w.reload = w.config.ReloadTicks
+}
+
+// maxBombs 同時に設置できるボムの数。0なら無制限
+func (w *weapon) maxBombs(bombCapacityUp bool) int {
+	if w.config.MaxBombs == 0 {
+		return 0
+	}
+	if bombCapacityUp {
+		return w.config.MaxBombs + BombCapacityBonus
+	}
+	return w.config.MaxBombs
+}
+
+// canPlaceBomb 設置中のボムの数がactiveBombsの時にさらにボムを設置できるか
+func (w *weapon) canPlaceBomb(activeBombs int, bombCapacityUp bool) bool {
+	limit := w.maxBombs(bombCapacityUp)
+	return limit == 0 || activeBombs < limit
+}
+
+// tick 発射間隔とリロードの残り時間を進める
+func (w *weapon) tick(activeBombs int) {
+	w.activeBombs = activeBombs
+	if w.cooldown > 0 {
+		w.cooldown--
+	}
+	if w.reload > 0 {
+		w.reload--
+		if w.reload == 0 {
+			w.ammo = w.config.MaxAmmo
+		}
+	}
+}
+
+func (w *weapon) status(bombCapacityUp bool) weaponStatus {
+	return weaponStatus{
+		weaponType:  w.weaponType,
+		bulletReady: w.canShoot(),
+		ammo:        w.ammo,
+		reloading:   w.reload > 0,
+		activeBombs: w.activeBombs,
+		maxBombs:    w.maxBombs(bombCapacityUp),
+	}
+}
+
+// toSharedWeaponState 武器の状態をshared.WeaponStateに変換する
+func (w *weapon) toSharedWeaponState(bombCapacityUp bool) *shared.WeaponState {
+	status := w.status(bombCapacityUp)
+	return &shared.WeaponState{
+		BulletReady: status.bulletReady,
+		Ammo:        int32(status.ammo),
+		MaxAmmo:     int32(w.config.MaxAmmo),
+		Reloading:   status.reloading,
+		ActiveBombs: int32(status.activeBombs),
+		MaxBombs:    int32(status.maxBombs),
+		Type:        status.weaponType.ToSharedWeaponType(),
+	}
+}
@@ -0,0 +1,157 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Game_Weapon(t *testing.T) {
+	newWeaponGame := func(weapon WeaponConfig) *Game {
+		return NewGameWithConfig(Config{
+			Width:  30,
+			Height: 30,
+			Weapon: weapon,
+		})
+	}
+
+	t.Run("発射間隔が空くまで次の弾を発射できない", func(t *testing.T) {
+		game := newWeaponGame(WeaponConfig{BulletCooldownTicks: 3})
+		game.AddPlayer("player1")
+		game.MovePlayer("player1", Position{X: 10, Y: 10}, DirectionRight)
+
+		assert.NotEmpty(t, game.ShootBullet("player1"))
+		assert.Empty(t, game.ShootBullet("player1"))
+
+		for range 3 {
+			game.Step()
+		}
+		assert.NotEmpty(t, game.ShootBullet("player1"))
+	})
+
+	t.Run("ラピッドファイア中は発射間隔が短くなる", func(t *testing.T) {
+		game := newWeaponGame(WeaponConfig{BulletCooldownTicks: 3 * RapidFireCooldownDivisor})
+		game.AddPlayer("player1")
+		game.MovePlayer("player1", Position{X: 10, Y: 10}, DirectionRight)
+		game.GetPlayers()["player1"].addEffect(ItemTypeRapidFire, testTimings().powerUpEffect)
+
+		assert.NotEmpty(t, game.ShootBullet("player1"))
+		for range 3 {
+			game.Step()
+		}
+		assert.NotEmpty(t, game.ShootBullet("player1"))
+	})
+
+	t.Run("弾切れになるとリロードが終わるまで発射できない", func(t *testing.T) {
+		game := newWeaponGame(WeaponConfig{MaxAmmo: 2, ReloadTicks: 5})
+		game.AddPlayer("player1")
+		game.MovePlayer("player1", Position{X: 10, Y: 10}, DirectionRight)
+		player := game.GetPlayers()["player1"]
+
+		assert.NotEmpty(t, game.ShootBullet("player1"))
+		assert.NotEmpty(t, game.ShootBullet("player1"))
+		assert.Empty(t, game.ShootBullet("player1"))
+
+		weapon := player.ToSharedPlayerState().GetWeapon()
+		assert.True(t, weapon.GetReloading())
+		assert.False(t, weapon.GetBulletReady())
+		assert.EqualValues(t, 0, weapon.GetAmmo())
+
+		for range 5 {
+			game.Step()
+		}
+		weapon = player.ToSharedPlayerState().GetWeapon()
+		assert.True(t, weapon.GetBulletReady())
+		assert.EqualValues(t, 2, weapon.GetAmmo())
+	})
+
+	t.Run("弾が残っていても手動でリロードできる", func(t *testing.T) {
+		game := newWeaponGame(WeaponConfig{MaxAmmo: 3, ReloadTicks: 5})
+		game.AddPlayer("player1")
+		game.MovePlayer("player1", Position{X: 10, Y: 10}, DirectionRight)
+
+		game.ShootBullet("player1")
+		game.Reload("player1")
+		assert.Empty(t, game.ShootBullet("player1"))
+
+		for range 5 {
+			game.Step()
+		}
+		assert.EqualValues(t, 3, game.GetPlayers()["player1"].ToSharedPlayerState().GetWeapon().GetAmmo())
+	})
+
+	t.Run("同時に設置できるボムの数が制限され、爆発すると再び設置できる", func(t *testing.T) {
+		game := newWeaponGame(WeaponConfig{MaxBombs: 1})
+		game.AddPlayer("player1")
+		game.MovePlayer("player1", Position{X: 10, Y: 10}, DirectionRight)
+
+		assert.NotEmpty(t, game.PlaceBomb("player1"))
+		game.MovePlayer("player1", Position{X: 20, Y: 20}, DirectionRight)
+		assert.Empty(t, game.PlaceBomb("player1"))
+
+		for range testTimings().bombExplosion {
+			game.Step()
+		}
+		assert.NotEmpty(t, game.PlaceBomb("player1"))
+	})
+
+	t.Run("ボム所持数アップで設置できるボムの数が増える", func(t *testing.T) {
+		game := newWeaponGame(WeaponConfig{MaxBombs: 1})
+		game.AddPlayer("player1")
+		game.GetPlayers()["player1"].addEffect(ItemTypeBombCapacityUp, testTimings().powerUpEffect)
+
+		game.MovePlayer("player1", Position{X: 10, Y: 10}, DirectionRight)
+		assert.NotEmpty(t, game.PlaceBomb("player1"))
+		game.MovePlayer("player1", Position{X: 20, Y: 20}, DirectionRight)
+		assert.NotEmpty(t, game.PlaceBomb("player1"))
+		game.MovePlayer("player1", Position{X: 25, Y: 25}, DirectionRight)
+		assert.Empty(t, game.PlaceBomb("player1"))
+	})
+
+	t.Run("ボムが爆発するまでの時間と爆発の範囲を設定できる", func(t *testing.T) {
+		game := newWeaponGame(WeaponConfig{BombExplosionTicks: 10, BombFireRange: 1})
+		game.AddPlayer("player1")
+		game.MovePlayer("player1", Position{X: 10, Y: 10}, DirectionRight)
+		bombID := game.PlaceBomb("player1")
+		game.MovePlayer("player1", Position{X: 20, Y: 20}, DirectionRight)
+
+		for range 9 {
+			game.Step()
+		}
+		assert.Contains(t, game.GetItems(), bombID)
+
+		game.Step()
+		assert.NotContains(t, game.GetItems(), bombID)
+		assert.Len(t, game.GetItems(), 5, "中心と上下左右1マスに火が出る")
+	})
+
+	t.Run("武器ごとに弾の速さを設定できる", func(t *testing.T) {
+		game := newWeaponGame(WeaponConfig{BulletMoveTicks: map[WeaponType]int{WeaponTypePistol: 1}})
+		game.AddPlayer("player1")
+		game.MovePlayer("player1", Position{X: 10, Y: 10}, DirectionRight)
+
+		bulletID := game.ShootBullet("player1")
+		for range 3 {
+			game.Step()
+		}
+		assert.Equal(t, Position{X: 14, Y: 10}, game.GetItems()[bulletID].Position())
+	})
+
+	t.Run("武器の状態が変わるとプレイヤーの更新が通知される", func(t *testing.T) {
+		game := newWeaponGame(WeaponConfig{BulletCooldownTicks: 2})
+		game.AddPlayer("player1")
+		game.MovePlayer("player1", Position{X: 10, Y: 0}, DirectionUp)
+
+		player := game.GetPlayers()["player1"]
+		game.Step()
+
+		// 盤面外への発射なのでアイテムは増えないが、武器の状態は変わる
+		game.ShootBullet("player1")
+		assert.Equal(t, []Event{PlayerUpdated{Player: player}}, game.Step())
+
+		// 発射間隔が明けた時も通知される
+		assert.Equal(t, []Event{PlayerUpdated{Player: player}}, game.Step())
+
+		assert.Empty(t, game.Step())
+	})
+}
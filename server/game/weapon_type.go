@@ -0,0 +1,90 @@
+package game
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/shibayu36/terminal-shooter/shared"
+)
+
+// WeaponType 弾を発射する武器の種類
+type WeaponType string
+
+const (
+	WeaponTypePistol   WeaponType = "pistol"   // 標準的な武器
+	WeaponTypeRifle    WeaponType = "rifle"    // 弾が速い
+	WeaponTypeShotgun  WeaponType = "shotgun"  // 3発の弾が扇状に広がる。射程が短い
+	WeaponTypePiercing WeaponType = "piercing" // プレイヤーを貫通する
+)
+
+// WeaponSpec 武器の性能
+type WeaponSpec struct {
+	// 弾が1マス進むのにかかるtick数
+	MoveTicks int
+	// 弾が消えるまでに進むマス数。0なら盤面外に出るまで進む
+	Range int
+	// 当たった時のダメージ
+	Damage int
+	// 1回の発射で扇状に広がる弾の数。1なら真っ直ぐ1発
+	Pellets int
+	// プレイヤーに当たっても消えずに貫通するか
+	Piercing bool
+}
+
+// weaponSpecs 武器の種類ごとの性能
+var weaponSpecs = map[WeaponType]WeaponSpec{ //nolint:gochecknoglobals
+	WeaponTypePistol:   {MoveTicks: 30, Range: 0, Damage: BulletDamage, Pellets: 1, Piercing: false},
+	WeaponTypeRifle:    {MoveTicks: 10, Range: 0, Damage: 20, Pellets: 1, Piercing: false},
+	WeaponTypeShotgun:  {MoveTicks: 20, Range: 5, Damage: 20, Pellets: 3, Piercing: false},
+	WeaponTypePiercing: {MoveTicks: 20, Range: 0, Damage: 30, Pellets: 1, Piercing: true},
+}
+
+// ParseWeaponType 名前から武器の種類を取得する
+func ParseWeaponType(name string) (WeaponType, error) {
+	weaponType := WeaponType(name)
+	if _, ok := weaponSpecs[weaponType]; !ok {
+		return "", errors.Newf("unknown weapon type: %s", name)
+	}
+	return weaponType, nil
+}
+
+// Spec 武器の性能を取得する
+func (wt WeaponType) Spec() WeaponSpec {
+	spec, ok := weaponSpecs[wt]
+	if !ok {
+		panic(fmt.Sprintf("invalid weapon type: %s", wt))
+	}
+	return spec
+}
+
+// ToSharedWeaponType WeaponTypeをshared.WeaponTypeに変換する
+func (wt WeaponType) ToSharedWeaponType() shared.WeaponType {
+	switch wt {
+	case WeaponTypePistol:
+		return shared.WeaponType_PISTOL
+	case WeaponTypeRifle:
+		return shared.WeaponType_RIFLE
+	case WeaponTypeShotgun:
+		return shared.WeaponType_SHOTGUN
+	case WeaponTypePiercing:
+		return shared.WeaponType_PIERCING
+	default:
+		panic(fmt.Sprintf("invalid weapon type: %s", wt))
+	}
+}
+
+// shared.WeaponTypeをWeaponTypeに変換する
+func FromSharedWeaponType(wt shared.WeaponType) (WeaponType, error) {
+	switch wt {
+	case shared.WeaponType_PISTOL:
+		return WeaponTypePistol, nil
+	case shared.WeaponType_RIFLE:
+		return WeaponTypeRifle, nil
+	case shared.WeaponType_SHOTGUN:
+		return WeaponTypeShotgun, nil
+	case shared.WeaponType_PIERCING:
+		return WeaponTypePiercing, nil
+	default:
+		return "", errors.Newf("invalid weapon type: %d", wt)
+	}
+}
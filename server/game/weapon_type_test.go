@@ -0,0 +1,99 @@
+package game
+
+import (
+	"testing"
+
+	"github.com/shibayu36/terminal-shooter/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WeaponType(t *testing.T) {
+	for _, weaponType := range []WeaponType{WeaponTypePistol, WeaponTypeRifle, WeaponTypeShotgun, WeaponTypePiercing} {
+		converted, err := FromSharedWeaponType(weaponType.ToSharedWeaponType())
+		require.NoError(t, err)
+		assert.Equal(t, weaponType, converted)
+	}
+
+	_, err := FromSharedWeaponType(shared.WeaponType(100))
+	assert.Error(t, err)
+
+	parsed, err := ParseWeaponType("rifle")
+	require.NoError(t, err)
+	assert.Equal(t, WeaponTypeRifle, parsed)
+
+	_, err = ParseWeaponType("laser")
+	assert.Error(t, err)
+}
+
+func Test_Game_WeaponType(t *testing.T) {
+	newShooter := func(weaponType WeaponType) *Game {
+		game := NewGame(30, 30)
+		game.AddPlayer("player1")
+		game.MovePlayer("player1", Position{X: 10, Y: 10}, DirectionRight)
+		game.SelectWeapon("player1", weaponType)
+		return game
+	}
+
+	t.Run("ライフルの弾はピストルより速い", func(t *testing.T) {
+		game := newShooter(WeaponTypeRifle)
+		bulletID := game.ShootBullet("player1")
+
+		for range WeaponTypeRifle.Spec().MoveTicks * 3 {
+			game.Step()
+		}
+		assert.Equal(t, Position{X: 14, Y: 10}, game.GetItems()[bulletID].Position())
+		assert.Equal(t, WeaponTypeRifle, game.GetItems()[bulletID].(*Bullet).WeaponType())
+	})
+
+	t.Run("ショットガンは3発の弾が扇状に広がり、射程を超えると消える", func(t *testing.T) {
+		game := newShooter(WeaponTypeShotgun)
+		game.ShootBullet("player1")
+
+		positions := func() map[Position]bool {
+			positions := make(map[Position]bool)
+			for _, item := range game.GetItems() {
+				positions[item.Position()] = true
+			}
+			return positions
+		}
+		assert.Equal(t, map[Position]bool{
+			{X: 11, Y: 9}:  true,
+			{X: 11, Y: 10}: true,
+			{X: 11, Y: 11}: true,
+		}, positions())
+
+		spec := WeaponTypeShotgun.Spec()
+		for range spec.MoveTicks {
+			game.Step()
+		}
+		assert.Equal(t, map[Position]bool{
+			{X: 12, Y: 8}:  true,
+			{X: 12, Y: 10}: true,
+			{X: 12, Y: 12}: true,
+		}, positions())
+
+		for range spec.MoveTicks * spec.Range {
+			game.Step()
+		}
+		assert.Empty(t, game.GetItems())
+	})
+
+	t.Run("貫通する弾は複数のプレイヤーに1度ずつダメージを与える", func(t *testing.T) {
+		game := newShooter(WeaponTypePiercing)
+		game.AddPlayer("player2")
+		game.MovePlayer("player2", Position{X: 12, Y: 10}, DirectionLeft)
+		game.AddPlayer("player3")
+		game.MovePlayer("player3", Position{X: 13, Y: 10}, DirectionLeft)
+
+		bulletID := game.ShootBullet("player1")
+		for range WeaponTypePiercing.Spec().MoveTicks * 3 {
+			game.Step()
+		}
+
+		damage := WeaponTypePiercing.Spec().Damage
+		assert.Equal(t, MaxHP-damage, game.GetPlayers()["player2"].HP())
+		assert.Equal(t, MaxHP-damage, game.GetPlayers()["player3"].HP())
+		assert.Contains(t, game.GetItems(), bulletID, "貫通する弾は消えない")
+	})
+}
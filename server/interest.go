@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/cockroachdb/errors"
+	"github.com/shibayu36/terminal-shooter/server/bot"
+	"github.com/shibayu36/terminal-shooter/server/game"
+	"github.com/shibayu36/terminal-shooter/shared"
+	"google.golang.org/protobuf/proto"
+)
+
+// ControllerConfig クライアントへの配信の設定
+type ControllerConfig struct {
+	// プレイヤーに状態を配信する範囲(関心領域)の半径。0なら盤面全体を配信する
+	// 範囲の外のプレイヤーやアイテムの変化はそのプレイヤーには配信しない。観戦者には全て配信する
+	InterestRadius int
+}
+
+// interest 関心領域で配信先を絞り込むために、プレイヤーとアイテムの最後に配信した位置を覚えておく
+// クライアントは範囲の外に出たものを表示しなくなるので、範囲から出る時と範囲に入る時にも状態を届ける必要がある
+// publishEventからだけ使うのでロックしない
+type interest struct {
+	radius  int
+	players map[game.PlayerID]game.Position
+	items   map[game.ItemID]game.Position
+}
+
+func newInterest(radius int) *interest {
+	return &interest{
+		radius:  radius,
+		players: make(map[game.PlayerID]game.Position),
+		items:   make(map[game.ItemID]game.Position),
+	}
+}
+
+// enabled 関心領域で配信先を絞り込むか
+func (i *interest) enabled() bool {
+	return i.radius > 0
+}
+
+// contains centerの関心領域にpositionが入っているか。game.PlayersInRadiusと同じくユークリッド距離で判定する
+func (i *interest) contains(center, position game.Position) bool {
+	dx, dy := position.X-center.X, position.Y-center.Y
+	return dx*dx+dy*dy <= i.radius*i.radius
+}
+
+// trackPlayer プレイヤーの最後に配信した位置を更新する。前回から動いた場合は前回の位置を返す
+func (i *interest) trackPlayer(player *game.Player) (game.Position, bool) {
+	if !i.enabled() {
+		return game.Position{X: 0, Y: 0}, false
+	}
+	return moveTo(i.players, player.PlayerID, player.Position())
+}
+
+// trackItem アイテムの最後に配信した位置を更新する。前回から動いた場合は前回の位置を返す
+func (i *interest) trackItem(item game.Item) (game.Position, bool) {
+	if !i.enabled() {
+		return game.Position{X: 0, Y: 0}, false
+	}
+	return moveTo(i.items, item.ID(), item.Position())
+}
+
+func moveTo[K comparable](positions map[K]game.Position, key K, position game.Position) (game.Position, bool) {
+	previous, ok := positions[key]
+	positions[key] = position
+	return previous, ok && previous != position
+}
+
+// publishAround positionsのいずれかを関心領域に含むプレイヤーと、観戦者に配信する
+// 関心領域を使わない場合は全員に配信する
+func (c *Controller) publishAround(topic string, payload []byte, positions ...game.Position) error {
+	if !c.interest.enabled() {
+		return c.broker.Broadcast(topic, payload)
+	}
+
+	nearby := make(map[string]bool)
+	for _, position := range positions {
+		for _, player := range c.game.PlayersInRadius(position, c.interest.radius) {
+			nearby[string(player.PlayerID)] = true
+		}
+	}
+	return c.broker.Multicast(topic, payload, func(clientID string) bool {
+		return nearby[clientID] || shared.IsSpectatorClientID(clientID)
+	})
+}
+
+// sendEnteredView プレイヤーが動いたことで関心領域に新しく入ったプレイヤーとアイテムの状態を、そのプレイヤーに送る
+func (c *Controller) sendEnteredView(player *game.Player, previous game.Position) {
+	// ボットはクライアントがないので送らない
+	if bot.IsBot(player.PlayerID) {
+		return
+	}
+
+	position := player.Position()
+	var errs []error
+	for _, other := range c.game.PlayersInRadius(position, c.interest.radius) {
+		if other.PlayerID == player.PlayerID || c.interest.contains(previous, other.Position()) {
+			continue
+		}
+		if err := c.sendState(player.PlayerID, "player_state", other.ToSharedPlayerState()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, item := range c.game.ItemsInRadius(position, c.interest.radius) {
+		if c.interest.contains(previous, item.Position()) {
+			continue
+		}
+		if err := c.sendState(player.PlayerID, "item_state", toSharedItemState(item)); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		slog.Error(fmt.Sprintf("failed to send states entered view\n%+v", err))
+	}
+}
+
+func (c *Controller) sendState(playerID game.PlayerID, topic string, state proto.Message) error {
+	payload, err := proto.Marshal(state)
+	if err != nil {
+		return errors.Wrapf(err, "failed to marshal %s", topic)
+	}
+	if err := c.broker.Send(string(playerID), topic, payload); err != nil {
+		return errors.Wrapf(err, "failed to send %s", topic)
+	}
+	return nil
+}
+
+// forget 盤面から消えたプレイヤーとアイテムの位置を忘れる
+func (c *Controller) forget(event game.Event) {
+	switch event := event.(type) {
+	case game.PlayerLeft:
+		delete(c.interest.players, event.Player.PlayerID)
+	case game.ItemRemoved:
+		delete(c.interest.items, event.Item.ID())
+	}
+}
+
+// sharedMatchState クライアントに送るマッチの状態。クライアントが範囲外を表示しないように関心領域の半径も含める
+func (c *Controller) sharedMatchState() *shared.MatchState {
+	state := c.game.ToSharedMatchState()
+	state.InterestRadius = int32(c.interest.radius)
+	return state
+}
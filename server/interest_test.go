@@ -0,0 +1,106 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/shibayu36/terminal-shooter/server/game"
+	"github.com/shibayu36/terminal-shooter/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// receivedIDs クライアントがfrom番目以降に受け取ったプレイヤーとアイテムのID
+func receivedIDs(t *testing.T, cl *mockClient, from int) []string {
+	t.Helper()
+	var ids []string
+	for _, published := range cl.Published()[from:] {
+		switch published.TopicName {
+		case "player_state":
+			state := &shared.PlayerState{}
+			require.NoError(t, proto.Unmarshal(published.Payload, state))
+			ids = append(ids, state.GetPlayerId())
+		case "item_state":
+			state := &shared.ItemState{}
+			require.NoError(t, proto.Unmarshal(published.Payload, state))
+			ids = append(ids, state.GetItemId())
+		}
+	}
+	return ids
+}
+
+func TestController_Interest(t *testing.T) {
+	broker := NewBroker()
+	state := game.NewGame(30, 30)
+	controller := NewControllerWithConfig(broker, state, ControllerConfig{InterestRadius: 5})
+
+	cl1 := &mockClient{id: "id1"}
+	cl2 := &mockClient{id: "id2"}
+	spectator := &mockClient{id: shared.SpectatorClientIDPrefix + "1"}
+	connectPlayer(t, controller, cl1)
+	connectPlayer(t, controller, cl2)
+	require.NoError(t, controller.OnConnected(spectator, nil))
+	state.MovePlayer("id1", game.Position{X: 2, Y: 2}, game.DirectionUp)
+	state.MovePlayer("id2", game.Position{X: 20, Y: 20}, game.DirectionUp)
+	publishEvents(controller, state)
+
+	t.Run("マッチの状態で関心領域の半径を伝える", func(t *testing.T) {
+		from := len(cl1.Published())
+		require.NoError(t, controller.OnSubscribed(cl1, nil))
+		last := cl1.Published()[len(cl1.Published())-1]
+		require.Equal(t, "match_state", last.TopicName)
+		matchState := &shared.MatchState{}
+		require.NoError(t, proto.Unmarshal(last.Payload, matchState))
+		assert.Equal(t, int32(5), matchState.GetInterestRadius())
+
+		// 購読した時は範囲に関係なく全て送る
+		assert.Contains(t, receivedIDs(t, cl1, from), "id2")
+	})
+
+	t.Run("近くのプレイヤーと観戦者にだけ配信する", func(t *testing.T) {
+		from1, from2, fromSpectator := len(cl1.Published()), len(cl2.Published()), len(spectator.Published())
+		bombID := state.PlaceBomb("id2")
+		require.NotEmpty(t, bombID)
+		publishEvents(controller, state)
+
+		assert.NotContains(t, receivedIDs(t, cl1, from1), string(bombID))
+		assert.Contains(t, receivedIDs(t, cl2, from2), string(bombID))
+		assert.Contains(t, receivedIDs(t, spectator, fromSpectator), string(bombID))
+	})
+
+	t.Run("動いて範囲に入ったプレイヤーとアイテムの状態を送る", func(t *testing.T) {
+		from := len(cl1.Published())
+		state.MovePlayer("id1", game.Position{X: 17, Y: 20}, game.DirectionRight)
+		publishEvents(controller, state)
+
+		ids := receivedIDs(t, cl1, from)
+		assert.Contains(t, ids, "id1")
+		assert.Contains(t, ids, "id2", "範囲に入ったプレイヤー")
+		assert.Len(t, state.ItemsInRadius(game.Position{X: 20, Y: 20}, 0), 1)
+		assert.Contains(t, ids, string(state.ItemsInRadius(game.Position{X: 20, Y: 20}, 0)[0].ID()), "範囲に入ったアイテム")
+	})
+
+	t.Run("範囲から出る動きは前の位置の近くにいたプレイヤーにも配信する", func(t *testing.T) {
+		from := len(cl1.Published())
+		state.MovePlayer("id2", game.Position{X: 29, Y: 29}, game.DirectionDown)
+		publishEvents(controller, state)
+		assert.Contains(t, receivedIDs(t, cl1, from), "id2")
+
+		// 範囲の外での動きは配信しない
+		from = len(cl1.Published())
+		state.MovePlayer("id2", game.Position{X: 28, Y: 29}, game.DirectionLeft)
+		publishEvents(controller, state)
+		assert.NotContains(t, receivedIDs(t, cl1, from), "id2")
+	})
+
+	t.Run("アイテムの削除は全員に配信する", func(t *testing.T) {
+		state.MovePlayer("id1", game.Position{X: 2, Y: 2}, game.DirectionUp)
+		publishEvents(controller, state)
+		bomb := state.ItemsInRadius(game.Position{X: 20, Y: 20}, 0)[0]
+
+		from := len(cl1.Published())
+		state.RemoveItem(bomb.ID())
+		publishEvents(controller, state)
+		assert.Contains(t, receivedIDs(t, cl1, from), string(bomb.ID()))
+	})
+}
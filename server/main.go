@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -12,15 +13,27 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/shibayu36/terminal-shooter/server/bot"
 	"github.com/shibayu36/terminal-shooter/server/game"
 )
 
+// サーバーのバージョン。ビルド時に -ldflags "-X main.version=..." で設定する
+//
+//nolint:gochecknoglobals
+var version = "dev"
+
+// 設定は初期値、設定ファイル、環境変数、フラグの順に上書きされる。詳しくは-hを参照
 func main() {
-	options := &runOptions{
-		MQTTPort:    "1883",
-		MetricsPort: "2112",
+	config, err := parseServerConfig(os.Args[1:], os.Getenv, os.Stderr)
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		slog.Error(fmt.Sprintf("invalid config\n%+v", err))
+		os.Exit(1)
 	}
-	if err := run(context.Background(), options); err != nil {
+
+	if err := run(context.Background(), config.toRunOptions()); err != nil {
 		slog.Error(fmt.Sprintf("failed to run\n%+v", err))
 		os.Exit(1)
 	}
@@ -29,6 +42,35 @@ func main() {
 type runOptions struct {
 	MQTTPort    string
 	MetricsPort string
+	// 管理用のAPIの認証に使うtoken。空なら管理用のAPIを使えない
+	AdminToken string
+	// 盤面の大きさ
+	Width  int
+	Height int
+	// 1秒あたりのtick数。0ならgame.TicksPerSecond
+	TicksPerSecond int
+	// マッチの進行ルール。ゼロ値なら即座に開始し終了しない
+	Match game.MatchConfig
+	// ゲームモード。空ならdeathmatch
+	Mode game.GameModeName
+	// チーム戦で味方の攻撃が当たるか
+	FriendlyFire bool
+	// プレイヤーの武器の制限。ゼロ値なら制限なし
+	Weapon game.WeaponConfig
+	// プレイヤーの移動の制限。ゼロ値なら制限なし
+	Movement game.MovementConfig
+	// 盤面に配置する障害物。ゼロ値なら障害物なし
+	Arena game.ArenaConfig
+	// ゲーム内の乱数のシード。同じシードなら同じ盤面が生成される
+	Seed int64
+	// プレイヤーに状態を配信する範囲の半径。0なら盤面全体を配信する
+	InterestRadius int
+	// 更新ループの設定。ゼロ値なら遅れたtickは取り戻さない
+	Loop game.LoopConfig
+	// サーバー内で動かすボット。ゼロ値ならボットは参加しない
+	Bots bot.Config
+	// マッチのリプレイの記録。ゼロ値なら記録しない
+	Replay ReplayConfig
 }
 
 func run(ctx context.Context, opts *runOptions) error {
@@ -37,8 +79,41 @@ func run(ctx context.Context, opts *runOptions) error {
 
 	broker := NewBroker()
 
-	gameState := game.NewGame(30, 30)
-	controller := NewController(broker, gameState)
+	var mode game.GameMode
+	if opts.Mode != "" {
+		var err error
+		mode, err = game.NewGameMode(opts.Mode, game.GameModeOptions{FriendlyFire: opts.FriendlyFire})
+		if err != nil {
+			return err
+		}
+	}
+
+	gameState := game.NewGameWithConfig(game.Config{
+		Width:    opts.Width,
+		Height:   opts.Height,
+		Match:    opts.Match,
+		Mode:     mode,
+		Weapon:   opts.Weapon,
+		Movement: opts.Movement,
+		Arena:    opts.Arena,
+		Seed:     opts.Seed,
+		Loop:     opts.Loop,
+
+		TicksPerSecond: opts.TicksPerSecond,
+	})
+	controller := NewControllerWithConfig(broker, gameState, ControllerConfig{InterestRadius: opts.InterestRadius})
+
+	if opts.Bots.Count > 0 || opts.Bots.FillTo > 0 {
+		bot.NewManager(gameState, opts.Bots).Start()
+	}
+
+	if opts.Replay.Dir != "" {
+		recorder := NewReplayRecorder(opts.Replay, gameState, version)
+		if err := recorder.Start(); err != nil {
+			return err
+		}
+		defer recorder.Close()
+	}
 
 	server, err := NewServer(":"+opts.MQTTPort, controller)
 	if err != nil {
@@ -54,11 +129,15 @@ func run(ctx context.Context, opts *runOptions) error {
 	updatedCh := gameState.StartUpdateLoop(ctx)
 	controller.StartPublishLoop(ctx, updatedCh)
 
-	// Prometheusメトリクスサーバーの起動
+	// Prometheusメトリクスと管理用のAPIを提供するサーバーの起動
+	// 管理用のAPIはAdminTokenを持つリクエストだけを受け付ける
+	metricsMux := http.NewServeMux()
+	metricsMux.Handle("/metrics", promhttp.Handler())
+	metricsMux.Handle("/admin/", newAdminHandler(controller.ChatModerator(), opts.AdminToken))
 	//nolint:exhaustruct,gosec
 	metricsServer := &http.Server{
 		Addr:    ":" + opts.MetricsPort,
-		Handler: promhttp.Handler(),
+		Handler: metricsMux,
 	}
 	go func() {
 		err := metricsServer.ListenAndServe()
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/shibayu36/terminal-shooter/server/game"
+	"github.com/shibayu36/terminal-shooter/shared"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// ReplayConfig リプレイの記録の設定
+type ReplayConfig struct {
+	// リプレイファイルを書き込むディレクトリ。空なら記録しない
+	Dir string
+	// 残すリプレイファイルの数。超えたら古いものから削除する。0なら削除しない
+	MaxFiles int
+	// 1つのリプレイファイルの最大バイト数。超えたらそのマッチの残りは記録しない。0なら制限なし
+	MaxFileBytes int64
+}
+
+// ReplayRecorder マッチごとに、毎tickの操作と状態の変化をリプレイファイルに記録する
+// 対戦が始まったら新しいファイルを作り、対戦が終わったら閉じる
+type ReplayRecorder struct {
+	config  ReplayConfig
+	game    *game.Game
+	version string
+	now     func() time.Time
+
+	// 記録中のファイル。記録していない時はnil
+	file   *os.File
+	writer *bufio.Writer
+	// 記録中のファイルに書き込んだバイト数
+	written int64
+	// 前回のStepの終わりに対戦中だったか
+	running bool
+
+	mu sync.Mutex `exhaustruct:"optional"`
+}
+
+func NewReplayRecorder(config ReplayConfig, g *game.Game, version string) *ReplayRecorder {
+	return &ReplayRecorder{
+		config:  config,
+		game:    g,
+		version: version,
+		now:     time.Now,
+		file:    nil,
+		writer:  nil,
+		written: 0,
+		running: false,
+	}
+}
+
+// Start ゲームのStepのたびに記録するように登録する
+// 既に対戦中であれば、その時点から記録を始める
+func (r *ReplayRecorder) Start() error {
+	if err := os.MkdirAll(r.config.Dir, 0o755); err != nil {
+		return errors.Wrapf(err, "failed to create replay directory: %s", r.config.Dir)
+	}
+
+	r.mu.Lock()
+	if r.game.Match().IsRunning() {
+		r.openWithoutLock(0)
+		r.running = true
+	}
+	r.mu.Unlock()
+
+	r.game.AddStepObserver(r.observe)
+	return nil
+}
+
+// Close 記録中のファイルを閉じる
+func (r *ReplayRecorder) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closeWithoutLock()
+}
+
+// observe Stepの結果を記録し、対戦が始まったら新しいファイルを作り、終わったら閉じる
+func (r *ReplayRecorder) observe(result game.StepResult) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file != nil {
+		r.writeTickWithoutLock(result)
+	}
+
+	running := r.game.Match().IsRunning()
+	switch {
+	case running && !r.running:
+		r.openWithoutLock(result.Tick)
+	case !running && r.running:
+		r.closeWithoutLock()
+	}
+	r.running = running
+}
+
+// openWithoutLock 新しいリプレイファイルを作り、現在の盤面をヘッダーとして書き込む
+func (r *ReplayRecorder) openWithoutLock(tick int64) {
+	r.closeWithoutLock()
+
+	startedAt := r.now()
+	name := fmt.Sprintf("match-%s-%d%s", startedAt.Format("20060102-150405"), tick, shared.ReplayFileExt)
+	file, err := os.Create(filepath.Join(r.config.Dir, name))
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to create replay file\n%+v", err))
+		return
+	}
+	r.file = file
+	r.writer = bufio.NewWriter(file)
+	r.written = 0
+	slog.Info("replay recording started", "file", file.Name())
+
+	r.writeWithoutLock(r.header(startedAt, tick))
+	r.rotate()
+}
+
+// closeWithoutLock 記録中のファイルを閉じる
+func (r *ReplayRecorder) closeWithoutLock() {
+	if r.file == nil {
+		return
+	}
+	if err := r.writer.Flush(); err != nil {
+		slog.Error(fmt.Sprintf("failed to flush replay file\n%+v", err))
+	}
+	if err := r.file.Close(); err != nil {
+		slog.Error(fmt.Sprintf("failed to close replay file\n%+v", err))
+	}
+	slog.Info("replay recording finished", "file", r.file.Name(), "bytes", r.written)
+	r.file = nil
+	r.writer = nil
+}
+
+// writeTickWithoutLock 1tickの操作と状態の変化を書き込む。何も起きなかったtickは書き込まない
+func (r *ReplayRecorder) writeTickWithoutLock(result game.StepResult) {
+	if len(result.Inputs) == 0 && len(result.Events) == 0 {
+		return
+	}
+	r.writeWithoutLock(toReplayTick(result, r.game))
+
+	// サーバーが落ちても直前までは残るように、1秒ごとにファイルに書き出す
+	if r.file != nil && result.Tick%int64(r.game.TicksPerSecond()) == 0 {
+		if err := r.writer.Flush(); err != nil {
+			slog.Error(fmt.Sprintf("failed to flush replay file\n%+v", err))
+			r.closeWithoutLock()
+		}
+	}
+}
+
+// writeWithoutLock メッセージを書き込む
+// ファイルの大きさの上限を超える場合や書き込みに失敗した場合は、そのマッチの記録をやめる
+func (r *ReplayRecorder) writeWithoutLock(message proto.Message) {
+	size := proto.Size(message)
+	size += protowire.SizeVarint(uint64(size))
+	if r.config.MaxFileBytes > 0 && r.written+int64(size) > r.config.MaxFileBytes {
+		slog.Warn("replay file reached size limit", "file", r.file.Name(), "max_bytes", r.config.MaxFileBytes)
+		r.closeWithoutLock()
+		return
+	}
+
+	n, err := shared.WriteReplayMessage(r.writer, message)
+	r.written += int64(n)
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to write replay\n%+v", err))
+		r.closeWithoutLock()
+	}
+}
+
+// rotate 残すファイル数を超えた古いリプレイファイルを削除する
+// ファイル名は記録を開始した時刻から始まるので、名前の順が古い順になる
+func (r *ReplayRecorder) rotate() {
+	if r.config.MaxFiles <= 0 {
+		return
+	}
+
+	entries, err := os.ReadDir(r.config.Dir)
+	if err != nil {
+		slog.Error(fmt.Sprintf("failed to read replay directory\n%+v", err))
+		return
+	}
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), shared.ReplayFileExt) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for len(names) > r.config.MaxFiles {
+		if err := os.Remove(filepath.Join(r.config.Dir, names[0])); err != nil {
+			slog.Error(fmt.Sprintf("failed to remove old replay file\n%+v", err))
+		}
+		names = names[1:]
+	}
+}
+
+// header マッチの情報と現在の盤面をReplayHeaderにする
+func (r *ReplayRecorder) header(startedAt time.Time, tick int64) *shared.ReplayHeader {
+	arena := r.game.Arena()
+	header := &shared.ReplayHeader{
+		ServerVersion:   r.version,
+		ProtocolVersion: shared.ProtocolVersion,
+		StartedAt:       startedAt.UnixMilli(),
+		StartTick:       tick,
+		Seed:            r.game.Seed(),
+		Map: &shared.ReplayMap{
+			Width:          int32(r.game.Width),
+			Height:         int32(r.game.Height),
+			PillarInterval: int32(arena.PillarInterval),
+			BlockDensity:   arena.BlockDensity,
+			PowerUpChance:  arena.PowerUpChance,
+		},
+		Match: r.game.ToSharedMatchState(),
+	}
+
+	players := r.game.GetPlayers()
+	playerIDs := make([]game.PlayerID, 0, len(players))
+	for playerID := range players {
+		playerIDs = append(playerIDs, playerID)
+	}
+	sort.Slice(playerIDs, func(i, j int) bool { return playerIDs[i] < playerIDs[j] })
+	for _, playerID := range playerIDs {
+		header.Players = append(header.Players, players[playerID].ToSharedPlayerState())
+	}
+
+	items := r.game.GetItems()
+	itemIDs := make([]game.ItemID, 0, len(items))
+	for itemID := range items {
+		itemIDs = append(itemIDs, itemID)
+	}
+	sort.Slice(itemIDs, func(i, j int) bool { return itemIDs[i] < itemIDs[j] })
+	for _, itemID := range itemIDs {
+		header.Items = append(header.Items, toSharedItemState(items[itemID]))
+	}
+
+	return header
+}
+
+// toReplayTick Stepの結果をReplayTickに変換する
+// 同じプレイヤーやアイテムが何度変化しても、tickの終わりの状態を1つだけ記録する
+func toReplayTick(result game.StepResult, g *game.Game) *shared.ReplayTick {
+	tick := &shared.ReplayTick{Tick: result.Tick}
+	for _, input := range result.Inputs {
+		tick.Inputs = append(tick.Inputs, toReplayInput(input))
+	}
+
+	recordedPlayers := make(map[game.PlayerID]bool)
+	recordPlayer := func(player *game.Player) {
+		if recordedPlayers[player.PlayerID] {
+			return
+		}
+		recordedPlayers[player.PlayerID] = true
+		tick.Players = append(tick.Players, player.ToSharedPlayerState())
+	}
+
+	// 削除されたアイテムは、同じtickで追加や移動をしていても削除として記録する
+	removedItems := make(map[game.ItemID]bool)
+	for _, event := range result.Events {
+		if event, ok := event.(game.ItemRemoved); ok {
+			removedItems[event.Item.ID()] = true
+		}
+	}
+	recordedItems := make(map[game.ItemID]bool)
+	recordItem := func(item game.Item) {
+		if recordedItems[item.ID()] {
+			return
+		}
+		recordedItems[item.ID()] = true
+		if removedItems[item.ID()] {
+			tick.Items = append(tick.Items, toRemovedItemState(item))
+			return
+		}
+		tick.Items = append(tick.Items, toSharedItemState(item))
+	}
+
+	for _, event := range result.Events {
+		if gameEvent := toSharedGameEvent(event); gameEvent != nil {
+			tick.Events = append(tick.Events, gameEvent)
+		}
+
+		switch event := event.(type) {
+		case game.PlayerJoined:
+			recordPlayer(event.Player)
+		case game.PlayerMoved:
+			recordPlayer(event.Player)
+		case game.PlayerUpdated:
+			recordPlayer(event.Player)
+		case game.PlayerDied:
+			recordPlayer(event.Player)
+		case game.ItemSpawned:
+			recordItem(event.Item)
+		case game.ItemMoved:
+			recordItem(event.Item)
+		case game.ItemRemoved:
+			recordItem(event.Item)
+		case game.MatchUpdated:
+			tick.Match = g.ToSharedMatchState()
+		}
+	}
+
+	return tick
+}
+
+// toReplayInput 受け付けた操作をReplayInputに変換する。操作の種類に関係するフィールドだけを埋める
+func toReplayInput(input game.Input) *shared.ReplayInput {
+	replayInput := &shared.ReplayInput{PlayerId: string(input.PlayerID)}
+	switch input.Type {
+	case game.InputTypeJoin:
+		replayInput.Type = shared.ReplayInputType_REPLAY_INPUT_JOIN
+		replayInput.DisplayName = input.DisplayName
+	case game.InputTypeLeave:
+		replayInput.Type = shared.ReplayInputType_REPLAY_INPUT_LEAVE
+	case game.InputTypeMove:
+		replayInput.Type = shared.ReplayInputType_REPLAY_INPUT_MOVE
+		replayInput.Position = &shared.Position{X: int32(input.Position.X), Y: int32(input.Position.Y)}
+		if input.Direction != "" {
+			replayInput.Direction = input.Direction.ToSharedDirection()
+		}
+	case game.InputTypeShoot:
+		replayInput.Type = shared.ReplayInputType_REPLAY_INPUT_SHOOT
+	case game.InputTypePlaceBomb:
+		replayInput.Type = shared.ReplayInputType_REPLAY_INPUT_PLACE_BOMB
+	case game.InputTypeSelectWeapon:
+		replayInput.Type = shared.ReplayInputType_REPLAY_INPUT_SELECT_WEAPON
+		replayInput.Weapon = input.WeaponType.ToSharedWeaponType()
+	case game.InputTypeReload:
+		replayInput.Type = shared.ReplayInputType_REPLAY_INPUT_RELOAD
+	case game.InputTypeSwitchTeam:
+		replayInput.Type = shared.ReplayInputType_REPLAY_INPUT_SWITCH_TEAM
+		replayInput.Team = input.Team.ToSharedTeam()
+	case game.InputTypeSetDisplayName:
+		replayInput.Type = shared.ReplayInputType_REPLAY_INPUT_SET_DISPLAY_NAME
+		replayInput.DisplayName = input.DisplayName
+	}
+	return replayInput
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shibayu36/terminal-shooter/server/game"
+	"github.com/shibayu36/terminal-shooter/shared"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newReplayTestGame 2人揃うとすぐに始まり、2秒で終わるマッチのゲームを作る
+func newReplayTestGame() *game.Game {
+	return game.NewGameWithConfig(game.Config{
+		Width:  30,
+		Height: 30,
+		Match: game.MatchConfig{
+			MinPlayers:     2,
+			TimeLimitTicks: 2 * game.TicksPerSecond,
+			ResultTicks:    10,
+		},
+		Seed: 42,
+	})
+}
+
+// newTestReplayRecorder 記録を開始するたびに1分進む時計を使うReplayRecorderを作る
+func newTestReplayRecorder(t *testing.T, config ReplayConfig, g *game.Game) *ReplayRecorder {
+	t.Helper()
+	recorder := NewReplayRecorder(config, g, "test")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	recorder.now = func() time.Time {
+		now = now.Add(time.Minute)
+		return now
+	}
+	require.NoError(t, recorder.Start())
+	return recorder
+}
+
+// readReplay リプレイファイルのヘッダーと全てのtickを読み込む
+func readReplay(t *testing.T, path string) (*shared.ReplayHeader, []*shared.ReplayTick) {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	reader, err := shared.NewReplayReader(file)
+	require.NoError(t, err)
+	var ticks []*shared.ReplayTick
+	for {
+		tick, err := reader.Next()
+		if err == io.EOF {
+			return reader.Header(), ticks
+		}
+		require.NoError(t, err)
+		ticks = append(ticks, tick)
+	}
+}
+
+func replayFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*"+shared.ReplayFileExt))
+	require.NoError(t, err)
+	return files
+}
+
+func TestReplayRecorder(t *testing.T) {
+	dir := t.TempDir()
+	state := newReplayTestGame()
+	recorder := newTestReplayRecorder(t, ReplayConfig{Dir: dir, MaxFiles: 0, MaxFileBytes: 0}, state)
+
+	// 対戦が始まるまでは記録しない
+	state.AddPlayer("player1")
+	state.Step()
+	assert.Empty(t, replayFiles(t, dir))
+
+	state.AddPlayer("player2")
+	state.Step()
+	require.Len(t, replayFiles(t, dir), 1)
+
+	state.MovePlayer("player1", game.Position{X: 5, Y: 5}, game.DirectionRight)
+	state.ShootBullet("player1")
+	for state.Match().IsRunning() {
+		state.Step()
+	}
+	recorder.Close()
+
+	header, ticks := readReplay(t, replayFiles(t, dir)[0])
+	assert.Equal(t, "test", header.GetServerVersion())
+	assert.Equal(t, shared.ProtocolVersion, header.GetProtocolVersion())
+	assert.Equal(t, int64(2), header.GetStartTick())
+	assert.Equal(t, int64(42), header.GetSeed())
+	assert.Equal(t, int32(30), header.GetMap().GetWidth())
+	assert.Equal(t, shared.MatchPhase_RUNNING, header.GetMatch().GetPhase())
+	require.Len(t, header.GetPlayers(), 2)
+	assert.Equal(t, "player1", header.GetPlayers()[0].GetPlayerId())
+
+	// 最初のtickに、対戦開始後に受け付けた操作とその結果が記録される
+	require.NotEmpty(t, ticks)
+	first := ticks[0]
+	assert.Equal(t, int64(3), first.GetTick())
+	require.Len(t, first.GetInputs(), 2)
+	assert.Equal(t, shared.ReplayInputType_REPLAY_INPUT_MOVE, first.GetInputs()[0].GetType())
+	assert.Equal(t, int32(5), first.GetInputs()[0].GetPosition().GetX())
+	assert.Equal(t, shared.Direction_RIGHT, first.GetInputs()[0].GetDirection())
+	assert.Equal(t, shared.ReplayInputType_REPLAY_INPUT_SHOOT, first.GetInputs()[1].GetType())
+	require.Len(t, first.GetPlayers(), 1, "同じプレイヤーの変化は1つにまとめられる")
+	assert.Equal(t, int32(5), first.GetPlayers()[0].GetPosition().GetX())
+	require.Len(t, first.GetItems(), 1)
+	assert.Equal(t, shared.ItemType_BULLET, first.GetItems()[0].GetType())
+
+	// 何も起きなかったtickは記録しない
+	for i := 1; i < len(ticks); i++ {
+		assert.Greater(t, ticks[i].GetTick(), ticks[i-1].GetTick())
+	}
+	assert.Less(t, len(ticks), 2*game.TicksPerSecond)
+
+	// 最後のtickでマッチが終わる
+	assert.Equal(t, shared.MatchPhase_FINISHED, ticks[len(ticks)-1].GetMatch().GetPhase())
+}
+
+func TestReplayRecorder_Rotate(t *testing.T) {
+	dir := t.TempDir()
+	state := newReplayTestGame()
+	recorder := newTestReplayRecorder(t, ReplayConfig{Dir: dir, MaxFiles: 2, MaxFileBytes: 0}, state)
+	defer recorder.Close()
+
+	state.AddPlayer("player1")
+	state.AddPlayer("player2")
+
+	// 3回のマッチを行うと、古いリプレイファイルから削除される
+	var opened []string
+	for range 3 {
+		for !state.Match().IsRunning() {
+			state.Step()
+		}
+		files := replayFiles(t, dir)
+		opened = append(opened, files[len(files)-1])
+		for state.Match().IsRunning() {
+			state.Step()
+		}
+	}
+	assert.Equal(t, opened[1:], replayFiles(t, dir))
+}
+
+func TestReplayRecorder_MaxFileBytes(t *testing.T) {
+	dir := t.TempDir()
+	state := newReplayTestGame()
+	recorder := newTestReplayRecorder(t, ReplayConfig{Dir: dir, MaxFiles: 0, MaxFileBytes: 1024}, state)
+
+	state.AddPlayer("player1")
+	state.AddPlayer("player2")
+	state.Step()
+	for state.Match().IsRunning() {
+		state.MovePlayer("player1", game.Position{X: 5, Y: 5}, game.DirectionRight)
+		state.Step()
+	}
+	recorder.Close()
+
+	// 上限を超える前までのtickが、途中で切れずに読み込める
+	files := replayFiles(t, dir)
+	require.Len(t, files, 1)
+	info, err := os.Stat(files[0])
+	require.NoError(t, err)
+	assert.LessOrEqual(t, info.Size(), int64(1024))
+
+	_, ticks := readReplay(t, files[0])
+	assert.NotEmpty(t, ticks)
+	assert.Less(t, len(ticks), 2*game.TicksPerSecond)
+}
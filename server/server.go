@@ -13,6 +13,9 @@ import (
 	"github.com/eclipse/paho.mqtt.golang/packets"
 )
 
+// ErrClientIDRejected OnConnectedがこのエラーを返した場合、接続を拒否して切断する
+var ErrClientIDRejected = errors.New("client id rejected")
+
 type Hooker interface {
 	OnConnected(client Client, packet *packets.ConnectPacket) error
 	OnPublished(client Client, packet *packets.PublishPacket) error
@@ -113,9 +116,12 @@ func (s *Server) handleConnection(conn net.Conn) {
 			return
 		}
 
-		err := s.hook.OnDisconnected(client)
-		if err != nil {
-			slog.Error(fmt.Sprintf("Error on disconnected\n%+v", err))
+		// 接続を拒否したクライアントは登録されていないので、同じIDの他のクライアントの後始末をしない
+		if client.connected {
+			err := s.hook.OnDisconnected(client)
+			if err != nil {
+				slog.Error(fmt.Sprintf("Error on disconnected\n%+v", err))
+			}
 		}
 		conn.Close()
 
@@ -147,6 +153,11 @@ func (s *Server) handleConnection(conn net.Conn) {
 				return
 			}
 
+			if errors.Is(err, ErrClientIDRejected) {
+				slog.Info("Client rejected", "address", conn.RemoteAddr(), "reason", err.Error())
+				return
+			}
+
 			// packet一つのハンドリングを失敗しただけなら、そのパケットを破棄して続ける
 			slog.Error(fmt.Sprintf("Error handling packet\n%+v", err))
 		}
@@ -178,22 +189,39 @@ func (s *Server) handlePacket(client *client, packet packets.ControlPacket) erro
 }
 
 // handleConnect handles CONNECT packets
+// 接続を受け付けるかはOnConnectedの結果で決めるので、CONNACKはOnConnectedの後に送る
 func (s *Server) handleConnect(client *client, connectPacket *packets.ConnectPacket) error {
+	if client.connected {
+		// 2回目のCONNECTはプロトコル違反なので無視する
+		return errors.New("client already connected")
+	}
+
+	// クライアントの登録
+	client.id = connectPacket.ClientIdentifier
+
+	// OnConnectedで登録された後の配信がCONNACKより先に届かないように、CONNACKを送るまで送信を止める
+	client.sendMux.Lock()
+	defer client.sendMux.Unlock()
+
+	hookErr := s.hook.OnConnected(client, connectPacket)
+
 	// CONNACK パケットの作成と送信
 	//nolint:forcetypeassert
 	connack := packets.NewControlPacket(packets.Connack).(*packets.ConnackPacket)
 	connack.ReturnCode = packets.Accepted
 	connack.SessionPresent = false
+	if errors.Is(hookErr, ErrClientIDRejected) {
+		connack.ReturnCode = packets.ErrRefusedIDRejected
+	} else {
+		client.connected = true
+	}
 
 	if err := connack.Write(client.conn); err != nil {
 		return errors.Wrap(err, "failed to write CONNACK")
 	}
 
-	// クライアントの登録
-	client.id = connectPacket.ClientIdentifier
-
-	if err := s.hook.OnConnected(client, connectPacket); err != nil {
-		return errors.Wrap(err, "hook OnConnected failed")
+	if hookErr != nil {
+		return errors.Wrap(hookErr, "hook OnConnected failed")
 	}
 
 	return nil
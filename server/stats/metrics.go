@@ -11,6 +11,12 @@ var ActiveClients = promauto.NewGauge(prometheus.GaugeOpts{
 	Help: "The number of active clients",
 })
 
+// 接続中のクライアントのうち、観戦しているクライアント数
+var ActiveSpectators = promauto.NewGauge(prometheus.GaugeOpts{
+	Name: "terminal_shooter_active_spectators",
+	Help: "The number of active spectator clients",
+})
+
 // Publishされたパケット数
 var PublishedPackets = promauto.NewCounter(prometheus.CounterOpts{
 	Name: "terminal_shooter_published_packets_total",
@@ -38,3 +44,32 @@ var PublishStatesDuration = promauto.NewHistogram(prometheus.HistogramOpts{
 		0.02, 0.025, 0.03,
 	},
 })
+
+// 1tickの処理が間隔(16.7ms)に収まらなかった回数
+var TickOverruns = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "terminal_shooter_tick_overruns_total",
+	Help: "The total number of ticks that took longer than the tick interval",
+})
+
+// 処理が遅れて、追いつけずに飛ばしたtick数
+var MissedTicks = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "terminal_shooter_missed_ticks_total",
+	Help: "The total number of ticks skipped because the game loop fell behind",
+})
+
+// 処理の遅れを取り戻すために追加で実行したtick数
+var CatchUpTicks = promauto.NewCounter(prometheus.CounterOpts{
+	Name: "terminal_shooter_catch_up_ticks_total",
+	Help: "The total number of extra ticks run to catch up with the wall clock",
+})
+
+// ゲームのイベントを配信処理に渡すまでに待たされた時間
+var EventSendDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+	Name: "terminal_shooter_event_send_duration_seconds",
+	Help: "Time the game loop spent blocked sending events to the publisher",
+	Buckets: []float64{
+		0.00001, 0.0001, 0.001, 0.005, 0.01,
+		0.016667,
+		0.02, 0.03, 0.05,
+	},
+})
@@ -20,6 +20,59 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// 武器の種類
+type WeaponType int32
+
+const (
+	WeaponType_PISTOL   WeaponType = 0
+	WeaponType_RIFLE    WeaponType = 1
+	WeaponType_SHOTGUN  WeaponType = 2
+	WeaponType_PIERCING WeaponType = 3
+)
+
+// Enum value maps for WeaponType.
+var (
+	WeaponType_name = map[int32]string{
+		0: "PISTOL",
+		1: "RIFLE",
+		2: "SHOTGUN",
+		3: "PIERCING",
+	}
+	WeaponType_value = map[string]int32{
+		"PISTOL":   0,
+		"RIFLE":    1,
+		"SHOTGUN":  2,
+		"PIERCING": 3,
+	}
+)
+
+func (x WeaponType) Enum() *WeaponType {
+	p := new(WeaponType)
+	*p = x
+	return p
+}
+
+func (x WeaponType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (WeaponType) Descriptor() protoreflect.EnumDescriptor {
+	return file_game_proto_enumTypes[0].Descriptor()
+}
+
+func (WeaponType) Type() protoreflect.EnumType {
+	return &file_game_proto_enumTypes[0]
+}
+
+func (x WeaponType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use WeaponType.Descriptor instead.
+func (WeaponType) EnumDescriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{0}
+}
+
 // アイテムのステータス
 type ItemStatus int32
 
@@ -51,11 +104,11 @@ func (x ItemStatus) String() string {
 }
 
 func (ItemStatus) Descriptor() protoreflect.EnumDescriptor {
-	return file_game_proto_enumTypes[0].Descriptor()
+	return file_game_proto_enumTypes[1].Descriptor()
 }
 
 func (ItemStatus) Type() protoreflect.EnumType {
-	return &file_game_proto_enumTypes[0]
+	return &file_game_proto_enumTypes[1]
 }
 
 func (x ItemStatus) Number() protoreflect.EnumNumber {
@@ -64,7 +117,7 @@ func (x ItemStatus) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ItemStatus.Descriptor instead.
 func (ItemStatus) EnumDescriptor() ([]byte, []int) {
-	return file_game_proto_rawDescGZIP(), []int{0}
+	return file_game_proto_rawDescGZIP(), []int{1}
 }
 
 // 向き
@@ -104,11 +157,11 @@ func (x Direction) String() string {
 }
 
 func (Direction) Descriptor() protoreflect.EnumDescriptor {
-	return file_game_proto_enumTypes[1].Descriptor()
+	return file_game_proto_enumTypes[2].Descriptor()
 }
 
 func (Direction) Type() protoreflect.EnumType {
-	return &file_game_proto_enumTypes[1]
+	return &file_game_proto_enumTypes[2]
 }
 
 func (x Direction) Number() protoreflect.EnumNumber {
@@ -117,7 +170,7 @@ func (x Direction) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Direction.Descriptor instead.
 func (Direction) EnumDescriptor() ([]byte, []int) {
-	return file_game_proto_rawDescGZIP(), []int{1}
+	return file_game_proto_rawDescGZIP(), []int{2}
 }
 
 // プレイヤーのステータス
@@ -154,11 +207,11 @@ func (x Status) String() string {
 }
 
 func (Status) Descriptor() protoreflect.EnumDescriptor {
-	return file_game_proto_enumTypes[2].Descriptor()
+	return file_game_proto_enumTypes[3].Descriptor()
 }
 
 func (Status) Type() protoreflect.EnumType {
-	return &file_game_proto_enumTypes[2]
+	return &file_game_proto_enumTypes[3]
 }
 
 func (x Status) Number() protoreflect.EnumNumber {
@@ -167,7 +220,7 @@ func (x Status) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Status.Descriptor instead.
 func (Status) EnumDescriptor() ([]byte, []int) {
-	return file_game_proto_rawDescGZIP(), []int{2}
+	return file_game_proto_rawDescGZIP(), []int{3}
 }
 
 // アイテムの種類
@@ -177,6 +230,15 @@ const (
 	ItemType_BULLET    ItemType = 0
 	ItemType_BOMB      ItemType = 1
 	ItemType_BOMB_FIRE ItemType = 2
+	// パワーアップアイテム
+	ItemType_BOMB_CAPACITY_UP ItemType = 3
+	ItemType_BOMB_RANGE_UP    ItemType = 4
+	ItemType_SPEED_UP         ItemType = 5
+	ItemType_SHIELD           ItemType = 6
+	ItemType_RAPID_FIRE       ItemType = 7
+	// 障害物
+	ItemType_WALL  ItemType = 8
+	ItemType_BLOCK ItemType = 9
 )
 
 // Enum value maps for ItemType.
@@ -185,11 +247,25 @@ var (
 		0: "BULLET",
 		1: "BOMB",
 		2: "BOMB_FIRE",
+		3: "BOMB_CAPACITY_UP",
+		4: "BOMB_RANGE_UP",
+		5: "SPEED_UP",
+		6: "SHIELD",
+		7: "RAPID_FIRE",
+		8: "WALL",
+		9: "BLOCK",
 	}
 	ItemType_value = map[string]int32{
-		"BULLET":    0,
-		"BOMB":      1,
-		"BOMB_FIRE": 2,
+		"BULLET":           0,
+		"BOMB":             1,
+		"BOMB_FIRE":        2,
+		"BOMB_CAPACITY_UP": 3,
+		"BOMB_RANGE_UP":    4,
+		"SPEED_UP":         5,
+		"SHIELD":           6,
+		"RAPID_FIRE":       7,
+		"WALL":             8,
+		"BLOCK":            9,
 	}
 )
 
@@ -204,11 +280,11 @@ func (x ItemType) String() string {
 }
 
 func (ItemType) Descriptor() protoreflect.EnumDescriptor {
-	return file_game_proto_enumTypes[3].Descriptor()
+	return file_game_proto_enumTypes[4].Descriptor()
 }
 
 func (ItemType) Type() protoreflect.EnumType {
-	return &file_game_proto_enumTypes[3]
+	return &file_game_proto_enumTypes[4]
 }
 
 func (x ItemType) Number() protoreflect.EnumNumber {
@@ -217,15 +293,18 @@ func (x ItemType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ItemType.Descriptor instead.
 func (ItemType) EnumDescriptor() ([]byte, []int) {
-	return file_game_proto_rawDescGZIP(), []int{3}
+	return file_game_proto_rawDescGZIP(), []int{4}
 }
 
 // プレイヤーからのアクションの種類
 type ActionType int32
 
 const (
-	ActionType_SHOOT_BULLET ActionType = 0
-	ActionType_PLACE_BOMB   ActionType = 1
+	ActionType_SHOOT_BULLET  ActionType = 0
+	ActionType_PLACE_BOMB    ActionType = 1
+	ActionType_SWITCH_TEAM   ActionType = 2
+	ActionType_RELOAD        ActionType = 3
+	ActionType_SELECT_WEAPON ActionType = 4
 )
 
 // Enum value maps for ActionType.
@@ -233,10 +312,16 @@ var (
 	ActionType_name = map[int32]string{
 		0: "SHOOT_BULLET",
 		1: "PLACE_BOMB",
+		2: "SWITCH_TEAM",
+		3: "RELOAD",
+		4: "SELECT_WEAPON",
 	}
 	ActionType_value = map[string]int32{
-		"SHOOT_BULLET": 0,
-		"PLACE_BOMB":   1,
+		"SHOOT_BULLET":  0,
+		"PLACE_BOMB":    1,
+		"SWITCH_TEAM":   2,
+		"RELOAD":        3,
+		"SELECT_WEAPON": 4,
 	}
 )
 
@@ -251,11 +336,11 @@ func (x ActionType) String() string {
 }
 
 func (ActionType) Descriptor() protoreflect.EnumDescriptor {
-	return file_game_proto_enumTypes[4].Descriptor()
+	return file_game_proto_enumTypes[5].Descriptor()
 }
 
 func (ActionType) Type() protoreflect.EnumType {
-	return &file_game_proto_enumTypes[4]
+	return &file_game_proto_enumTypes[5]
 }
 
 func (x ActionType) Number() protoreflect.EnumNumber {
@@ -264,7 +349,326 @@ func (x ActionType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use ActionType.Descriptor instead.
 func (ActionType) EnumDescriptor() ([]byte, []int) {
-	return file_game_proto_rawDescGZIP(), []int{4}
+	return file_game_proto_rawDescGZIP(), []int{5}
+}
+
+// マッチの進行フェーズ
+type MatchPhase int32
+
+const (
+	MatchPhase_WAITING   MatchPhase = 0
+	MatchPhase_COUNTDOWN MatchPhase = 1
+	MatchPhase_RUNNING   MatchPhase = 2
+	MatchPhase_FINISHED  MatchPhase = 3
+)
+
+// Enum value maps for MatchPhase.
+var (
+	MatchPhase_name = map[int32]string{
+		0: "WAITING",
+		1: "COUNTDOWN",
+		2: "RUNNING",
+		3: "FINISHED",
+	}
+	MatchPhase_value = map[string]int32{
+		"WAITING":   0,
+		"COUNTDOWN": 1,
+		"RUNNING":   2,
+		"FINISHED":  3,
+	}
+)
+
+func (x MatchPhase) Enum() *MatchPhase {
+	p := new(MatchPhase)
+	*p = x
+	return p
+}
+
+func (x MatchPhase) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (MatchPhase) Descriptor() protoreflect.EnumDescriptor {
+	return file_game_proto_enumTypes[6].Descriptor()
+}
+
+func (MatchPhase) Type() protoreflect.EnumType {
+	return &file_game_proto_enumTypes[6]
+}
+
+func (x MatchPhase) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use MatchPhase.Descriptor instead.
+func (MatchPhase) EnumDescriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{6}
+}
+
+// ゲームモード
+type GameMode int32
+
+const (
+	GameMode_DEATHMATCH        GameMode = 0
+	GameMode_LAST_MAN_STANDING GameMode = 1
+	GameMode_TEAM_DEATHMATCH   GameMode = 2
+)
+
+// Enum value maps for GameMode.
+var (
+	GameMode_name = map[int32]string{
+		0: "DEATHMATCH",
+		1: "LAST_MAN_STANDING",
+		2: "TEAM_DEATHMATCH",
+	}
+	GameMode_value = map[string]int32{
+		"DEATHMATCH":        0,
+		"LAST_MAN_STANDING": 1,
+		"TEAM_DEATHMATCH":   2,
+	}
+)
+
+func (x GameMode) Enum() *GameMode {
+	p := new(GameMode)
+	*p = x
+	return p
+}
+
+func (x GameMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (GameMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_game_proto_enumTypes[7].Descriptor()
+}
+
+func (GameMode) Type() protoreflect.EnumType {
+	return &file_game_proto_enumTypes[7]
+}
+
+func (x GameMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use GameMode.Descriptor instead.
+func (GameMode) EnumDescriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{7}
+}
+
+// プレイヤーの所属チーム
+type Team int32
+
+const (
+	Team_NO_TEAM Team = 0
+	Team_RED     Team = 1
+	Team_BLUE    Team = 2
+)
+
+// Enum value maps for Team.
+var (
+	Team_name = map[int32]string{
+		0: "NO_TEAM",
+		1: "RED",
+		2: "BLUE",
+	}
+	Team_value = map[string]int32{
+		"NO_TEAM": 0,
+		"RED":     1,
+		"BLUE":    2,
+	}
+)
+
+func (x Team) Enum() *Team {
+	p := new(Team)
+	*p = x
+	return p
+}
+
+func (x Team) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Team) Descriptor() protoreflect.EnumDescriptor {
+	return file_game_proto_enumTypes[8].Descriptor()
+}
+
+func (Team) Type() protoreflect.EnumType {
+	return &file_game_proto_enumTypes[8]
+}
+
+func (x Team) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Team.Descriptor instead.
+func (Team) EnumDescriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{8}
+}
+
+type JoinResult int32
+
+const (
+	JoinResult_JOIN_ACCEPTED         JoinResult = 0
+	JoinResult_JOIN_NAME_INVALID     JoinResult = 1
+	JoinResult_JOIN_NAME_TAKEN       JoinResult = 2
+	JoinResult_JOIN_VERSION_MISMATCH JoinResult = 3
+)
+
+// Enum value maps for JoinResult.
+var (
+	JoinResult_name = map[int32]string{
+		0: "JOIN_ACCEPTED",
+		1: "JOIN_NAME_INVALID",
+		2: "JOIN_NAME_TAKEN",
+		3: "JOIN_VERSION_MISMATCH",
+	}
+	JoinResult_value = map[string]int32{
+		"JOIN_ACCEPTED":         0,
+		"JOIN_NAME_INVALID":     1,
+		"JOIN_NAME_TAKEN":       2,
+		"JOIN_VERSION_MISMATCH": 3,
+	}
+)
+
+func (x JoinResult) Enum() *JoinResult {
+	p := new(JoinResult)
+	*p = x
+	return p
+}
+
+func (x JoinResult) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (JoinResult) Descriptor() protoreflect.EnumDescriptor {
+	return file_game_proto_enumTypes[9].Descriptor()
+}
+
+func (JoinResult) Type() protoreflect.EnumType {
+	return &file_game_proto_enumTypes[9]
+}
+
+func (x JoinResult) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use JoinResult.Descriptor instead.
+func (JoinResult) EnumDescriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{9}
+}
+
+type ChatScope int32
+
+const (
+	ChatScope_CHAT_SCOPE_ALL ChatScope = 0
+	// 送信者と同じチームのプレイヤーにのみ配信する。チームに所属していない場合は全員に配信する
+	ChatScope_CHAT_SCOPE_TEAM ChatScope = 1
+)
+
+// Enum value maps for ChatScope.
+var (
+	ChatScope_name = map[int32]string{
+		0: "CHAT_SCOPE_ALL",
+		1: "CHAT_SCOPE_TEAM",
+	}
+	ChatScope_value = map[string]int32{
+		"CHAT_SCOPE_ALL":  0,
+		"CHAT_SCOPE_TEAM": 1,
+	}
+)
+
+func (x ChatScope) Enum() *ChatScope {
+	p := new(ChatScope)
+	*p = x
+	return p
+}
+
+func (x ChatScope) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChatScope) Descriptor() protoreflect.EnumDescriptor {
+	return file_game_proto_enumTypes[10].Descriptor()
+}
+
+func (ChatScope) Type() protoreflect.EnumType {
+	return &file_game_proto_enumTypes[10]
+}
+
+func (x ChatScope) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChatScope.Descriptor instead.
+func (ChatScope) EnumDescriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{10}
+}
+
+type ReplayInputType int32
+
+const (
+	ReplayInputType_REPLAY_INPUT_JOIN             ReplayInputType = 0
+	ReplayInputType_REPLAY_INPUT_LEAVE            ReplayInputType = 1
+	ReplayInputType_REPLAY_INPUT_MOVE             ReplayInputType = 2
+	ReplayInputType_REPLAY_INPUT_SHOOT            ReplayInputType = 3
+	ReplayInputType_REPLAY_INPUT_PLACE_BOMB       ReplayInputType = 4
+	ReplayInputType_REPLAY_INPUT_SELECT_WEAPON    ReplayInputType = 5
+	ReplayInputType_REPLAY_INPUT_RELOAD           ReplayInputType = 6
+	ReplayInputType_REPLAY_INPUT_SWITCH_TEAM      ReplayInputType = 7
+	ReplayInputType_REPLAY_INPUT_SET_DISPLAY_NAME ReplayInputType = 8
+)
+
+// Enum value maps for ReplayInputType.
+var (
+	ReplayInputType_name = map[int32]string{
+		0: "REPLAY_INPUT_JOIN",
+		1: "REPLAY_INPUT_LEAVE",
+		2: "REPLAY_INPUT_MOVE",
+		3: "REPLAY_INPUT_SHOOT",
+		4: "REPLAY_INPUT_PLACE_BOMB",
+		5: "REPLAY_INPUT_SELECT_WEAPON",
+		6: "REPLAY_INPUT_RELOAD",
+		7: "REPLAY_INPUT_SWITCH_TEAM",
+		8: "REPLAY_INPUT_SET_DISPLAY_NAME",
+	}
+	ReplayInputType_value = map[string]int32{
+		"REPLAY_INPUT_JOIN":             0,
+		"REPLAY_INPUT_LEAVE":            1,
+		"REPLAY_INPUT_MOVE":             2,
+		"REPLAY_INPUT_SHOOT":            3,
+		"REPLAY_INPUT_PLACE_BOMB":       4,
+		"REPLAY_INPUT_SELECT_WEAPON":    5,
+		"REPLAY_INPUT_RELOAD":           6,
+		"REPLAY_INPUT_SWITCH_TEAM":      7,
+		"REPLAY_INPUT_SET_DISPLAY_NAME": 8,
+	}
+)
+
+func (x ReplayInputType) Enum() *ReplayInputType {
+	p := new(ReplayInputType)
+	*p = x
+	return p
+}
+
+func (x ReplayInputType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ReplayInputType) Descriptor() protoreflect.EnumDescriptor {
+	return file_game_proto_enumTypes[11].Descriptor()
+}
+
+func (ReplayInputType) Type() protoreflect.EnumType {
+	return &file_game_proto_enumTypes[11]
+}
+
+func (x ReplayInputType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ReplayInputType.Descriptor instead.
+func (ReplayInputType) EnumDescriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{11}
 }
 
 // 位置情報
@@ -328,7 +732,20 @@ type PlayerState struct {
 	Position  *Position              `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
 	Direction Direction              `protobuf:"varint,4,opt,name=direction,proto3,enum=terminalshooter.Direction" json:"direction,omitempty"`
 	// statusはserverからのみ送信する
-	Status        Status `protobuf:"varint,3,opt,name=status,proto3,enum=terminalshooter.Status" json:"status,omitempty"`
+	Status Status `protobuf:"varint,3,opt,name=status,proto3,enum=terminalshooter.Status" json:"status,omitempty"`
+	// teamはserverからのみ送信する
+	Team Team `protobuf:"varint,5,opt,name=team,proto3,enum=terminalshooter.Team" json:"team,omitempty"`
+	// hpとmax_hpはserverからのみ送信する
+	Hp    int32 `protobuf:"varint,6,opt,name=hp,proto3" json:"hp,omitempty"`
+	MaxHp int32 `protobuf:"varint,7,opt,name=max_hp,json=maxHp,proto3" json:"max_hp,omitempty"`
+	// effectsはserverからのみ送信する
+	Effects []*PowerUpEffect `protobuf:"bytes,8,rep,name=effects,proto3" json:"effects,omitempty"`
+	// weaponはserverからのみ送信する
+	Weapon *WeaponState `protobuf:"bytes,9,opt,name=weapon,proto3" json:"weapon,omitempty"`
+	// 1マス移動するのに必要なtick数。0なら制限なし。serverからのみ送信する
+	MoveTicks int32 `protobuf:"varint,10,opt,name=move_ticks,json=moveTicks,proto3" json:"move_ticks,omitempty"`
+	// 表示名。参加リクエストが受け付けられるまでは空。serverからのみ送信する
+	DisplayName   string `protobuf:"bytes,11,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -391,33 +808,88 @@ func (x *PlayerState) GetStatus() Status {
 	return Status_ALIVE
 }
 
-// アイテムの状態
-// item_stateトピックのPayloadとして使う
-type ItemState struct {
-	state    protoimpl.MessageState `protogen:"open.v1"`
-	ItemId   string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
-	Type     ItemType               `protobuf:"varint,2,opt,name=type,proto3,enum=terminalshooter.ItemType" json:"type,omitempty"`
-	Position *Position              `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
-	// statusはserverからのみ送信する
-	Status        ItemStatus `protobuf:"varint,4,opt,name=status,proto3,enum=terminalshooter.ItemStatus" json:"status,omitempty"`
+func (x *PlayerState) GetTeam() Team {
+	if x != nil {
+		return x.Team
+	}
+	return Team_NO_TEAM
+}
+
+func (x *PlayerState) GetHp() int32 {
+	if x != nil {
+		return x.Hp
+	}
+	return 0
+}
+
+func (x *PlayerState) GetMaxHp() int32 {
+	if x != nil {
+		return x.MaxHp
+	}
+	return 0
+}
+
+func (x *PlayerState) GetEffects() []*PowerUpEffect {
+	if x != nil {
+		return x.Effects
+	}
+	return nil
+}
+
+func (x *PlayerState) GetWeapon() *WeaponState {
+	if x != nil {
+		return x.Weapon
+	}
+	return nil
+}
+
+func (x *PlayerState) GetMoveTicks() int32 {
+	if x != nil {
+		return x.MoveTicks
+	}
+	return 0
+}
+
+func (x *PlayerState) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+// プレイヤーの武器の状態
+type WeaponState struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 次の弾を発射できるか
+	BulletReady bool `protobuf:"varint,1,opt,name=bullet_ready,json=bulletReady,proto3" json:"bullet_ready,omitempty"`
+	// 残りの弾数。max_ammoが0なら弾数は無制限
+	Ammo    int32 `protobuf:"varint,2,opt,name=ammo,proto3" json:"ammo,omitempty"`
+	MaxAmmo int32 `protobuf:"varint,3,opt,name=max_ammo,json=maxAmmo,proto3" json:"max_ammo,omitempty"`
+	// リロード中か
+	Reloading bool `protobuf:"varint,4,opt,name=reloading,proto3" json:"reloading,omitempty"`
+	// 設置中のボムの数。max_bombsが0ならボムの数は無制限
+	ActiveBombs int32 `protobuf:"varint,5,opt,name=active_bombs,json=activeBombs,proto3" json:"active_bombs,omitempty"`
+	MaxBombs    int32 `protobuf:"varint,6,opt,name=max_bombs,json=maxBombs,proto3" json:"max_bombs,omitempty"`
+	// 選択中の武器
+	Type          WeaponType `protobuf:"varint,7,opt,name=type,proto3,enum=terminalshooter.WeaponType" json:"type,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ItemState) Reset() {
-	*x = ItemState{}
+func (x *WeaponState) Reset() {
+	*x = WeaponState{}
 	mi := &file_game_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ItemState) String() string {
+func (x *WeaponState) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ItemState) ProtoMessage() {}
+func (*WeaponState) ProtoMessage() {}
 
-func (x *ItemState) ProtoReflect() protoreflect.Message {
+func (x *WeaponState) ProtoReflect() protoreflect.Message {
 	mi := &file_game_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -429,63 +901,1325 @@ func (x *ItemState) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ItemState.ProtoReflect.Descriptor instead.
-func (*ItemState) Descriptor() ([]byte, []int) {
+// Deprecated: Use WeaponState.ProtoReflect.Descriptor instead.
+func (*WeaponState) Descriptor() ([]byte, []int) {
 	return file_game_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *ItemState) GetItemId() string {
+func (x *WeaponState) GetBulletReady() bool {
 	if x != nil {
-		return x.ItemId
+		return x.BulletReady
+	}
+	return false
+}
+
+func (x *WeaponState) GetAmmo() int32 {
+	if x != nil {
+		return x.Ammo
+	}
+	return 0
+}
+
+func (x *WeaponState) GetMaxAmmo() int32 {
+	if x != nil {
+		return x.MaxAmmo
+	}
+	return 0
+}
+
+func (x *WeaponState) GetReloading() bool {
+	if x != nil {
+		return x.Reloading
+	}
+	return false
+}
+
+func (x *WeaponState) GetActiveBombs() int32 {
+	if x != nil {
+		return x.ActiveBombs
+	}
+	return 0
+}
+
+func (x *WeaponState) GetMaxBombs() int32 {
+	if x != nil {
+		return x.MaxBombs
+	}
+	return 0
+}
+
+func (x *WeaponState) GetType() WeaponType {
+	if x != nil {
+		return x.Type
+	}
+	return WeaponType_PISTOL
+}
+
+// プレイヤーにかかっているパワーアップの効果
+type PowerUpEffect struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 効果の元になったパワーアップアイテムの種類
+	Type             ItemType `protobuf:"varint,1,opt,name=type,proto3,enum=terminalshooter.ItemType" json:"type,omitempty"`
+	RemainingSeconds int32    `protobuf:"varint,2,opt,name=remaining_seconds,json=remainingSeconds,proto3" json:"remaining_seconds,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *PowerUpEffect) Reset() {
+	*x = PowerUpEffect{}
+	mi := &file_game_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PowerUpEffect) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PowerUpEffect) ProtoMessage() {}
+
+func (x *PowerUpEffect) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PowerUpEffect.ProtoReflect.Descriptor instead.
+func (*PowerUpEffect) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *PowerUpEffect) GetType() ItemType {
+	if x != nil {
+		return x.Type
+	}
+	return ItemType_BULLET
+}
+
+func (x *PowerUpEffect) GetRemainingSeconds() int32 {
+	if x != nil {
+		return x.RemainingSeconds
+	}
+	return 0
+}
+
+// アイテムの状態
+// item_stateトピックのPayloadとして使う
+type ItemState struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ItemId   string                 `protobuf:"bytes,1,opt,name=item_id,json=itemId,proto3" json:"item_id,omitempty"`
+	Type     ItemType               `protobuf:"varint,2,opt,name=type,proto3,enum=terminalshooter.ItemType" json:"type,omitempty"`
+	Position *Position              `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
+	// statusはserverからのみ送信する
+	Status ItemStatus `protobuf:"varint,4,opt,name=status,proto3,enum=terminalshooter.ItemStatus" json:"status,omitempty"`
+	// BULLETの場合に発射した武器
+	Weapon        WeaponType `protobuf:"varint,5,opt,name=weapon,proto3,enum=terminalshooter.WeaponType" json:"weapon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ItemState) Reset() {
+	*x = ItemState{}
+	mi := &file_game_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ItemState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ItemState) ProtoMessage() {}
+
+func (x *ItemState) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ItemState.ProtoReflect.Descriptor instead.
+func (*ItemState) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ItemState) GetItemId() string {
+	if x != nil {
+		return x.ItemId
+	}
+	return ""
+}
+
+func (x *ItemState) GetType() ItemType {
+	if x != nil {
+		return x.Type
+	}
+	return ItemType_BULLET
+}
+
+func (x *ItemState) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *ItemState) GetStatus() ItemStatus {
+	if x != nil {
+		return x.Status
+	}
+	return ItemStatus_ACTIVE
+}
+
+func (x *ItemState) GetWeapon() WeaponType {
+	if x != nil {
+		return x.Weapon
+	}
+	return WeaponType_PISTOL
+}
+
+// プレイヤーからのアクション
+// クライアントから送るplayer_actionトピックのPayloadとして使う
+type PlayerActionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  ActionType             `protobuf:"varint,1,opt,name=type,proto3,enum=terminalshooter.ActionType" json:"type,omitempty"`
+	// SWITCH_TEAMの場合の移動先チーム
+	Team Team `protobuf:"varint,2,opt,name=team,proto3,enum=terminalshooter.Team" json:"team,omitempty"`
+	// SELECT_WEAPONの場合に持ち替える武器
+	Weapon        WeaponType `protobuf:"varint,3,opt,name=weapon,proto3,enum=terminalshooter.WeaponType" json:"weapon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerActionRequest) Reset() {
+	*x = PlayerActionRequest{}
+	mi := &file_game_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerActionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerActionRequest) ProtoMessage() {}
+
+func (x *PlayerActionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerActionRequest.ProtoReflect.Descriptor instead.
+func (*PlayerActionRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PlayerActionRequest) GetType() ActionType {
+	if x != nil {
+		return x.Type
+	}
+	return ActionType_SHOOT_BULLET
+}
+
+func (x *PlayerActionRequest) GetTeam() Team {
+	if x != nil {
+		return x.Team
+	}
+	return Team_NO_TEAM
+}
+
+func (x *PlayerActionRequest) GetWeapon() WeaponType {
+	if x != nil {
+		return x.Weapon
+	}
+	return WeaponType_PISTOL
+}
+
+// マッチの状態
+// match_stateトピックのPayloadとして使う
+type MatchState struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Phase MatchPhase             `protobuf:"varint,1,opt,name=phase,proto3,enum=terminalshooter.MatchPhase" json:"phase,omitempty"`
+	// 現在のフェーズの残り秒数。制限がない場合は0
+	RemainingSeconds int32          `protobuf:"varint,2,opt,name=remaining_seconds,json=remainingSeconds,proto3" json:"remaining_seconds,omitempty"`
+	Scores           []*PlayerScore `protobuf:"bytes,3,rep,name=scores,proto3" json:"scores,omitempty"`
+	// 直近に終了したマッチの勝者
+	WinnerIds []string `protobuf:"bytes,4,rep,name=winner_ids,json=winnerIds,proto3" json:"winner_ids,omitempty"`
+	Mode      GameMode `protobuf:"varint,5,opt,name=mode,proto3,enum=terminalshooter.GameMode" json:"mode,omitempty"`
+	// 盤面の大きさ。クライアントはこの範囲の中だけを移動できる
+	BoardWidth  int32 `protobuf:"varint,6,opt,name=board_width,json=boardWidth,proto3" json:"board_width,omitempty"`
+	BoardHeight int32 `protobuf:"varint,7,opt,name=board_height,json=boardHeight,proto3" json:"board_height,omitempty"`
+	// 1秒あたりのtick数。クライアントはtick数で送られてくる時間をこれで実時間に直す
+	TicksPerSecond int32 `protobuf:"varint,8,opt,name=ticks_per_second,json=ticksPerSecond,proto3" json:"ticks_per_second,omitempty"`
+	// プレイヤーに状態が配信される範囲の半径。0なら盤面全体が配信される
+	// クライアントは範囲外のプレイヤーやアイテムを表示しない
+	InterestRadius int32 `protobuf:"varint,9,opt,name=interest_radius,json=interestRadius,proto3" json:"interest_radius,omitempty"`
+	// チーム戦のチームごとのスコア。倒した時点のチームに加算されるので、抜けたプレイヤーの得点も残る
+	TeamScores    []*TeamScore `protobuf:"bytes,10,rep,name=team_scores,json=teamScores,proto3" json:"team_scores,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *MatchState) Reset() {
+	*x = MatchState{}
+	mi := &file_game_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MatchState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MatchState) ProtoMessage() {}
+
+func (x *MatchState) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MatchState.ProtoReflect.Descriptor instead.
+func (*MatchState) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MatchState) GetPhase() MatchPhase {
+	if x != nil {
+		return x.Phase
+	}
+	return MatchPhase_WAITING
+}
+
+func (x *MatchState) GetRemainingSeconds() int32 {
+	if x != nil {
+		return x.RemainingSeconds
+	}
+	return 0
+}
+
+func (x *MatchState) GetScores() []*PlayerScore {
+	if x != nil {
+		return x.Scores
+	}
+	return nil
+}
+
+func (x *MatchState) GetWinnerIds() []string {
+	if x != nil {
+		return x.WinnerIds
+	}
+	return nil
+}
+
+func (x *MatchState) GetMode() GameMode {
+	if x != nil {
+		return x.Mode
+	}
+	return GameMode_DEATHMATCH
+}
+
+func (x *MatchState) GetBoardWidth() int32 {
+	if x != nil {
+		return x.BoardWidth
+	}
+	return 0
+}
+
+func (x *MatchState) GetBoardHeight() int32 {
+	if x != nil {
+		return x.BoardHeight
+	}
+	return 0
+}
+
+func (x *MatchState) GetTicksPerSecond() int32 {
+	if x != nil {
+		return x.TicksPerSecond
+	}
+	return 0
+}
+
+func (x *MatchState) GetInterestRadius() int32 {
+	if x != nil {
+		return x.InterestRadius
+	}
+	return 0
+}
+
+func (x *MatchState) GetTeamScores() []*TeamScore {
+	if x != nil {
+		return x.TeamScores
+	}
+	return nil
+}
+
+// プレイヤーごとのスコア
+type PlayerScore struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Score         int32                  `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerScore) Reset() {
+	*x = PlayerScore{}
+	mi := &file_game_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerScore) ProtoMessage() {}
+
+func (x *PlayerScore) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerScore.ProtoReflect.Descriptor instead.
+func (*PlayerScore) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *PlayerScore) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *PlayerScore) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+// チームごとのスコア
+type TeamScore struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Team          Team                   `protobuf:"varint,1,opt,name=team,proto3,enum=terminalshooter.Team" json:"team,omitempty"`
+	Score         int32                  `protobuf:"varint,2,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TeamScore) Reset() {
+	*x = TeamScore{}
+	mi := &file_game_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TeamScore) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TeamScore) ProtoMessage() {}
+
+func (x *TeamScore) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TeamScore.ProtoReflect.Descriptor instead.
+func (*TeamScore) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *TeamScore) GetTeam() Team {
+	if x != nil {
+		return x.Team
+	}
+	return Team_NO_TEAM
+}
+
+func (x *TeamScore) GetScore() int32 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+// ゲーム内で起きた出来事
+// game_eventトピックのPayloadとして使う。serverからのみ送信する
+type GameEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Event:
+	//
+	//	*GameEvent_PlayerKilled
+	//	*GameEvent_BombExploded
+	//	*GameEvent_PlayerJoined
+	//	*GameEvent_PlayerLeft
+	//	*GameEvent_PowerUpCollected
+	Event         isGameEvent_Event `protobuf_oneof:"event"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GameEvent) Reset() {
+	*x = GameEvent{}
+	mi := &file_game_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GameEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GameEvent) ProtoMessage() {}
+
+func (x *GameEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GameEvent.ProtoReflect.Descriptor instead.
+func (*GameEvent) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GameEvent) GetEvent() isGameEvent_Event {
+	if x != nil {
+		return x.Event
+	}
+	return nil
+}
+
+func (x *GameEvent) GetPlayerKilled() *PlayerKilled {
+	if x != nil {
+		if x, ok := x.Event.(*GameEvent_PlayerKilled); ok {
+			return x.PlayerKilled
+		}
+	}
+	return nil
+}
+
+func (x *GameEvent) GetBombExploded() *BombExploded {
+	if x != nil {
+		if x, ok := x.Event.(*GameEvent_BombExploded); ok {
+			return x.BombExploded
+		}
+	}
+	return nil
+}
+
+func (x *GameEvent) GetPlayerJoined() *PlayerJoined {
+	if x != nil {
+		if x, ok := x.Event.(*GameEvent_PlayerJoined); ok {
+			return x.PlayerJoined
+		}
+	}
+	return nil
+}
+
+func (x *GameEvent) GetPlayerLeft() *PlayerLeft {
+	if x != nil {
+		if x, ok := x.Event.(*GameEvent_PlayerLeft); ok {
+			return x.PlayerLeft
+		}
+	}
+	return nil
+}
+
+func (x *GameEvent) GetPowerUpCollected() *PowerUpCollected {
+	if x != nil {
+		if x, ok := x.Event.(*GameEvent_PowerUpCollected); ok {
+			return x.PowerUpCollected
+		}
+	}
+	return nil
+}
+
+type isGameEvent_Event interface {
+	isGameEvent_Event()
+}
+
+type GameEvent_PlayerKilled struct {
+	PlayerKilled *PlayerKilled `protobuf:"bytes,1,opt,name=player_killed,json=playerKilled,proto3,oneof"`
+}
+
+type GameEvent_BombExploded struct {
+	BombExploded *BombExploded `protobuf:"bytes,2,opt,name=bomb_exploded,json=bombExploded,proto3,oneof"`
+}
+
+type GameEvent_PlayerJoined struct {
+	PlayerJoined *PlayerJoined `protobuf:"bytes,3,opt,name=player_joined,json=playerJoined,proto3,oneof"`
+}
+
+type GameEvent_PlayerLeft struct {
+	PlayerLeft *PlayerLeft `protobuf:"bytes,4,opt,name=player_left,json=playerLeft,proto3,oneof"`
+}
+
+type GameEvent_PowerUpCollected struct {
+	PowerUpCollected *PowerUpCollected `protobuf:"bytes,5,opt,name=power_up_collected,json=powerUpCollected,proto3,oneof"`
+}
+
+func (*GameEvent_PlayerKilled) isGameEvent_Event() {}
+
+func (*GameEvent_BombExploded) isGameEvent_Event() {}
+
+func (*GameEvent_PlayerJoined) isGameEvent_Event() {}
+
+func (*GameEvent_PlayerLeft) isGameEvent_Event() {}
+
+func (*GameEvent_PowerUpCollected) isGameEvent_Event() {}
+
+// プレイヤーが倒された
+type PlayerKilled struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	VictimId string                 `protobuf:"bytes,1,opt,name=victim_id,json=victimId,proto3" json:"victim_id,omitempty"`
+	// 倒したプレイヤー。持ち主のいない攻撃の場合は空
+	KillerId string `protobuf:"bytes,2,opt,name=killer_id,json=killerId,proto3" json:"killer_id,omitempty"`
+	// 倒した攻撃のアイテムの種類。BULLETかBOMB_FIRE
+	Cause ItemType `protobuf:"varint,3,opt,name=cause,proto3,enum=terminalshooter.ItemType" json:"cause,omitempty"`
+	// causeがBULLETの場合に発射した武器
+	Weapon        WeaponType `protobuf:"varint,4,opt,name=weapon,proto3,enum=terminalshooter.WeaponType" json:"weapon,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerKilled) Reset() {
+	*x = PlayerKilled{}
+	mi := &file_game_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerKilled) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerKilled) ProtoMessage() {}
+
+func (x *PlayerKilled) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerKilled.ProtoReflect.Descriptor instead.
+func (*PlayerKilled) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *PlayerKilled) GetVictimId() string {
+	if x != nil {
+		return x.VictimId
+	}
+	return ""
+}
+
+func (x *PlayerKilled) GetKillerId() string {
+	if x != nil {
+		return x.KillerId
+	}
+	return ""
+}
+
+func (x *PlayerKilled) GetCause() ItemType {
+	if x != nil {
+		return x.Cause
+	}
+	return ItemType_BULLET
+}
+
+func (x *PlayerKilled) GetWeapon() WeaponType {
+	if x != nil {
+		return x.Weapon
+	}
+	return WeaponType_PISTOL
+}
+
+// ボムが爆発した
+type BombExploded struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ボムを設置したプレイヤー
+	OwnerId       string    `protobuf:"bytes,1,opt,name=owner_id,json=ownerId,proto3" json:"owner_id,omitempty"`
+	Position      *Position `protobuf:"bytes,2,opt,name=position,proto3" json:"position,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BombExploded) Reset() {
+	*x = BombExploded{}
+	mi := &file_game_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BombExploded) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BombExploded) ProtoMessage() {}
+
+func (x *BombExploded) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BombExploded.ProtoReflect.Descriptor instead.
+func (*BombExploded) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BombExploded) GetOwnerId() string {
+	if x != nil {
+		return x.OwnerId
+	}
+	return ""
+}
+
+func (x *BombExploded) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+// プレイヤーがゲームに参加した
+type PlayerJoined struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerJoined) Reset() {
+	*x = PlayerJoined{}
+	mi := &file_game_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerJoined) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerJoined) ProtoMessage() {}
+
+func (x *PlayerJoined) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerJoined.ProtoReflect.Descriptor instead.
+func (*PlayerJoined) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PlayerJoined) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+// プレイヤーがゲームから抜けた
+type PlayerLeft struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PlayerLeft) Reset() {
+	*x = PlayerLeft{}
+	mi := &file_game_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PlayerLeft) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PlayerLeft) ProtoMessage() {}
+
+func (x *PlayerLeft) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PlayerLeft.ProtoReflect.Descriptor instead.
+func (*PlayerLeft) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *PlayerLeft) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+// プレイヤーがパワーアップを拾った
+type PowerUpCollected struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PlayerId      string                 `protobuf:"bytes,1,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	Type          ItemType               `protobuf:"varint,2,opt,name=type,proto3,enum=terminalshooter.ItemType" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PowerUpCollected) Reset() {
+	*x = PowerUpCollected{}
+	mi := &file_game_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PowerUpCollected) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PowerUpCollected) ProtoMessage() {}
+
+func (x *PowerUpCollected) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PowerUpCollected.ProtoReflect.Descriptor instead.
+func (*PowerUpCollected) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PowerUpCollected) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *PowerUpCollected) GetType() ItemType {
+	if x != nil {
+		return x.Type
+	}
+	return ItemType_BULLET
+}
+
+// クライアントが接続後に送る参加リクエスト
+type JoinRequest struct {
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	DisplayName string                 `protobuf:"bytes,1,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// 希望するチーム。チーム戦で人数差が広がらない場合のみ反映される
+	PreferredTeam Team `protobuf:"varint,2,opt,name=preferred_team,json=preferredTeam,proto3,enum=terminalshooter.Team" json:"preferred_team,omitempty"`
+	// クライアントのバージョン。serverと一致しない場合は参加できない
+	ClientVersion string `protobuf:"bytes,3,opt,name=client_version,json=clientVersion,proto3" json:"client_version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinRequest) Reset() {
+	*x = JoinRequest{}
+	mi := &file_game_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinRequest) ProtoMessage() {}
+
+func (x *JoinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinRequest.ProtoReflect.Descriptor instead.
+func (*JoinRequest) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *JoinRequest) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *JoinRequest) GetPreferredTeam() Team {
+	if x != nil {
+		return x.PreferredTeam
+	}
+	return Team_NO_TEAM
+}
+
+func (x *JoinRequest) GetClientVersion() string {
+	if x != nil {
+		return x.ClientVersion
+	}
+	return ""
+}
+
+// 参加リクエストへの応答。リクエストしたクライアントにのみ送信する
+type JoinResponse struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Result   JoinResult             `protobuf:"varint,1,opt,name=result,proto3,enum=terminalshooter.JoinResult" json:"result,omitempty"`
+	PlayerId string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	// 受け付けられた表示名
+	DisplayName string `protobuf:"bytes,3,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	// 受け付けられなかった理由
+	Message       string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *JoinResponse) Reset() {
+	*x = JoinResponse{}
+	mi := &file_game_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *JoinResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*JoinResponse) ProtoMessage() {}
+
+func (x *JoinResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use JoinResponse.ProtoReflect.Descriptor instead.
+func (*JoinResponse) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *JoinResponse) GetResult() JoinResult {
+	if x != nil {
+		return x.Result
+	}
+	return JoinResult_JOIN_ACCEPTED
+}
+
+func (x *JoinResponse) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *JoinResponse) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
+}
+
+func (x *JoinResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// チャットのメッセージ
+// クライアントはtextとscopeのみ送信し、serverが送信者を埋めて配信する
+type ChatMessage struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	SenderId   string                 `protobuf:"bytes,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	SenderName string                 `protobuf:"bytes,2,opt,name=sender_name,json=senderName,proto3" json:"sender_name,omitempty"`
+	Text       string                 `protobuf:"bytes,3,opt,name=text,proto3" json:"text,omitempty"`
+	Scope      ChatScope              `protobuf:"varint,4,opt,name=scope,proto3,enum=terminalshooter.ChatScope" json:"scope,omitempty"`
+	// serverからのお知らせか。送信が受け付けられなかった理由などを送信者にのみ送る
+	System        bool `protobuf:"varint,5,opt,name=system,proto3" json:"system,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMessage) Reset() {
+	*x = ChatMessage{}
+	mi := &file_game_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMessage) ProtoMessage() {}
+
+func (x *ChatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
+func (*ChatMessage) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ChatMessage) GetSenderId() string {
+	if x != nil {
+		return x.SenderId
 	}
 	return ""
 }
 
-func (x *ItemState) GetType() ItemType {
+func (x *ChatMessage) GetSenderName() string {
 	if x != nil {
-		return x.Type
+		return x.SenderName
 	}
-	return ItemType_BULLET
+	return ""
 }
 
-func (x *ItemState) GetPosition() *Position {
+func (x *ChatMessage) GetText() string {
 	if x != nil {
-		return x.Position
+		return x.Text
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetScope() ChatScope {
+	if x != nil {
+		return x.Scope
+	}
+	return ChatScope_CHAT_SCOPE_ALL
+}
+
+func (x *ChatMessage) GetSystem() bool {
+	if x != nil {
+		return x.System
+	}
+	return false
+}
+
+// リプレイファイルの先頭に書き込む、マッチの情報と記録を開始した時点の盤面
+// リプレイファイルは、長さ(uvarint)を前に付けたReplayHeaderに続けて、同じく長さを前に付けたReplayTickを並べたもの
+type ReplayHeader struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// 記録したserverのバージョン
+	ServerVersion   string `protobuf:"bytes,1,opt,name=server_version,json=serverVersion,proto3" json:"server_version,omitempty"`
+	ProtocolVersion string `protobuf:"bytes,2,opt,name=protocol_version,json=protocolVersion,proto3" json:"protocol_version,omitempty"`
+	// 記録を開始した時刻(Unixミリ秒)
+	StartedAt int64 `protobuf:"varint,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	// 記録を開始したtick
+	StartTick int64 `protobuf:"varint,4,opt,name=start_tick,json=startTick,proto3" json:"start_tick,omitempty"`
+	// ゲーム内の乱数のシード
+	Seed          int64          `protobuf:"varint,5,opt,name=seed,proto3" json:"seed,omitempty"`
+	Map           *ReplayMap     `protobuf:"bytes,6,opt,name=map,proto3" json:"map,omitempty"`
+	Players       []*PlayerState `protobuf:"bytes,7,rep,name=players,proto3" json:"players,omitempty"`
+	Items         []*ItemState   `protobuf:"bytes,8,rep,name=items,proto3" json:"items,omitempty"`
+	Match         *MatchState    `protobuf:"bytes,9,opt,name=match,proto3" json:"match,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayHeader) Reset() {
+	*x = ReplayHeader{}
+	mi := &file_game_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayHeader) ProtoMessage() {}
+
+func (x *ReplayHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayHeader.ProtoReflect.Descriptor instead.
+func (*ReplayHeader) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ReplayHeader) GetServerVersion() string {
+	if x != nil {
+		return x.ServerVersion
+	}
+	return ""
+}
+
+func (x *ReplayHeader) GetProtocolVersion() string {
+	if x != nil {
+		return x.ProtocolVersion
+	}
+	return ""
+}
+
+func (x *ReplayHeader) GetStartedAt() int64 {
+	if x != nil {
+		return x.StartedAt
+	}
+	return 0
+}
+
+func (x *ReplayHeader) GetStartTick() int64 {
+	if x != nil {
+		return x.StartTick
+	}
+	return 0
+}
+
+func (x *ReplayHeader) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *ReplayHeader) GetMap() *ReplayMap {
+	if x != nil {
+		return x.Map
 	}
 	return nil
 }
 
-func (x *ItemState) GetStatus() ItemStatus {
+func (x *ReplayHeader) GetPlayers() []*PlayerState {
 	if x != nil {
-		return x.Status
+		return x.Players
 	}
-	return ItemStatus_ACTIVE
+	return nil
 }
 
-// プレイヤーからのアクション
-// クライアントから送るplayer_actionトピックのPayloadとして使う
-type PlayerActionRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          ActionType             `protobuf:"varint,1,opt,name=type,proto3,enum=terminalshooter.ActionType" json:"type,omitempty"`
+func (x *ReplayHeader) GetItems() []*ItemState {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ReplayHeader) GetMatch() *MatchState {
+	if x != nil {
+		return x.Match
+	}
+	return nil
+}
+
+// 盤面の大きさと障害物の配置ルール
+type ReplayMap struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Width          int32                  `protobuf:"varint,1,opt,name=width,proto3" json:"width,omitempty"`
+	Height         int32                  `protobuf:"varint,2,opt,name=height,proto3" json:"height,omitempty"`
+	PillarInterval int32                  `protobuf:"varint,3,opt,name=pillar_interval,json=pillarInterval,proto3" json:"pillar_interval,omitempty"`
+	BlockDensity   float64                `protobuf:"fixed64,4,opt,name=block_density,json=blockDensity,proto3" json:"block_density,omitempty"`
+	PowerUpChance  float64                `protobuf:"fixed64,5,opt,name=power_up_chance,json=powerUpChance,proto3" json:"power_up_chance,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ReplayMap) Reset() {
+	*x = ReplayMap{}
+	mi := &file_game_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayMap) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayMap) ProtoMessage() {}
+
+func (x *ReplayMap) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayMap.ProtoReflect.Descriptor instead.
+func (*ReplayMap) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ReplayMap) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *ReplayMap) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *ReplayMap) GetPillarInterval() int32 {
+	if x != nil {
+		return x.PillarInterval
+	}
+	return 0
+}
+
+func (x *ReplayMap) GetBlockDensity() float64 {
+	if x != nil {
+		return x.BlockDensity
+	}
+	return 0
+}
+
+func (x *ReplayMap) GetPowerUpChance() float64 {
+	if x != nil {
+		return x.PowerUpChance
+	}
+	return 0
+}
+
+// 1tickの間に受け付けた操作と、その結果変化した状態
+// 何も起きなかったtickは書き込まない
+type ReplayTick struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Tick   int64                  `protobuf:"varint,1,opt,name=tick,proto3" json:"tick,omitempty"`
+	Inputs []*ReplayInput         `protobuf:"bytes,2,rep,name=inputs,proto3" json:"inputs,omitempty"`
+	// 変化したプレイヤーとアイテムの、tickの終わりの状態
+	Players []*PlayerState `protobuf:"bytes,3,rep,name=players,proto3" json:"players,omitempty"`
+	Items   []*ItemState   `protobuf:"bytes,4,rep,name=items,proto3" json:"items,omitempty"`
+	// マッチの状態が変化した場合のみ
+	Match         *MatchState  `protobuf:"bytes,5,opt,name=match,proto3" json:"match,omitempty"`
+	Events        []*GameEvent `protobuf:"bytes,6,rep,name=events,proto3" json:"events,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *PlayerActionRequest) Reset() {
-	*x = PlayerActionRequest{}
-	mi := &file_game_proto_msgTypes[3]
+func (x *ReplayTick) Reset() {
+	*x = ReplayTick{}
+	mi := &file_game_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *PlayerActionRequest) String() string {
+func (x *ReplayTick) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*PlayerActionRequest) ProtoMessage() {}
+func (*ReplayTick) ProtoMessage() {}
 
-func (x *PlayerActionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_game_proto_msgTypes[3]
+func (x *ReplayTick) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -496,16 +2230,148 @@ func (x *PlayerActionRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use PlayerActionRequest.ProtoReflect.Descriptor instead.
-func (*PlayerActionRequest) Descriptor() ([]byte, []int) {
-	return file_game_proto_rawDescGZIP(), []int{3}
+// Deprecated: Use ReplayTick.ProtoReflect.Descriptor instead.
+func (*ReplayTick) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{20}
 }
 
-func (x *PlayerActionRequest) GetType() ActionType {
+func (x *ReplayTick) GetTick() int64 {
+	if x != nil {
+		return x.Tick
+	}
+	return 0
+}
+
+func (x *ReplayTick) GetInputs() []*ReplayInput {
+	if x != nil {
+		return x.Inputs
+	}
+	return nil
+}
+
+func (x *ReplayTick) GetPlayers() []*PlayerState {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *ReplayTick) GetItems() []*ItemState {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+func (x *ReplayTick) GetMatch() *MatchState {
+	if x != nil {
+		return x.Match
+	}
+	return nil
+}
+
+func (x *ReplayTick) GetEvents() []*GameEvent {
+	if x != nil {
+		return x.Events
+	}
+	return nil
+}
+
+// ゲームの外から受け付けた操作。受け付けられたかどうかに関わらず記録する
+type ReplayInput struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Type     ReplayInputType        `protobuf:"varint,1,opt,name=type,proto3,enum=terminalshooter.ReplayInputType" json:"type,omitempty"`
+	PlayerId string                 `protobuf:"bytes,2,opt,name=player_id,json=playerId,proto3" json:"player_id,omitempty"`
+	// REPLAY_INPUT_MOVEの移動先と向き
+	Position  *Position `protobuf:"bytes,3,opt,name=position,proto3" json:"position,omitempty"`
+	Direction Direction `protobuf:"varint,4,opt,name=direction,proto3,enum=terminalshooter.Direction" json:"direction,omitempty"`
+	// REPLAY_INPUT_SWITCH_TEAMのチーム
+	Team Team `protobuf:"varint,5,opt,name=team,proto3,enum=terminalshooter.Team" json:"team,omitempty"`
+	// REPLAY_INPUT_SELECT_WEAPONの武器
+	Weapon WeaponType `protobuf:"varint,6,opt,name=weapon,proto3,enum=terminalshooter.WeaponType" json:"weapon,omitempty"`
+	// REPLAY_INPUT_SET_DISPLAY_NAMEの表示名
+	DisplayName   string `protobuf:"bytes,7,opt,name=display_name,json=displayName,proto3" json:"display_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayInput) Reset() {
+	*x = ReplayInput{}
+	mi := &file_game_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayInput) ProtoMessage() {}
+
+func (x *ReplayInput) ProtoReflect() protoreflect.Message {
+	mi := &file_game_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayInput.ProtoReflect.Descriptor instead.
+func (*ReplayInput) Descriptor() ([]byte, []int) {
+	return file_game_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ReplayInput) GetType() ReplayInputType {
 	if x != nil {
 		return x.Type
 	}
-	return ActionType_SHOOT_BULLET
+	return ReplayInputType_REPLAY_INPUT_JOIN
+}
+
+func (x *ReplayInput) GetPlayerId() string {
+	if x != nil {
+		return x.PlayerId
+	}
+	return ""
+}
+
+func (x *ReplayInput) GetPosition() *Position {
+	if x != nil {
+		return x.Position
+	}
+	return nil
+}
+
+func (x *ReplayInput) GetDirection() Direction {
+	if x != nil {
+		return x.Direction
+	}
+	return Direction_UP
+}
+
+func (x *ReplayInput) GetTeam() Team {
+	if x != nil {
+		return x.Team
+	}
+	return Team_NO_TEAM
+}
+
+func (x *ReplayInput) GetWeapon() WeaponType {
+	if x != nil {
+		return x.Weapon
+	}
+	return WeaponType_PISTOL
+}
+
+func (x *ReplayInput) GetDisplayName() string {
+	if x != nil {
+		return x.DisplayName
+	}
+	return ""
 }
 
 var File_game_proto protoreflect.FileDescriptor
@@ -515,7 +2381,7 @@ var file_game_proto_rawDesc = []byte{
 	0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x22, 0x26, 0x0a,
 	0x08, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x0c, 0x0a, 0x01, 0x78, 0x18, 0x01,
 	0x20, 0x01, 0x28, 0x05, 0x52, 0x01, 0x78, 0x12, 0x0c, 0x0a, 0x01, 0x79, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x05, 0x52, 0x01, 0x79, 0x22, 0xcc, 0x01, 0x0a, 0x0b, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x28, 0x05, 0x52, 0x01, 0x79, 0x22, 0xd0, 0x03, 0x0a, 0x0b, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72,
 	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f,
 	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72,
 	0x49, 0x64, 0x12, 0x35, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02,
@@ -528,42 +2394,336 @@ var file_game_proto_rawDesc = []byte{
 	0x69, 0x6f, 0x6e, 0x12, 0x2f, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20,
 	0x01, 0x28, 0x0e, 0x32, 0x17, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68,
 	0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x22, 0xbf, 0x01, 0x0a, 0x09, 0x49, 0x74, 0x65, 0x6d, 0x53, 0x74, 0x61,
-	0x74, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x74, 0x65, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20,
-	0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x64, 0x12, 0x2d, 0x0a, 0x04, 0x74,
-	0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x74, 0x65, 0x72, 0x6d,
-	0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x49, 0x74, 0x65, 0x6d,
-	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x70, 0x6f,
-	0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74,
-	0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x50,
-	0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
-	0x6e, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x61, 0x74, 0x75, 0x73, 0x12, 0x29, 0x0a, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x15, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f,
+	0x6f, 0x74, 0x65, 0x72, 0x2e, 0x54, 0x65, 0x61, 0x6d, 0x52, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x12,
+	0x0e, 0x0a, 0x02, 0x68, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x02, 0x68, 0x70, 0x12,
+	0x15, 0x0a, 0x06, 0x6d, 0x61, 0x78, 0x5f, 0x68, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x6d, 0x61, 0x78, 0x48, 0x70, 0x12, 0x38, 0x0a, 0x07, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74,
+	0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e,
+	0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x55,
+	0x70, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x52, 0x07, 0x65, 0x66, 0x66, 0x65, 0x63, 0x74, 0x73,
+	0x12, 0x34, 0x0a, 0x06, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x18, 0x09, 0x20, 0x01, 0x28, 0x0b,
+	0x32, 0x1c, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74,
+	0x65, 0x72, 0x2e, 0x57, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x06,
+	0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x6d, 0x6f, 0x76, 0x65, 0x5f, 0x74,
+	0x69, 0x63, 0x6b, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x6d, 0x6f, 0x76, 0x65,
+	0x54, 0x69, 0x63, 0x6b, 0x73, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79,
+	0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73,
+	0x70, 0x6c, 0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0xee, 0x01, 0x0a, 0x0b, 0x57, 0x65, 0x61,
+	0x70, 0x6f, 0x6e, 0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x75, 0x6c, 0x6c,
+	0x65, 0x74, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0b,
+	0x62, 0x75, 0x6c, 0x6c, 0x65, 0x74, 0x52, 0x65, 0x61, 0x64, 0x79, 0x12, 0x12, 0x0a, 0x04, 0x61,
+	0x6d, 0x6d, 0x6f, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x61, 0x6d, 0x6d, 0x6f, 0x12,
+	0x19, 0x0a, 0x08, 0x6d, 0x61, 0x78, 0x5f, 0x61, 0x6d, 0x6d, 0x6f, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x07, 0x6d, 0x61, 0x78, 0x41, 0x6d, 0x6d, 0x6f, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65,
+	0x6c, 0x6f, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x18, 0x04, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x72,
+	0x65, 0x6c, 0x6f, 0x61, 0x64, 0x69, 0x6e, 0x67, 0x12, 0x21, 0x0a, 0x0c, 0x61, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x5f, 0x62, 0x6f, 0x6d, 0x62, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b,
+	0x61, 0x63, 0x74, 0x69, 0x76, 0x65, 0x42, 0x6f, 0x6d, 0x62, 0x73, 0x12, 0x1b, 0x0a, 0x09, 0x6d,
+	0x61, 0x78, 0x5f, 0x62, 0x6f, 0x6d, 0x62, 0x73, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08,
+	0x6d, 0x61, 0x78, 0x42, 0x6f, 0x6d, 0x62, 0x73, 0x12, 0x2f, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65,
+	0x18, 0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61,
+	0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x57, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x22, 0x6b, 0x0a, 0x0d, 0x50, 0x6f, 0x77,
+	0x65, 0x72, 0x55, 0x70, 0x45, 0x66, 0x66, 0x65, 0x63, 0x74, 0x12, 0x2d, 0x0a, 0x04, 0x74, 0x79,
+	0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69,
+	0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x54,
+	0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x72, 0x65, 0x6d,
+	0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x05, 0x52, 0x10, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x53,
+	0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x22, 0xf4, 0x01, 0x0a, 0x09, 0x49, 0x74, 0x65, 0x6d, 0x53,
+	0x74, 0x61, 0x74, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x74, 0x65, 0x6d, 0x5f, 0x69, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x69, 0x74, 0x65, 0x6d, 0x49, 0x64, 0x12, 0x2d, 0x0a,
+	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x74, 0x65,
+	0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x49, 0x74,
+	0x65, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x35, 0x0a, 0x08,
+	0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19,
+	0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72,
+	0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x33, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68,
+	0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x33, 0x0a, 0x06, 0x77, 0x65, 0x61, 0x70,
+	0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69,
+	0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x57, 0x65, 0x61, 0x70, 0x6f,
+	0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x22, 0xa6, 0x01,
+	0x0a, 0x13, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68,
+	0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x29, 0x0a, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73,
+	0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x54, 0x65, 0x61, 0x6d, 0x52, 0x04, 0x74, 0x65, 0x61,
+	0x6d, 0x12, 0x33, 0x0a, 0x06, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28,
 	0x0e, 0x32, 0x1b, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f,
-	0x74, 0x65, 0x72, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x46, 0x0a, 0x13, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72,
-	0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x2f, 0x0a,
-	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x74, 0x65,
-	0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x41, 0x63,
-	0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x2a, 0x25,
-	0x0a, 0x0a, 0x49, 0x74, 0x65, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0a, 0x0a, 0x06,
-	0x41, 0x43, 0x54, 0x49, 0x56, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x45, 0x4d, 0x4f,
-	0x56, 0x45, 0x44, 0x10, 0x01, 0x2a, 0x32, 0x0a, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69,
-	0x6f, 0x6e, 0x12, 0x06, 0x0a, 0x02, 0x55, 0x50, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x4f,
-	0x57, 0x4e, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x45, 0x46, 0x54, 0x10, 0x02, 0x12, 0x09,
-	0x0a, 0x05, 0x52, 0x49, 0x47, 0x48, 0x54, 0x10, 0x03, 0x2a, 0x2f, 0x0a, 0x06, 0x53, 0x74, 0x61,
-	0x74, 0x75, 0x73, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x4c, 0x49, 0x56, 0x45, 0x10, 0x00, 0x12, 0x08,
-	0x0a, 0x04, 0x44, 0x45, 0x41, 0x44, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x44, 0x49, 0x53, 0x43,
-	0x4f, 0x4e, 0x4e, 0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x01, 0x2a, 0x2f, 0x0a, 0x08, 0x49, 0x74,
-	0x65, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x42, 0x55, 0x4c, 0x4c, 0x45, 0x54,
-	0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x42, 0x4f, 0x4d, 0x42, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09,
-	0x42, 0x4f, 0x4d, 0x42, 0x5f, 0x46, 0x49, 0x52, 0x45, 0x10, 0x02, 0x2a, 0x2e, 0x0a, 0x0a, 0x41,
-	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x10, 0x0a, 0x0c, 0x53, 0x48, 0x4f,
-	0x4f, 0x54, 0x5f, 0x42, 0x55, 0x4c, 0x4c, 0x45, 0x54, 0x10, 0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x50,
-	0x4c, 0x41, 0x43, 0x45, 0x5f, 0x42, 0x4f, 0x4d, 0x42, 0x10, 0x01, 0x42, 0x2e, 0x5a, 0x2c, 0x67,
-	0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x68, 0x69, 0x62, 0x61, 0x79,
-	0x75, 0x33, 0x36, 0x2f, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x2d, 0x73, 0x68, 0x6f,
-	0x6f, 0x74, 0x65, 0x72, 0x2f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x62, 0x06, 0x70, 0x72, 0x6f,
-	0x74, 0x6f, 0x33,
+	0x74, 0x65, 0x72, 0x2e, 0x57, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x06,
+	0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x22, 0xc4, 0x03, 0x0a, 0x0a, 0x4d, 0x61, 0x74, 0x63, 0x68,
+	0x53, 0x74, 0x61, 0x74, 0x65, 0x12, 0x31, 0x0a, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x18, 0x01,
+	0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73,
+	0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x50, 0x68, 0x61, 0x73,
+	0x65, 0x52, 0x05, 0x70, 0x68, 0x61, 0x73, 0x65, 0x12, 0x2b, 0x0a, 0x11, 0x72, 0x65, 0x6d, 0x61,
+	0x69, 0x6e, 0x69, 0x6e, 0x67, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x73, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x10, 0x72, 0x65, 0x6d, 0x61, 0x69, 0x6e, 0x69, 0x6e, 0x67, 0x53, 0x65,
+	0x63, 0x6f, 0x6e, 0x64, 0x73, 0x12, 0x34, 0x0a, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18,
+	0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c,
+	0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x53, 0x63,
+	0x6f, 0x72, 0x65, 0x52, 0x06, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x12, 0x1d, 0x0a, 0x0a, 0x77,
+	0x69, 0x6e, 0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52,
+	0x09, 0x77, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x49, 0x64, 0x73, 0x12, 0x2d, 0x0a, 0x04, 0x6d, 0x6f,
+	0x64, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69,
+	0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x4d,
+	0x6f, 0x64, 0x65, 0x52, 0x04, 0x6d, 0x6f, 0x64, 0x65, 0x12, 0x1f, 0x0a, 0x0b, 0x62, 0x6f, 0x61,
+	0x72, 0x64, 0x5f, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a,
+	0x62, 0x6f, 0x61, 0x72, 0x64, 0x57, 0x69, 0x64, 0x74, 0x68, 0x12, 0x21, 0x0a, 0x0c, 0x62, 0x6f,
+	0x61, 0x72, 0x64, 0x5f, 0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0b, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x48, 0x65, 0x69, 0x67, 0x68, 0x74, 0x12, 0x28, 0x0a,
+	0x10, 0x74, 0x69, 0x63, 0x6b, 0x73, 0x5f, 0x70, 0x65, 0x72, 0x5f, 0x73, 0x65, 0x63, 0x6f, 0x6e,
+	0x64, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x74, 0x69, 0x63, 0x6b, 0x73, 0x50, 0x65,
+	0x72, 0x53, 0x65, 0x63, 0x6f, 0x6e, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x69, 0x6e, 0x74, 0x65, 0x72,
+	0x65, 0x73, 0x74, 0x5f, 0x72, 0x61, 0x64, 0x69, 0x75, 0x73, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x65, 0x73, 0x74, 0x52, 0x61, 0x64, 0x69, 0x75, 0x73,
+	0x12, 0x3b, 0x0a, 0x0b, 0x74, 0x65, 0x61, 0x6d, 0x5f, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x18,
+	0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c,
+	0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x54, 0x65, 0x61, 0x6d, 0x53, 0x63, 0x6f, 0x72,
+	0x65, 0x52, 0x0a, 0x74, 0x65, 0x61, 0x6d, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x73, 0x22, 0x40, 0x0a,
+	0x0b, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f,
+	0x72, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x22,
+	0x4c, 0x0a, 0x09, 0x54, 0x65, 0x61, 0x6d, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x29, 0x0a, 0x04,
+	0x74, 0x65, 0x61, 0x6d, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x74, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x54, 0x65, 0x61,
+	0x6d, 0x52, 0x04, 0x74, 0x65, 0x61, 0x6d, 0x12, 0x14, 0x0a, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x72, 0x65, 0x22, 0xf9, 0x02,
+	0x0a, 0x09, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x12, 0x44, 0x0a, 0x0d, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x6b, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f,
+	0x6f, 0x74, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4b, 0x69, 0x6c, 0x6c, 0x65,
+	0x64, 0x48, 0x00, 0x52, 0x0c, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4b, 0x69, 0x6c, 0x6c, 0x65,
+	0x64, 0x12, 0x44, 0x0a, 0x0d, 0x62, 0x6f, 0x6d, 0x62, 0x5f, 0x65, 0x78, 0x70, 0x6c, 0x6f, 0x64,
+	0x65, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69,
+	0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x42, 0x6f, 0x6d, 0x62, 0x45,
+	0x78, 0x70, 0x6c, 0x6f, 0x64, 0x65, 0x64, 0x48, 0x00, 0x52, 0x0c, 0x62, 0x6f, 0x6d, 0x62, 0x45,
+	0x78, 0x70, 0x6c, 0x6f, 0x64, 0x65, 0x64, 0x12, 0x44, 0x0a, 0x0d, 0x70, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x5f, 0x6a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1d,
+	0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72,
+	0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x48, 0x00, 0x52,
+	0x0c, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x12, 0x3e, 0x0a,
+	0x0b, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x6c, 0x65, 0x66, 0x74, 0x18, 0x04, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f,
+	0x6f, 0x74, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4c, 0x65, 0x66, 0x74, 0x48,
+	0x00, 0x52, 0x0a, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4c, 0x65, 0x66, 0x74, 0x12, 0x51, 0x0a,
+	0x12, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x75, 0x70, 0x5f, 0x63, 0x6f, 0x6c, 0x6c, 0x65, 0x63,
+	0x74, 0x65, 0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x21, 0x2e, 0x74, 0x65, 0x72, 0x6d,
+	0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x77, 0x65,
+	0x72, 0x55, 0x70, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x48, 0x00, 0x52, 0x10,
+	0x70, 0x6f, 0x77, 0x65, 0x72, 0x55, 0x70, 0x43, 0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64,
+	0x42, 0x07, 0x0a, 0x05, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x22, 0xae, 0x01, 0x0a, 0x0c, 0x50, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x4b, 0x69, 0x6c, 0x6c, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x76, 0x69,
+	0x63, 0x74, 0x69, 0x6d, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x76,
+	0x69, 0x63, 0x74, 0x69, 0x6d, 0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x6b, 0x69, 0x6c, 0x6c, 0x65,
+	0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6b, 0x69, 0x6c, 0x6c,
+	0x65, 0x72, 0x49, 0x64, 0x12, 0x2f, 0x0a, 0x05, 0x63, 0x61, 0x75, 0x73, 0x65, 0x18, 0x03, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68,
+	0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x05,
+	0x63, 0x61, 0x75, 0x73, 0x65, 0x12, 0x33, 0x0a, 0x06, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c,
+	0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x57, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x54, 0x79,
+	0x70, 0x65, 0x52, 0x06, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x22, 0x60, 0x0a, 0x0c, 0x42, 0x6f,
+	0x6d, 0x62, 0x45, 0x78, 0x70, 0x6c, 0x6f, 0x64, 0x65, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x6f, 0x77,
+	0x6e, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6f, 0x77,
+	0x6e, 0x65, 0x72, 0x49, 0x64, 0x12, 0x35, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f,
+	0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e,
+	0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x73, 0x69, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x22, 0x2b, 0x0a, 0x0c,
+	0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x4a, 0x6f, 0x69, 0x6e, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09,
+	0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x22, 0x29, 0x0a, 0x0a, 0x50, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x4c, 0x65, 0x66, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x49, 0x64, 0x22, 0x5e, 0x0a, 0x10, 0x50, 0x6f, 0x77, 0x65, 0x72, 0x55, 0x70, 0x43,
+	0x6f, 0x6c, 0x6c, 0x65, 0x63, 0x74, 0x65, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61,
+	0x79, 0x65, 0x72, 0x49, 0x64, 0x12, 0x2d, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x19, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68,
+	0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04,
+	0x74, 0x79, 0x70, 0x65, 0x22, 0x95, 0x01, 0x0a, 0x0b, 0x4a, 0x6f, 0x69, 0x6e, 0x52, 0x65, 0x71,
+	0x75, 0x65, 0x73, 0x74, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f,
+	0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70,
+	0x6c, 0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x3c, 0x0a, 0x0e, 0x70, 0x72, 0x65, 0x66, 0x65,
+	0x72, 0x72, 0x65, 0x64, 0x5f, 0x74, 0x65, 0x61, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e, 0x32,
+	0x15, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65,
+	0x72, 0x2e, 0x54, 0x65, 0x61, 0x6d, 0x52, 0x0d, 0x70, 0x72, 0x65, 0x66, 0x65, 0x72, 0x72, 0x65,
+	0x64, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x25, 0x0a, 0x0e, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x5f,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0d, 0x63,
+	0x6c, 0x69, 0x65, 0x6e, 0x74, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x22, 0x9d, 0x01, 0x0a,
+	0x0c, 0x4a, 0x6f, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x33, 0x0a,
+	0x06, 0x72, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e,
+	0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e,
+	0x4a, 0x6f, 0x69, 0x6e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x06, 0x72, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18,
+	0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x12,
+	0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x4e, 0x61,
+	0x6d, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x04, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x22, 0xa9, 0x01, 0x0a,
+	0x0b, 0x43, 0x68, 0x61, 0x74, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09,
+	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x73, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0a,
+	0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65,
+	0x78, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x12, 0x30,
+	0x0a, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x1a, 0x2e,
+	0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e,
+	0x43, 0x68, 0x61, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x52, 0x05, 0x73, 0x63, 0x6f, 0x70, 0x65,
+	0x12, 0x16, 0x0a, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08,
+	0x52, 0x06, 0x73, 0x79, 0x73, 0x74, 0x65, 0x6d, 0x22, 0xfd, 0x02, 0x0a, 0x0c, 0x52, 0x65, 0x70,
+	0x6c, 0x61, 0x79, 0x48, 0x65, 0x61, 0x64, 0x65, 0x72, 0x12, 0x25, 0x0a, 0x0e, 0x73, 0x65, 0x72,
+	0x76, 0x65, 0x72, 0x5f, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0d, 0x73, 0x65, 0x72, 0x76, 0x65, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e,
+	0x12, 0x29, 0x0a, 0x10, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x63, 0x6f, 0x6c, 0x5f, 0x76, 0x65, 0x72,
+	0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x63, 0x6f, 0x6c, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x1d, 0x0a, 0x0a, 0x73,
+	0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52,
+	0x09, 0x73, 0x74, 0x61, 0x72, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x1d, 0x0a, 0x0a, 0x73, 0x74,
+	0x61, 0x72, 0x74, 0x5f, 0x74, 0x69, 0x63, 0x6b, 0x18, 0x04, 0x20, 0x01, 0x28, 0x03, 0x52, 0x09,
+	0x73, 0x74, 0x61, 0x72, 0x74, 0x54, 0x69, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x73, 0x65, 0x65,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x73, 0x65, 0x65, 0x64, 0x12, 0x2c, 0x0a,
+	0x03, 0x6d, 0x61, 0x70, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x70,
+	0x6c, 0x61, 0x79, 0x4d, 0x61, 0x70, 0x52, 0x03, 0x6d, 0x61, 0x70, 0x12, 0x36, 0x0a, 0x07, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x07, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x74,
+	0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x50,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x73, 0x12, 0x30, 0x0a, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x18, 0x08, 0x20, 0x03,
+	0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f,
+	0x6f, 0x74, 0x65, 0x72, 0x2e, 0x49, 0x74, 0x65, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05,
+	0x69, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x31, 0x0a, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x09,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73,
+	0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74,
+	0x65, 0x52, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x22, 0xaf, 0x01, 0x0a, 0x09, 0x52, 0x65, 0x70,
+	0x6c, 0x61, 0x79, 0x4d, 0x61, 0x70, 0x12, 0x14, 0x0a, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x77, 0x69, 0x64, 0x74, 0x68, 0x12, 0x16, 0x0a, 0x06,
+	0x68, 0x65, 0x69, 0x67, 0x68, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x68, 0x65,
+	0x69, 0x67, 0x68, 0x74, 0x12, 0x27, 0x0a, 0x0f, 0x70, 0x69, 0x6c, 0x6c, 0x61, 0x72, 0x5f, 0x69,
+	0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x70,
+	0x69, 0x6c, 0x6c, 0x61, 0x72, 0x49, 0x6e, 0x74, 0x65, 0x72, 0x76, 0x61, 0x6c, 0x12, 0x23, 0x0a,
+	0x0d, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x5f, 0x64, 0x65, 0x6e, 0x73, 0x69, 0x74, 0x79, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x01, 0x52, 0x0c, 0x62, 0x6c, 0x6f, 0x63, 0x6b, 0x44, 0x65, 0x6e, 0x73, 0x69,
+	0x74, 0x79, 0x12, 0x26, 0x0a, 0x0f, 0x70, 0x6f, 0x77, 0x65, 0x72, 0x5f, 0x75, 0x70, 0x5f, 0x63,
+	0x68, 0x61, 0x6e, 0x63, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x01, 0x52, 0x0d, 0x70, 0x6f, 0x77,
+	0x65, 0x72, 0x55, 0x70, 0x43, 0x68, 0x61, 0x6e, 0x63, 0x65, 0x22, 0xa7, 0x02, 0x0a, 0x0a, 0x52,
+	0x65, 0x70, 0x6c, 0x61, 0x79, 0x54, 0x69, 0x63, 0x6b, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x69, 0x63,
+	0x6b, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x04, 0x74, 0x69, 0x63, 0x6b, 0x12, 0x34, 0x0a,
+	0x06, 0x69, 0x6e, 0x70, 0x75, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e,
+	0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e,
+	0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x49, 0x6e, 0x70, 0x75, 0x74, 0x52, 0x06, 0x69, 0x6e, 0x70,
+	0x75, 0x74, 0x73, 0x12, 0x36, 0x0a, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1c, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73,
+	0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x53, 0x74, 0x61,
+	0x74, 0x65, 0x52, 0x07, 0x70, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x30, 0x0a, 0x05, 0x69,
+	0x74, 0x65, 0x6d, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x74, 0x65, 0x72,
+	0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x49, 0x74, 0x65,
+	0x6d, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x69, 0x74, 0x65, 0x6d, 0x73, 0x12, 0x31, 0x0a,
+	0x05, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1b, 0x2e, 0x74,
+	0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x53, 0x74, 0x61, 0x74, 0x65, 0x52, 0x05, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x12, 0x32, 0x0a, 0x06, 0x65, 0x76, 0x65, 0x6e, 0x74, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x1a, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74,
+	0x65, 0x72, 0x2e, 0x47, 0x61, 0x6d, 0x65, 0x45, 0x76, 0x65, 0x6e, 0x74, 0x52, 0x06, 0x65, 0x76,
+	0x65, 0x6e, 0x74, 0x73, 0x22, 0xd4, 0x02, 0x0a, 0x0b, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x49,
+	0x6e, 0x70, 0x75, 0x74, 0x12, 0x34, 0x0a, 0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x20, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f,
+	0x6f, 0x74, 0x65, 0x72, 0x2e, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x49, 0x6e, 0x70, 0x75, 0x74,
+	0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x74, 0x79, 0x70, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x70, 0x6c,
+	0x61, 0x79, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x70,
+	0x6c, 0x61, 0x79, 0x65, 0x72, 0x49, 0x64, 0x12, 0x35, 0x0a, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74,
+	0x69, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x74, 0x65, 0x72, 0x6d,
+	0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x50, 0x6f, 0x73, 0x69,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x70, 0x6f, 0x73, 0x69, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x38,
+	0x0a, 0x09, 0x64, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x1a, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68, 0x6f, 0x6f,
+	0x74, 0x65, 0x72, 0x2e, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x64,
+	0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x29, 0x0a, 0x04, 0x74, 0x65, 0x61, 0x6d,
+	0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61,
+	0x6c, 0x73, 0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x54, 0x65, 0x61, 0x6d, 0x52, 0x04, 0x74,
+	0x65, 0x61, 0x6d, 0x12, 0x33, 0x0a, 0x06, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x18, 0x06, 0x20,
+	0x01, 0x28, 0x0e, 0x32, 0x1b, 0x2e, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x73, 0x68,
+	0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2e, 0x57, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65,
+	0x52, 0x06, 0x77, 0x65, 0x61, 0x70, 0x6f, 0x6e, 0x12, 0x21, 0x0a, 0x0c, 0x64, 0x69, 0x73, 0x70,
+	0x6c, 0x61, 0x79, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b,
+	0x64, 0x69, 0x73, 0x70, 0x6c, 0x61, 0x79, 0x4e, 0x61, 0x6d, 0x65, 0x2a, 0x3e, 0x0a, 0x0a, 0x57,
+	0x65, 0x61, 0x70, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x50, 0x49, 0x53,
+	0x54, 0x4f, 0x4c, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x52, 0x49, 0x46, 0x4c, 0x45, 0x10, 0x01,
+	0x12, 0x0b, 0x0a, 0x07, 0x53, 0x48, 0x4f, 0x54, 0x47, 0x55, 0x4e, 0x10, 0x02, 0x12, 0x0c, 0x0a,
+	0x08, 0x50, 0x49, 0x45, 0x52, 0x43, 0x49, 0x4e, 0x47, 0x10, 0x03, 0x2a, 0x25, 0x0a, 0x0a, 0x49,
+	0x74, 0x65, 0x6d, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x43, 0x54,
+	0x49, 0x56, 0x45, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x52, 0x45, 0x4d, 0x4f, 0x56, 0x45, 0x44,
+	0x10, 0x01, 0x2a, 0x32, 0x0a, 0x09, 0x44, 0x69, 0x72, 0x65, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x06, 0x0a, 0x02, 0x55, 0x50, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x4f, 0x57, 0x4e, 0x10,
+	0x01, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x45, 0x46, 0x54, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x52,
+	0x49, 0x47, 0x48, 0x54, 0x10, 0x03, 0x2a, 0x2f, 0x0a, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73,
+	0x12, 0x09, 0x0a, 0x05, 0x41, 0x4c, 0x49, 0x56, 0x45, 0x10, 0x00, 0x12, 0x08, 0x0a, 0x04, 0x44,
+	0x45, 0x41, 0x44, 0x10, 0x02, 0x12, 0x10, 0x0a, 0x0c, 0x44, 0x49, 0x53, 0x43, 0x4f, 0x4e, 0x4e,
+	0x45, 0x43, 0x54, 0x45, 0x44, 0x10, 0x01, 0x2a, 0x97, 0x01, 0x0a, 0x08, 0x49, 0x74, 0x65, 0x6d,
+	0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x42, 0x55, 0x4c, 0x4c, 0x45, 0x54, 0x10, 0x00,
+	0x12, 0x08, 0x0a, 0x04, 0x42, 0x4f, 0x4d, 0x42, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09, 0x42, 0x4f,
+	0x4d, 0x42, 0x5f, 0x46, 0x49, 0x52, 0x45, 0x10, 0x02, 0x12, 0x14, 0x0a, 0x10, 0x42, 0x4f, 0x4d,
+	0x42, 0x5f, 0x43, 0x41, 0x50, 0x41, 0x43, 0x49, 0x54, 0x59, 0x5f, 0x55, 0x50, 0x10, 0x03, 0x12,
+	0x11, 0x0a, 0x0d, 0x42, 0x4f, 0x4d, 0x42, 0x5f, 0x52, 0x41, 0x4e, 0x47, 0x45, 0x5f, 0x55, 0x50,
+	0x10, 0x04, 0x12, 0x0c, 0x0a, 0x08, 0x53, 0x50, 0x45, 0x45, 0x44, 0x5f, 0x55, 0x50, 0x10, 0x05,
+	0x12, 0x0a, 0x0a, 0x06, 0x53, 0x48, 0x49, 0x45, 0x4c, 0x44, 0x10, 0x06, 0x12, 0x0e, 0x0a, 0x0a,
+	0x52, 0x41, 0x50, 0x49, 0x44, 0x5f, 0x46, 0x49, 0x52, 0x45, 0x10, 0x07, 0x12, 0x08, 0x0a, 0x04,
+	0x57, 0x41, 0x4c, 0x4c, 0x10, 0x08, 0x12, 0x09, 0x0a, 0x05, 0x42, 0x4c, 0x4f, 0x43, 0x4b, 0x10,
+	0x09, 0x2a, 0x5e, 0x0a, 0x0a, 0x41, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x10, 0x0a, 0x0c, 0x53, 0x48, 0x4f, 0x4f, 0x54, 0x5f, 0x42, 0x55, 0x4c, 0x4c, 0x45, 0x54, 0x10,
+	0x00, 0x12, 0x0e, 0x0a, 0x0a, 0x50, 0x4c, 0x41, 0x43, 0x45, 0x5f, 0x42, 0x4f, 0x4d, 0x42, 0x10,
+	0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x53, 0x57, 0x49, 0x54, 0x43, 0x48, 0x5f, 0x54, 0x45, 0x41, 0x4d,
+	0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x45, 0x4c, 0x4f, 0x41, 0x44, 0x10, 0x03, 0x12, 0x11,
+	0x0a, 0x0d, 0x53, 0x45, 0x4c, 0x45, 0x43, 0x54, 0x5f, 0x57, 0x45, 0x41, 0x50, 0x4f, 0x4e, 0x10,
+	0x04, 0x2a, 0x43, 0x0a, 0x0a, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x50, 0x68, 0x61, 0x73, 0x65, 0x12,
+	0x0b, 0x0a, 0x07, 0x57, 0x41, 0x49, 0x54, 0x49, 0x4e, 0x47, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09,
+	0x43, 0x4f, 0x55, 0x4e, 0x54, 0x44, 0x4f, 0x57, 0x4e, 0x10, 0x01, 0x12, 0x0b, 0x0a, 0x07, 0x52,
+	0x55, 0x4e, 0x4e, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x46, 0x49, 0x4e, 0x49,
+	0x53, 0x48, 0x45, 0x44, 0x10, 0x03, 0x2a, 0x46, 0x0a, 0x08, 0x47, 0x61, 0x6d, 0x65, 0x4d, 0x6f,
+	0x64, 0x65, 0x12, 0x0e, 0x0a, 0x0a, 0x44, 0x45, 0x41, 0x54, 0x48, 0x4d, 0x41, 0x54, 0x43, 0x48,
+	0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x4c, 0x41, 0x53, 0x54, 0x5f, 0x4d, 0x41, 0x4e, 0x5f, 0x53,
+	0x54, 0x41, 0x4e, 0x44, 0x49, 0x4e, 0x47, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x54, 0x45, 0x41,
+	0x4d, 0x5f, 0x44, 0x45, 0x41, 0x54, 0x48, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x02, 0x2a, 0x26,
+	0x0a, 0x04, 0x54, 0x65, 0x61, 0x6d, 0x12, 0x0b, 0x0a, 0x07, 0x4e, 0x4f, 0x5f, 0x54, 0x45, 0x41,
+	0x4d, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x52, 0x45, 0x44, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04,
+	0x42, 0x4c, 0x55, 0x45, 0x10, 0x02, 0x2a, 0x66, 0x0a, 0x0a, 0x4a, 0x6f, 0x69, 0x6e, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x12, 0x11, 0x0a, 0x0d, 0x4a, 0x4f, 0x49, 0x4e, 0x5f, 0x41, 0x43, 0x43,
+	0x45, 0x50, 0x54, 0x45, 0x44, 0x10, 0x00, 0x12, 0x15, 0x0a, 0x11, 0x4a, 0x4f, 0x49, 0x4e, 0x5f,
+	0x4e, 0x41, 0x4d, 0x45, 0x5f, 0x49, 0x4e, 0x56, 0x41, 0x4c, 0x49, 0x44, 0x10, 0x01, 0x12, 0x13,
+	0x0a, 0x0f, 0x4a, 0x4f, 0x49, 0x4e, 0x5f, 0x4e, 0x41, 0x4d, 0x45, 0x5f, 0x54, 0x41, 0x4b, 0x45,
+	0x4e, 0x10, 0x02, 0x12, 0x19, 0x0a, 0x15, 0x4a, 0x4f, 0x49, 0x4e, 0x5f, 0x56, 0x45, 0x52, 0x53,
+	0x49, 0x4f, 0x4e, 0x5f, 0x4d, 0x49, 0x53, 0x4d, 0x41, 0x54, 0x43, 0x48, 0x10, 0x03, 0x2a, 0x34,
+	0x0a, 0x09, 0x43, 0x68, 0x61, 0x74, 0x53, 0x63, 0x6f, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x0e, 0x43,
+	0x48, 0x41, 0x54, 0x5f, 0x53, 0x43, 0x4f, 0x50, 0x45, 0x5f, 0x41, 0x4c, 0x4c, 0x10, 0x00, 0x12,
+	0x13, 0x0a, 0x0f, 0x43, 0x48, 0x41, 0x54, 0x5f, 0x53, 0x43, 0x4f, 0x50, 0x45, 0x5f, 0x54, 0x45,
+	0x41, 0x4d, 0x10, 0x01, 0x2a, 0x86, 0x02, 0x0a, 0x0f, 0x52, 0x65, 0x70, 0x6c, 0x61, 0x79, 0x49,
+	0x6e, 0x70, 0x75, 0x74, 0x54, 0x79, 0x70, 0x65, 0x12, 0x15, 0x0a, 0x11, 0x52, 0x45, 0x50, 0x4c,
+	0x41, 0x59, 0x5f, 0x49, 0x4e, 0x50, 0x55, 0x54, 0x5f, 0x4a, 0x4f, 0x49, 0x4e, 0x10, 0x00, 0x12,
+	0x16, 0x0a, 0x12, 0x52, 0x45, 0x50, 0x4c, 0x41, 0x59, 0x5f, 0x49, 0x4e, 0x50, 0x55, 0x54, 0x5f,
+	0x4c, 0x45, 0x41, 0x56, 0x45, 0x10, 0x01, 0x12, 0x15, 0x0a, 0x11, 0x52, 0x45, 0x50, 0x4c, 0x41,
+	0x59, 0x5f, 0x49, 0x4e, 0x50, 0x55, 0x54, 0x5f, 0x4d, 0x4f, 0x56, 0x45, 0x10, 0x02, 0x12, 0x16,
+	0x0a, 0x12, 0x52, 0x45, 0x50, 0x4c, 0x41, 0x59, 0x5f, 0x49, 0x4e, 0x50, 0x55, 0x54, 0x5f, 0x53,
+	0x48, 0x4f, 0x4f, 0x54, 0x10, 0x03, 0x12, 0x1b, 0x0a, 0x17, 0x52, 0x45, 0x50, 0x4c, 0x41, 0x59,
+	0x5f, 0x49, 0x4e, 0x50, 0x55, 0x54, 0x5f, 0x50, 0x4c, 0x41, 0x43, 0x45, 0x5f, 0x42, 0x4f, 0x4d,
+	0x42, 0x10, 0x04, 0x12, 0x1e, 0x0a, 0x1a, 0x52, 0x45, 0x50, 0x4c, 0x41, 0x59, 0x5f, 0x49, 0x4e,
+	0x50, 0x55, 0x54, 0x5f, 0x53, 0x45, 0x4c, 0x45, 0x43, 0x54, 0x5f, 0x57, 0x45, 0x41, 0x50, 0x4f,
+	0x4e, 0x10, 0x05, 0x12, 0x17, 0x0a, 0x13, 0x52, 0x45, 0x50, 0x4c, 0x41, 0x59, 0x5f, 0x49, 0x4e,
+	0x50, 0x55, 0x54, 0x5f, 0x52, 0x45, 0x4c, 0x4f, 0x41, 0x44, 0x10, 0x06, 0x12, 0x1c, 0x0a, 0x18,
+	0x52, 0x45, 0x50, 0x4c, 0x41, 0x59, 0x5f, 0x49, 0x4e, 0x50, 0x55, 0x54, 0x5f, 0x53, 0x57, 0x49,
+	0x54, 0x43, 0x48, 0x5f, 0x54, 0x45, 0x41, 0x4d, 0x10, 0x07, 0x12, 0x21, 0x0a, 0x1d, 0x52, 0x45,
+	0x50, 0x4c, 0x41, 0x59, 0x5f, 0x49, 0x4e, 0x50, 0x55, 0x54, 0x5f, 0x53, 0x45, 0x54, 0x5f, 0x44,
+	0x49, 0x53, 0x50, 0x4c, 0x41, 0x59, 0x5f, 0x4e, 0x41, 0x4d, 0x45, 0x10, 0x08, 0x42, 0x2e, 0x5a,
+	0x2c, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x73, 0x68, 0x69, 0x62,
+	0x61, 0x79, 0x75, 0x33, 0x36, 0x2f, 0x74, 0x65, 0x72, 0x6d, 0x69, 0x6e, 0x61, 0x6c, 0x2d, 0x73,
+	0x68, 0x6f, 0x6f, 0x74, 0x65, 0x72, 0x2f, 0x73, 0x68, 0x61, 0x72, 0x65, 0x64, 0x62, 0x06, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -578,32 +2738,96 @@ func file_game_proto_rawDescGZIP() []byte {
 	return file_game_proto_rawDescData
 }
 
-var file_game_proto_enumTypes = make([]protoimpl.EnumInfo, 5)
-var file_game_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_game_proto_enumTypes = make([]protoimpl.EnumInfo, 12)
+var file_game_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_game_proto_goTypes = []any{
-	(ItemStatus)(0),             // 0: terminalshooter.ItemStatus
-	(Direction)(0),              // 1: terminalshooter.Direction
-	(Status)(0),                 // 2: terminalshooter.Status
-	(ItemType)(0),               // 3: terminalshooter.ItemType
-	(ActionType)(0),             // 4: terminalshooter.ActionType
-	(*Position)(nil),            // 5: terminalshooter.Position
-	(*PlayerState)(nil),         // 6: terminalshooter.PlayerState
-	(*ItemState)(nil),           // 7: terminalshooter.ItemState
-	(*PlayerActionRequest)(nil), // 8: terminalshooter.PlayerActionRequest
+	(WeaponType)(0),             // 0: terminalshooter.WeaponType
+	(ItemStatus)(0),             // 1: terminalshooter.ItemStatus
+	(Direction)(0),              // 2: terminalshooter.Direction
+	(Status)(0),                 // 3: terminalshooter.Status
+	(ItemType)(0),               // 4: terminalshooter.ItemType
+	(ActionType)(0),             // 5: terminalshooter.ActionType
+	(MatchPhase)(0),             // 6: terminalshooter.MatchPhase
+	(GameMode)(0),               // 7: terminalshooter.GameMode
+	(Team)(0),                   // 8: terminalshooter.Team
+	(JoinResult)(0),             // 9: terminalshooter.JoinResult
+	(ChatScope)(0),              // 10: terminalshooter.ChatScope
+	(ReplayInputType)(0),        // 11: terminalshooter.ReplayInputType
+	(*Position)(nil),            // 12: terminalshooter.Position
+	(*PlayerState)(nil),         // 13: terminalshooter.PlayerState
+	(*WeaponState)(nil),         // 14: terminalshooter.WeaponState
+	(*PowerUpEffect)(nil),       // 15: terminalshooter.PowerUpEffect
+	(*ItemState)(nil),           // 16: terminalshooter.ItemState
+	(*PlayerActionRequest)(nil), // 17: terminalshooter.PlayerActionRequest
+	(*MatchState)(nil),          // 18: terminalshooter.MatchState
+	(*PlayerScore)(nil),         // 19: terminalshooter.PlayerScore
+	(*TeamScore)(nil),           // 20: terminalshooter.TeamScore
+	(*GameEvent)(nil),           // 21: terminalshooter.GameEvent
+	(*PlayerKilled)(nil),        // 22: terminalshooter.PlayerKilled
+	(*BombExploded)(nil),        // 23: terminalshooter.BombExploded
+	(*PlayerJoined)(nil),        // 24: terminalshooter.PlayerJoined
+	(*PlayerLeft)(nil),          // 25: terminalshooter.PlayerLeft
+	(*PowerUpCollected)(nil),    // 26: terminalshooter.PowerUpCollected
+	(*JoinRequest)(nil),         // 27: terminalshooter.JoinRequest
+	(*JoinResponse)(nil),        // 28: terminalshooter.JoinResponse
+	(*ChatMessage)(nil),         // 29: terminalshooter.ChatMessage
+	(*ReplayHeader)(nil),        // 30: terminalshooter.ReplayHeader
+	(*ReplayMap)(nil),           // 31: terminalshooter.ReplayMap
+	(*ReplayTick)(nil),          // 32: terminalshooter.ReplayTick
+	(*ReplayInput)(nil),         // 33: terminalshooter.ReplayInput
 }
 var file_game_proto_depIdxs = []int32{
-	5, // 0: terminalshooter.PlayerState.position:type_name -> terminalshooter.Position
-	1, // 1: terminalshooter.PlayerState.direction:type_name -> terminalshooter.Direction
-	2, // 2: terminalshooter.PlayerState.status:type_name -> terminalshooter.Status
-	3, // 3: terminalshooter.ItemState.type:type_name -> terminalshooter.ItemType
-	5, // 4: terminalshooter.ItemState.position:type_name -> terminalshooter.Position
-	0, // 5: terminalshooter.ItemState.status:type_name -> terminalshooter.ItemStatus
-	4, // 6: terminalshooter.PlayerActionRequest.type:type_name -> terminalshooter.ActionType
-	7, // [7:7] is the sub-list for method output_type
-	7, // [7:7] is the sub-list for method input_type
-	7, // [7:7] is the sub-list for extension type_name
-	7, // [7:7] is the sub-list for extension extendee
-	0, // [0:7] is the sub-list for field type_name
+	12, // 0: terminalshooter.PlayerState.position:type_name -> terminalshooter.Position
+	2,  // 1: terminalshooter.PlayerState.direction:type_name -> terminalshooter.Direction
+	3,  // 2: terminalshooter.PlayerState.status:type_name -> terminalshooter.Status
+	8,  // 3: terminalshooter.PlayerState.team:type_name -> terminalshooter.Team
+	15, // 4: terminalshooter.PlayerState.effects:type_name -> terminalshooter.PowerUpEffect
+	14, // 5: terminalshooter.PlayerState.weapon:type_name -> terminalshooter.WeaponState
+	0,  // 6: terminalshooter.WeaponState.type:type_name -> terminalshooter.WeaponType
+	4,  // 7: terminalshooter.PowerUpEffect.type:type_name -> terminalshooter.ItemType
+	4,  // 8: terminalshooter.ItemState.type:type_name -> terminalshooter.ItemType
+	12, // 9: terminalshooter.ItemState.position:type_name -> terminalshooter.Position
+	1,  // 10: terminalshooter.ItemState.status:type_name -> terminalshooter.ItemStatus
+	0,  // 11: terminalshooter.ItemState.weapon:type_name -> terminalshooter.WeaponType
+	5,  // 12: terminalshooter.PlayerActionRequest.type:type_name -> terminalshooter.ActionType
+	8,  // 13: terminalshooter.PlayerActionRequest.team:type_name -> terminalshooter.Team
+	0,  // 14: terminalshooter.PlayerActionRequest.weapon:type_name -> terminalshooter.WeaponType
+	6,  // 15: terminalshooter.MatchState.phase:type_name -> terminalshooter.MatchPhase
+	19, // 16: terminalshooter.MatchState.scores:type_name -> terminalshooter.PlayerScore
+	7,  // 17: terminalshooter.MatchState.mode:type_name -> terminalshooter.GameMode
+	20, // 18: terminalshooter.MatchState.team_scores:type_name -> terminalshooter.TeamScore
+	8,  // 19: terminalshooter.TeamScore.team:type_name -> terminalshooter.Team
+	22, // 20: terminalshooter.GameEvent.player_killed:type_name -> terminalshooter.PlayerKilled
+	23, // 21: terminalshooter.GameEvent.bomb_exploded:type_name -> terminalshooter.BombExploded
+	24, // 22: terminalshooter.GameEvent.player_joined:type_name -> terminalshooter.PlayerJoined
+	25, // 23: terminalshooter.GameEvent.player_left:type_name -> terminalshooter.PlayerLeft
+	26, // 24: terminalshooter.GameEvent.power_up_collected:type_name -> terminalshooter.PowerUpCollected
+	4,  // 25: terminalshooter.PlayerKilled.cause:type_name -> terminalshooter.ItemType
+	0,  // 26: terminalshooter.PlayerKilled.weapon:type_name -> terminalshooter.WeaponType
+	12, // 27: terminalshooter.BombExploded.position:type_name -> terminalshooter.Position
+	4,  // 28: terminalshooter.PowerUpCollected.type:type_name -> terminalshooter.ItemType
+	8,  // 29: terminalshooter.JoinRequest.preferred_team:type_name -> terminalshooter.Team
+	9,  // 30: terminalshooter.JoinResponse.result:type_name -> terminalshooter.JoinResult
+	10, // 31: terminalshooter.ChatMessage.scope:type_name -> terminalshooter.ChatScope
+	31, // 32: terminalshooter.ReplayHeader.map:type_name -> terminalshooter.ReplayMap
+	13, // 33: terminalshooter.ReplayHeader.players:type_name -> terminalshooter.PlayerState
+	16, // 34: terminalshooter.ReplayHeader.items:type_name -> terminalshooter.ItemState
+	18, // 35: terminalshooter.ReplayHeader.match:type_name -> terminalshooter.MatchState
+	33, // 36: terminalshooter.ReplayTick.inputs:type_name -> terminalshooter.ReplayInput
+	13, // 37: terminalshooter.ReplayTick.players:type_name -> terminalshooter.PlayerState
+	16, // 38: terminalshooter.ReplayTick.items:type_name -> terminalshooter.ItemState
+	18, // 39: terminalshooter.ReplayTick.match:type_name -> terminalshooter.MatchState
+	21, // 40: terminalshooter.ReplayTick.events:type_name -> terminalshooter.GameEvent
+	11, // 41: terminalshooter.ReplayInput.type:type_name -> terminalshooter.ReplayInputType
+	12, // 42: terminalshooter.ReplayInput.position:type_name -> terminalshooter.Position
+	2,  // 43: terminalshooter.ReplayInput.direction:type_name -> terminalshooter.Direction
+	8,  // 44: terminalshooter.ReplayInput.team:type_name -> terminalshooter.Team
+	0,  // 45: terminalshooter.ReplayInput.weapon:type_name -> terminalshooter.WeaponType
+	46, // [46:46] is the sub-list for method output_type
+	46, // [46:46] is the sub-list for method input_type
+	46, // [46:46] is the sub-list for extension type_name
+	46, // [46:46] is the sub-list for extension extendee
+	0,  // [0:46] is the sub-list for field type_name
 }
 
 func init() { file_game_proto_init() }
@@ -611,13 +2835,20 @@ func file_game_proto_init() {
 	if File_game_proto != nil {
 		return
 	}
+	file_game_proto_msgTypes[9].OneofWrappers = []any{
+		(*GameEvent_PlayerKilled)(nil),
+		(*GameEvent_BombExploded)(nil),
+		(*GameEvent_PlayerJoined)(nil),
+		(*GameEvent_PlayerLeft)(nil),
+		(*GameEvent_PowerUpCollected)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_game_proto_rawDesc,
-			NumEnums:      5,
-			NumMessages:   4,
+			NumEnums:      12,
+			NumMessages:   22,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,55 @@
+package shared
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+)
+
+// リプレイファイルの拡張子
+const ReplayFileExt = ".replay"
+
+// WriteReplayMessage 長さ(uvarint)を前に付けてmessageを書き込み、書き込んだバイト数を返す
+func WriteReplayMessage(w io.Writer, message proto.Message) (int, error) {
+	n, err := protodelim.MarshalTo(w, message)
+	if err != nil {
+		return n, errors.Wrap(err, "failed to write replay message")
+	}
+	return n, nil
+}
+
+// ReplayReader リプレイファイルを先頭から読む
+type ReplayReader struct {
+	reader *bufio.Reader
+	header *ReplayHeader
+}
+
+// NewReplayReader リプレイファイルのヘッダーを読み込む
+func NewReplayReader(r io.Reader) (*ReplayReader, error) {
+	reader := bufio.NewReader(r)
+	header := &ReplayHeader{}
+	if err := protodelim.UnmarshalFrom(reader, header); err != nil {
+		return nil, errors.Wrap(err, "failed to read replay header")
+	}
+	return &ReplayReader{reader: reader, header: header}, nil
+}
+
+func (r *ReplayReader) Header() *ReplayHeader {
+	return r.header
+}
+
+// Next 次のtickを読み込む。最後まで読んだらio.EOFを返す
+// 途中で書き込みが止まったファイルの末尾はio.ErrUnexpectedEOFになる
+func (r *ReplayReader) Next() (*ReplayTick, error) {
+	tick := &ReplayTick{}
+	if err := protodelim.UnmarshalFrom(r.reader, tick); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, errors.Wrap(err, "failed to read replay tick")
+	}
+	return tick, nil
+}
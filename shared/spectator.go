@@ -0,0 +1,12 @@
+package shared
+
+import "strings"
+
+// SpectatorClientIDPrefix 観戦用のクライアントIDの接頭辞
+// このクライアントIDで接続すると、プレイヤーとして参加せずに全ての状態を受け取る
+const SpectatorClientIDPrefix = "spectator-"
+
+// IsSpectatorClientID 観戦用のクライアントIDか
+func IsSpectatorClientID(clientID string) bool {
+	return strings.HasPrefix(clientID, SpectatorClientIDPrefix)
+}
@@ -0,0 +1,5 @@
+package shared
+
+// ProtocolVersion クライアントとサーバーの通信の互換性を表すバージョン
+// 互換性のない変更をした時に上げる
+const ProtocolVersion = "1"